@@ -0,0 +1,87 @@
+//go:generate mockgen -destination mock_accountdata/mock_accountdata.go github.com/anyproto/anytype-push-server/accountdata AccountData
+
+package accountdata
+
+import (
+	"context"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/anytype-push-server/db"
+	"github.com/anyproto/anytype-push-server/repo/accountrepo"
+	"github.com/anyproto/anytype-push-server/repo/reportrepo"
+	"github.com/anyproto/anytype-push-server/repo/spacerepo"
+	"github.com/anyproto/anytype-push-server/repo/tokenrepo"
+)
+
+const CName = "push.accountdata"
+
+var log = logger.NewNamed(CName)
+
+func New() AccountData {
+	return new(accountData)
+}
+
+// AccountData operates on everything the push server stores about an account
+type AccountData interface {
+	// Delete removes tokens, topic subscriptions and reports of the account, spaces managed by other admins or members are kept
+	// without the account; it's safe to call it several times
+	Delete(ctx context.Context, accountId string) (err error)
+	// Export collects everything stored about the account, token values are masked unless revealTokens is set
+	Export(ctx context.Context, accountId string, revealTokens bool) (export *Export, err error)
+	app.Component
+}
+
+type accountData struct {
+	db          db.Database
+	tokenRepo   tokenrepo.TokenRepo
+	accountRepo accountrepo.AccountRepo
+	spaceRepo   spacerepo.SpaceRepo
+	reportRepo  reportrepo.ReportRepo
+}
+
+func (d *accountData) Init(a *app.App) (err error) {
+	d.db = a.MustComponent(db.CName).(db.Database)
+	d.tokenRepo = a.MustComponent(tokenrepo.CName).(tokenrepo.TokenRepo)
+	d.accountRepo = a.MustComponent(accountrepo.CName).(accountrepo.AccountRepo)
+	d.spaceRepo = a.MustComponent(spacerepo.CName).(spacerepo.SpaceRepo)
+	d.reportRepo = a.MustComponent(reportrepo.CName).(reportrepo.ReportRepo)
+	return
+}
+
+func (d *accountData) Name() (name string) {
+	return CName
+}
+
+func (d *accountData) Delete(ctx context.Context, accountId string) (err error) {
+	err = d.db.Tx(ctx, func(txCtx mongo.SessionContext) error {
+		if err := d.tokenRepo.RemoveAccountTokens(txCtx, accountId); err != nil {
+			return err
+		}
+		if err := d.accountRepo.RemoveAccount(txCtx, accountId); err != nil {
+			return err
+		}
+		if err := d.reportRepo.RemoveByReporterId(txCtx, accountId); err != nil {
+			return err
+		}
+		removedIds, err := d.spaceRepo.RemoveAccount(txCtx, accountId)
+		if err != nil {
+			return err
+		}
+		// the account was the last admin or member of these spaces
+		for _, spaceId := range removedIds {
+			if err := d.accountRepo.RemoveSpaceTopics(txCtx, spaceId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	log.Info("account data deleted", zap.String("accountId", accountId))
+	return
+}
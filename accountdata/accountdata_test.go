@@ -0,0 +1,192 @@
+package accountdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/db"
+	"github.com/anyproto/anytype-push-server/domain"
+	"github.com/anyproto/anytype-push-server/repo/accountrepo"
+	"github.com/anyproto/anytype-push-server/repo/reportrepo"
+	"github.com/anyproto/anytype-push-server/repo/spacerepo"
+	"github.com/anyproto/anytype-push-server/repo/tokenrepo"
+)
+
+var ctx = context.Background()
+
+func TestAccountData_Delete(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.tokenRepo.AddToken(ctx, domain.Token{Id: "t1", AccountId: "a1", PeerId: "p1"}))
+	require.NoError(t, fx.tokenRepo.AddToken(ctx, domain.Token{Id: "t2", AccountId: "a2", PeerId: "p2"}))
+	require.NoError(t, fx.accountRepo.SetAccountTopics(ctx, "a1", []domain.Topic{"s1/t1", "s3/t1"}))
+	require.NoError(t, fx.accountRepo.SetAccountTopics(ctx, "a2", []domain.Topic{"s1/t1", "s3/t1"}))
+	// s1 is managed by a1 only, s3 has another admin, s4 is a 1-1 space of a1 and a2
+	require.NoError(t, fx.spaceRepo.Create(ctx, domain.Space{Id: "s1", Author: "a1"}))
+	require.NoError(t, fx.spaceRepo.Create(ctx, domain.Space{Id: "s2", Author: "a2"}))
+	require.NoError(t, fx.spaceRepo.Create(ctx, domain.Space{Id: "s3", Author: "a1"}))
+	require.NoError(t, fx.spaceRepo.AddAdmin(ctx, domain.Space{Id: "s3", Author: "a1"}, "a2"))
+	require.NoError(t, fx.spaceRepo.AddOneToOneMember(ctx, "s4", "a1"))
+	require.NoError(t, fx.spaceRepo.AddOneToOneMember(ctx, "s4", "a2"))
+	require.NoError(t, fx.reportRepo.Add(ctx, domain.Report{ReporterId: "a1", GroupId: "g1"}))
+	require.NoError(t, fx.reportRepo.Add(ctx, domain.Report{ReporterId: "a2", GroupId: "g1"}))
+
+	require.NoError(t, fx.Delete(ctx, "a1"))
+	// idempotent
+	require.NoError(t, fx.Delete(ctx, "a1"))
+
+	tokens, err := fx.tokenRepo.GetTokensByAccountId(ctx, "a1")
+	require.NoError(t, err)
+	assert.Len(t, tokens, 0)
+	tokens, err = fx.tokenRepo.GetTokensByAccountId(ctx, "a2")
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	// topics of the removed space are removed from other accounts too
+	accountIds, err := fx.accountRepo.GetAccountIdsByTopics(ctx, []domain.Topic{"s1/t1"})
+	require.NoError(t, err)
+	assert.Empty(t, accountIds)
+	accountIds, err = fx.accountRepo.GetAccountIdsByTopics(ctx, []domain.Topic{"s3/t1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a2"}, accountIds)
+
+	reports, err := fx.reportRepo.GetByGroupId(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "a2", reports[0].ReporterId)
+
+	existed, err := fx.spaceRepo.ExistedSpaces(ctx, []string{"s1", "s2", "s3", "s4"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"s2", "s3", "s4"}, existed)
+	for _, spaceId := range []string{"s3", "s4"} {
+		space, err := fx.spaceRepo.Get(ctx, spaceId)
+		require.NoError(t, err)
+		assert.Equal(t, "a2", space.Author)
+	}
+	require.ErrorIs(t, fx.spaceRepo.Remove(ctx, domain.Space{Id: "s1", Author: "a1"}), spacerepo.ErrSpaceNotFound)
+}
+
+func TestAccountData_Export(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.tokenRepo.AddToken(ctx, domain.Token{
+		Id:         "token-value-123",
+		AccountId:  "a1",
+		PeerId:     "p1",
+		Platform:   domain.PlatformIOS,
+		AppVersion: "1.0.0",
+	}))
+	require.NoError(t, fx.accountRepo.SetAccountTopics(ctx, "a1", []domain.Topic{"s1/t1", "s2/t2"}))
+	require.NoError(t, fx.spaceRepo.Create(ctx, domain.Space{Id: "s1", Author: "a1"}))
+	require.NoError(t, fx.spaceRepo.Create(ctx, domain.Space{Id: "s2", Author: "a2"}))
+	require.NoError(t, fx.spaceRepo.AddAdmin(ctx, domain.Space{Id: "s2", Author: "a2"}, "a1"))
+	require.NoError(t, fx.spaceRepo.AddOneToOneMember(ctx, "s3", "a2"))
+	require.NoError(t, fx.spaceRepo.AddOneToOneMember(ctx, "s3", "a1"))
+	require.NoError(t, fx.accountRepo.BlockAccount(ctx, "a1", "a3"))
+	require.NoError(t, fx.reportRepo.Add(ctx, domain.Report{ReporterId: "a1", GroupId: "g1", SenderId: "a3", Reason: "spam"}))
+
+	export, err := fx.Export(ctx, "a1", false)
+	require.NoError(t, err)
+	assert.Equal(t, ExportVersion, export.Version)
+	assert.Equal(t, "a1", export.AccountId)
+	require.Len(t, export.Tokens, 1)
+	assert.Equal(t, "toke*******-123", export.Tokens[0].Token)
+	assert.Equal(t, "p1", export.Tokens[0].PeerId)
+	assert.Equal(t, "ios", export.Tokens[0].Platform)
+	assert.Equal(t, "valid", export.Tokens[0].Status)
+	assert.Equal(t, "1.0.0", export.Tokens[0].AppVersion)
+	assert.Equal(t, []string{"s1/t1", "s2/t2"}, export.Topics)
+	require.Len(t, export.Spaces, 3)
+	for _, space := range export.Spaces {
+		space.Created = 0
+		switch space.SpaceKey {
+		case "s1":
+			assert.Equal(t, ExportSpace{SpaceKey: "s1", Type: "regular", Author: true, Admin: true}, space)
+		case "s2":
+			assert.Equal(t, ExportSpace{SpaceKey: "s2", Type: "regular", Admin: true}, space)
+		default:
+			assert.Equal(t, ExportSpace{SpaceKey: "s3", Type: "oneToOne", Member: true}, space)
+		}
+	}
+	assert.Equal(t, []string{"a3"}, export.Blocked)
+	require.Len(t, export.Reports, 1)
+	assert.Equal(t, ExportReport{GroupId: "g1", SenderId: "a3", Reason: "spam", Created: export.Reports[0].Created}, export.Reports[0])
+
+	export, err = fx.Export(ctx, "a1", true)
+	require.NoError(t, err)
+	assert.Equal(t, "token-value-123", export.Tokens[0].Token)
+
+	export, err = fx.Export(ctx, "a4", false)
+	require.NoError(t, err)
+	assert.Len(t, export.Tokens, 0)
+	assert.Len(t, export.Topics, 0)
+	assert.Len(t, export.Spaces, 0)
+	assert.Len(t, export.Blocked, 0)
+	assert.Len(t, export.Reports, 0)
+}
+
+func newFixture(t testing.TB) *fixture {
+	fx := &fixture{
+		AccountData: New(),
+		a:           new(app.App),
+		db:          db.New(),
+		tokenRepo:   tokenrepo.New(),
+		accountRepo: accountrepo.New(),
+		spaceRepo:   spacerepo.New(),
+		reportRepo:  reportrepo.New(),
+	}
+	fx.a.Register(&testConfig{
+		Mongo: db.Mongo{
+			Connect:  "mongodb://localhost:27017",
+			Database: "publish_unittest",
+		},
+	}).
+		Register(fx.db).
+		Register(fx.tokenRepo).
+		Register(fx.accountRepo).
+		Register(fx.spaceRepo).
+		Register(fx.reportRepo).
+		Register(fx.AccountData)
+	require.NoError(t, fx.a.Start(ctx))
+	t.Cleanup(func() {
+		fx.finish(t)
+	})
+	return fx
+}
+
+type fixture struct {
+	AccountData
+	a           *app.App
+	db          db.Database
+	tokenRepo   tokenrepo.TokenRepo
+	accountRepo accountrepo.AccountRepo
+	spaceRepo   spacerepo.SpaceRepo
+	reportRepo  reportrepo.ReportRepo
+}
+
+func (fx *fixture) finish(t testing.TB) {
+	_ = fx.db.Db().Drop(ctx)
+	require.NoError(t, fx.a.Close(ctx))
+}
+
+type testConfig struct {
+	Mongo db.Mongo
+}
+
+func (t testConfig) Init(a *app.App) (err error) {
+	return
+}
+
+func (t testConfig) Name() (name string) {
+	return "config"
+}
+
+func (t testConfig) GetMongo() db.Mongo {
+	return t.Mongo
+}
+
+func (t testConfig) GetTokenRepo() tokenrepo.Config {
+	return tokenrepo.Config{}
+}
@@ -0,0 +1,137 @@
+package accountdata
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+// ExportVersion is incremented on every incompatible change of the Export format
+const ExportVersion = 1
+
+type Export struct {
+	Version   int            `json:"version"`
+	Created   int64          `json:"created"`
+	AccountId string         `json:"accountId"`
+	Tokens    []ExportToken  `json:"tokens"`
+	Topics    []string       `json:"topics"`
+	Spaces    []ExportSpace  `json:"spaces"`
+	Blocked   []string       `json:"blocked"`
+	Reports   []ExportReport `json:"reports"`
+}
+
+type ExportToken struct {
+	Token           string `json:"token"`
+	PeerId          string `json:"peerId"`
+	Platform        string `json:"platform"`
+	Status          string `json:"status"`
+	AppVersion      string `json:"appVersion"`
+	OsVersion       string `json:"osVersion"`
+	BuildChannel    string `json:"buildChannel"`
+	ApnsEnvironment string `json:"apnsEnvironment"`
+	Created         int64  `json:"created"`
+	Updated         int64  `json:"updated"`
+	FailCount       int    `json:"failCount"`
+	InvalidReason   string `json:"invalidReason,omitempty"`
+	InvalidSince    int64  `json:"invalidSince,omitempty"`
+}
+
+type ExportSpace struct {
+	SpaceKey string `json:"spaceKey"`
+	Type     string `json:"type"`
+	Created  int64  `json:"created"`
+	Author   bool   `json:"author"`
+	Admin    bool   `json:"admin"`
+	Member   bool   `json:"member"`
+}
+
+// ExportReport is a notification report made by the account
+type ExportReport struct {
+	GroupId  string `json:"groupId"`
+	SenderId string `json:"senderId,omitempty"`
+	Reason   string `json:"reason"`
+	Created  int64  `json:"created"`
+}
+
+func (d *accountData) Export(ctx context.Context, accountId string, revealTokens bool) (export *Export, err error) {
+	tokens, err := d.tokenRepo.GetTokensByAccountId(ctx, accountId)
+	if err != nil {
+		return
+	}
+	account, err := d.accountRepo.GetAccount(ctx, accountId)
+	if err != nil {
+		return
+	}
+	spaces, err := d.spaceRepo.GetByAccount(ctx, accountId)
+	if err != nil {
+		return
+	}
+	reports, err := d.reportRepo.GetByReporterId(ctx, accountId)
+	if err != nil {
+		return
+	}
+	export = &Export{
+		Version:   ExportVersion,
+		Created:   time.Now().Unix(),
+		AccountId: accountId,
+		Tokens:    make([]ExportToken, len(tokens)),
+		Topics:    make([]string, len(account.Topics)),
+		Spaces:    make([]ExportSpace, len(spaces)),
+		Blocked:   append([]string{}, account.Blocked...),
+		Reports:   make([]ExportReport, len(reports)),
+	}
+	for i, token := range tokens {
+		tokenValue := token.Id
+		if !revealTokens {
+			tokenValue = maskToken(tokenValue)
+		}
+		export.Tokens[i] = ExportToken{
+			Token:           tokenValue,
+			PeerId:          token.PeerId,
+			Platform:        token.Platform.String(),
+			Status:          token.Status.String(),
+			AppVersion:      token.AppVersion,
+			OsVersion:       token.OsVersion,
+			BuildChannel:    token.BuildChannel.String(),
+			ApnsEnvironment: token.ApnsEnvironment.String(),
+			Created:         token.Created,
+			Updated:         token.Updated,
+			FailCount:       token.FailCount,
+			InvalidReason:   string(token.InvalidReason),
+			InvalidSince:    token.InvalidSince,
+		}
+	}
+	for i, topic := range account.Topics {
+		export.Topics[i] = string(topic)
+	}
+	for i, space := range spaces {
+		export.Spaces[i] = ExportSpace{
+			SpaceKey: space.Id,
+			Type:     space.Type.String(),
+			Created:  space.Created,
+			Author:   space.Author == accountId,
+			Admin:    space.Type == domain.SpaceTypeRegular && space.IsAdmin(accountId),
+			Member:   slices.Contains(space.Members, accountId),
+		}
+	}
+	for i, report := range reports {
+		export.Reports[i] = ExportReport{
+			GroupId:  report.GroupId,
+			SenderId: report.SenderId,
+			Reason:   report.Reason,
+			Created:  report.Created,
+		}
+	}
+	return
+}
+
+func maskToken(token string) string {
+	const visible = 4
+	if len(token) <= visible*2 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:visible] + strings.Repeat("*", len(token)-visible*2) + token[len(token)-visible:]
+}
@@ -0,0 +1,13 @@
+package accountdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskToken(t *testing.T) {
+	assert.Equal(t, "", maskToken(""))
+	assert.Equal(t, "********", maskToken("12345678"))
+	assert.Equal(t, "1234*5678", maskToken("123405678"))
+}
@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/anyproto/anytype-push-server/accountdata (interfaces: AccountData)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock_accountdata/mock_accountdata.go github.com/anyproto/anytype-push-server/accountdata AccountData
+//
+
+// Package mock_accountdata is a generated GoMock package.
+package mock_accountdata
+
+import (
+	context "context"
+	reflect "reflect"
+
+	app "github.com/anyproto/any-sync/app"
+	accountdata "github.com/anyproto/anytype-push-server/accountdata"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountData is a mock of AccountData interface.
+type MockAccountData struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDataMockRecorder
+}
+
+// MockAccountDataMockRecorder is the mock recorder for MockAccountData.
+type MockAccountDataMockRecorder struct {
+	mock *MockAccountData
+}
+
+// NewMockAccountData creates a new mock instance.
+func NewMockAccountData(ctrl *gomock.Controller) *MockAccountData {
+	mock := &MockAccountData{ctrl: ctrl}
+	mock.recorder = &MockAccountDataMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountData) EXPECT() *MockAccountDataMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockAccountData) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccountDataMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccountData)(nil).Delete), arg0, arg1)
+}
+
+// Export mocks base method.
+func (m *MockAccountData) Export(arg0 context.Context, arg1 string, arg2 bool) (*accountdata.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*accountdata.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockAccountDataMockRecorder) Export(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockAccountData)(nil).Export), arg0, arg1, arg2)
+}
+
+// Init mocks base method.
+func (m *MockAccountData) Init(arg0 *app.App) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockAccountDataMockRecorder) Init(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockAccountData)(nil).Init), arg0)
+}
+
+// Name mocks base method.
+func (m *MockAccountData) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockAccountDataMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockAccountData)(nil).Name))
+}
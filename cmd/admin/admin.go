@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/logger"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/anytype-push-server/accountdata"
+	"github.com/anyproto/anytype-push-server/config"
+	"github.com/anyproto/anytype-push-server/db"
+	"github.com/anyproto/anytype-push-server/domain"
+	"github.com/anyproto/anytype-push-server/repo/accountrepo"
+	"github.com/anyproto/anytype-push-server/repo/banrepo"
+	"github.com/anyproto/anytype-push-server/repo/reportrepo"
+	"github.com/anyproto/anytype-push-server/repo/spacerepo"
+	"github.com/anyproto/anytype-push-server/repo/tokenrepo"
+)
+
+var log = logger.NewNamed("push.admin")
+
+var (
+	flagConfigFile = flag.String("c", "etc/anytype-push-server.yml", "path to config file")
+	flagTimeout    = flag.Duration("t", time.Minute, "command timeout")
+	flagFullTokens = flag.Bool("full-tokens", false, "don't mask token values in the output")
+)
+
+type command struct {
+	usage string
+	nArgs int
+	run   func(ctx context.Context, a *app.App, args []string) error
+}
+
+var commands = map[string]command{
+	"delete-account": {
+		usage: "<accountId>: remove all push data of the account",
+		nArgs: 1,
+		run: func(ctx context.Context, a *app.App, args []string) error {
+			return a.MustComponent(accountdata.CName).(accountdata.AccountData).Delete(ctx, args[0])
+		},
+	},
+	"export-account": {
+		usage: "<accountId>: print all push data of the account as json",
+		nArgs: 1,
+		run: func(ctx context.Context, a *app.App, args []string) error {
+			export, err := a.MustComponent(accountdata.CName).(accountdata.AccountData).Export(ctx, args[0], *flagFullTokens)
+			if err != nil {
+				return err
+			}
+			return printJson(export)
+		},
+	},
+	"ban": {
+		usage: "<accountId> <reason>: forbid the account to send notifications",
+		nArgs: 2,
+		run: func(ctx context.Context, a *app.App, args []string) error {
+			return a.MustComponent(banrepo.CName).(banrepo.BanRepo).Ban(ctx, domain.Ban{AccountId: args[0], Reason: args[1]})
+		},
+	},
+	"unban": {
+		usage: "<accountId>: remove the account from the ban list",
+		nArgs: 1,
+		run: func(ctx context.Context, a *app.App, args []string) error {
+			return a.MustComponent(banrepo.CName).(banrepo.BanRepo).Unban(ctx, args[0])
+		},
+	},
+	"bans": {
+		usage: ": print banned accounts",
+		nArgs: 0,
+		run: func(ctx context.Context, a *app.App, args []string) error {
+			bans, err := a.MustComponent(banrepo.CName).(banrepo.BanRepo).List(ctx)
+			if err != nil {
+				return err
+			}
+			return printJson(bans)
+		},
+	},
+	"reports": {
+		usage: "<accountId>: print reports about notifications sent by the account",
+		nArgs: 1,
+		run: func(ctx context.Context, a *app.App, args []string) error {
+			reports, err := a.MustComponent(reportrepo.CName).(reportrepo.ReportRepo).GetBySenderId(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return printJson(reports)
+		},
+	},
+	"group-reports": {
+		usage: "<groupId>: print reports about the notification group",
+		nArgs: 1,
+		run: func(ctx context.Context, a *app.App, args []string) error {
+			reports, err := a.MustComponent(reportrepo.CName).(reportrepo.ReportRepo).GetByGroupId(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return printJson(reports)
+		},
+	},
+	"tokens": {
+		usage: "<accountId>: print tokens of the account with their status and quarantine info",
+		nArgs: 1,
+		run: func(ctx context.Context, a *app.App, args []string) error {
+			export, err := a.MustComponent(accountdata.CName).(accountdata.AccountData).Export(ctx, args[0], *flagFullTokens)
+			if err != nil {
+				return err
+			}
+			return printJson(export.Tokens)
+		},
+	},
+}
+
+func printJson(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok || flag.NArg()-1 != cmd.nArgs {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *flagTimeout)
+	defer cancel()
+
+	conf, err := config.NewFromFile(*flagConfigFile)
+	if err != nil {
+		log.Fatal("can't open config file", zap.Error(err))
+	}
+
+	a := newApp(conf)
+	if err = a.Start(ctx); err != nil {
+		log.Fatal("can't start app", zap.Error(err))
+	}
+	defer func() {
+		_ = a.Close(context.Background())
+	}()
+
+	if err = cmd.run(ctx, a, flag.Args()[1:]); err != nil {
+		log.Error("command failed", zap.String("command", flag.Arg(0)), zap.Error(err))
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <command> [args]\n\nCommands:\n", os.Args[0])
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "  %s %s\n", name, commands[name].usage)
+	}
+	_, _ = fmt.Fprintln(flag.CommandLine.Output(), "\nFlags:")
+	flag.PrintDefaults()
+}
+
+func newApp(conf *config.Config) *app.App {
+	// background jobs belong to the server
+	conf.TokenRepo.Janitor.Enabled = false
+	conf.TokenRepo.Stats.Enabled = false
+
+	a := new(app.App)
+	a.Register(conf)
+	Bootstrap(a)
+	return a
+}
+
+func Bootstrap(a *app.App) {
+	a.Register(db.New()).
+		Register(tokenrepo.New()).
+		Register(accountrepo.New()).
+		Register(spacerepo.New()).
+		Register(banrepo.New()).
+		Register(reportrepo.New()).
+		Register(accountdata.New())
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/config"
+)
+
+func TestNewApp(t *testing.T) {
+	// the admin app is run with the shipped server config
+	conf, err := config.NewFromFile("../../etc/anytype-push-server.yml")
+	require.NoError(t, err)
+	a := newApp(conf)
+	a.IterateComponents(func(c app.Component) {
+		require.NoError(t, c.Init(a), c.Name())
+	})
+}
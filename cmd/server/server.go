@@ -31,12 +31,17 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/anyproto/anytype-push-server/account"
+	"github.com/anyproto/anytype-push-server/accountdata"
 	"github.com/anyproto/anytype-push-server/config"
 	"github.com/anyproto/anytype-push-server/db"
 	"github.com/anyproto/anytype-push-server/push"
 	"github.com/anyproto/anytype-push-server/queue"
+	"github.com/anyproto/anytype-push-server/ratelimit"
 	"github.com/anyproto/anytype-push-server/redisprovider"
+	"github.com/anyproto/anytype-push-server/replay"
 	"github.com/anyproto/anytype-push-server/repo/accountrepo"
+	"github.com/anyproto/anytype-push-server/repo/banrepo"
+	"github.com/anyproto/anytype-push-server/repo/reportrepo"
 	"github.com/anyproto/anytype-push-server/repo/spacerepo"
 	"github.com/anyproto/anytype-push-server/repo/tokenrepo"
 	"github.com/anyproto/anytype-push-server/sender"
@@ -125,7 +130,12 @@ func Bootstrap(a *app.App) {
 		Register(tokenrepo.New()).
 		Register(accountrepo.New()).
 		Register(spacerepo.New()).
+		Register(banrepo.New()).
+		Register(reportrepo.New()).
+		Register(accountdata.New()).
 		Register(queue.New()).
+		Register(ratelimit.New()).
+		Register(replay.New()).
 		Register(sender.New()).
 		Register(fcm.New()).
 		Register(push.New()).
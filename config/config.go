@@ -14,7 +14,11 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/anyproto/anytype-push-server/db"
+	"github.com/anyproto/anytype-push-server/push"
+	"github.com/anyproto/anytype-push-server/ratelimit"
 	"github.com/anyproto/anytype-push-server/redisprovider"
+	"github.com/anyproto/anytype-push-server/replay"
+	"github.com/anyproto/anytype-push-server/repo/tokenrepo"
 	"github.com/anyproto/anytype-push-server/sender/provider/fcm"
 )
 
@@ -44,6 +48,10 @@ type Config struct {
 	NetworkUpdateIntervalSec int                    `yaml:"networkUpdateIntervalSec"`
 	FCM                      fcm.Config             `yaml:"fcm"`
 	Metric                   metric.Config          `yaml:"metric"`
+	TokenRepo                tokenrepo.Config       `yaml:"tokenRepo"`
+	Push                     push.Config            `yaml:"push"`
+	RateLimit                ratelimit.Config       `yaml:"rateLimit"`
+	Replay                   replay.Config          `yaml:"replay"`
 }
 
 func (c *Config) Init(a *app.App) (err error) {
@@ -101,3 +109,19 @@ func (c *Config) GetFCM() fcm.Config {
 func (c *Config) GetMetric() metric.Config {
 	return c.Metric
 }
+
+func (c *Config) GetTokenRepo() tokenrepo.Config {
+	return c.TokenRepo
+}
+
+func (c *Config) GetPush() push.Config {
+	return c.Push
+}
+
+func (c *Config) GetRateLimit() ratelimit.Config {
+	return c.RateLimit
+}
+
+func (c *Config) GetReplay() replay.Config {
+	return c.Replay
+}
@@ -1,3 +1,5 @@
+//go:generate mockgen -destination mock_db/mock_db.go github.com/anyproto/anytype-push-server/db Database
+
 package db
 
 import (
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/anyproto/anytype-push-server/db (interfaces: Database)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock_db/mock_db.go github.com/anyproto/anytype-push-server/db Database
+//
+
+// Package mock_db is a generated GoMock package.
+package mock_db
+
+import (
+	context "context"
+	reflect "reflect"
+
+	app "github.com/anyproto/any-sync/app"
+	mongo "go.mongodb.org/mongo-driver/mongo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDatabase is a mock of Database interface.
+type MockDatabase struct {
+	ctrl     *gomock.Controller
+	recorder *MockDatabaseMockRecorder
+}
+
+// MockDatabaseMockRecorder is the mock recorder for MockDatabase.
+type MockDatabaseMockRecorder struct {
+	mock *MockDatabase
+}
+
+// NewMockDatabase creates a new mock instance.
+func NewMockDatabase(ctrl *gomock.Controller) *MockDatabase {
+	mock := &MockDatabase{ctrl: ctrl}
+	mock.recorder = &MockDatabaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDatabase) EXPECT() *MockDatabaseMockRecorder {
+	return m.recorder
+}
+
+// Db mocks base method.
+func (m *MockDatabase) Db() *mongo.Database {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Db")
+	ret0, _ := ret[0].(*mongo.Database)
+	return ret0
+}
+
+// Db indicates an expected call of Db.
+func (mr *MockDatabaseMockRecorder) Db() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Db", reflect.TypeOf((*MockDatabase)(nil).Db))
+}
+
+// Init mocks base method.
+func (m *MockDatabase) Init(arg0 *app.App) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockDatabaseMockRecorder) Init(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockDatabase)(nil).Init), arg0)
+}
+
+// Name mocks base method.
+func (m *MockDatabase) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockDatabaseMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockDatabase)(nil).Name))
+}
+
+// Tx mocks base method.
+func (m *MockDatabase) Tx(arg0 context.Context, arg1 func(mongo.SessionContext) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tx indicates an expected call of Tx.
+func (mr *MockDatabaseMockRecorder) Tx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tx", reflect.TypeOf((*MockDatabase)(nil).Tx), arg0, arg1)
+}
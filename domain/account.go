@@ -5,4 +5,42 @@ type Account struct {
 	Topics  []Topic `bson:"topics"`
 	Updated int64   `bson:"updated"`
 	Created int64   `bson:"created"`
+	// Version is incremented on every change of topics
+	Version int64 `bson:"version"`
+	// Changes is a bounded log of the latest topic changes, the last entry corresponds to Version
+	Changes []TopicsChange `bson:"changes"`
+	// Expiring holds the expiry time of temporary subscriptions
+	Expiring []TopicExpiry `bson:"expiring"`
+	// Blocked is a list of accounts whose notifications are not delivered to this account
+	Blocked []string `bson:"blocked"`
+}
+
+// ActiveTopics returns topics not expired at the given time and the expiry of temporary ones
+func (a Account) ActiveTopics(now int64) (topics []Topic, expires map[Topic]int64) {
+	expires = make(map[Topic]int64, len(a.Expiring))
+	for _, e := range a.Expiring {
+		expires[e.Topic] = e.Expires
+	}
+	topics = make([]Topic, 0, len(a.Topics))
+	for _, topic := range a.Topics {
+		if exp, ok := expires[topic]; ok && exp < now {
+			delete(expires, topic)
+			continue
+		}
+		topics = append(topics, topic)
+	}
+	return
+}
+
+type TopicExpiry struct {
+	Topic Topic `bson:"topic"`
+	// Expires is a unix time after which the subscription is removed
+	Expires int64 `bson:"expires"`
+}
+
+type TopicsChange struct {
+	Added   []Topic `bson:"added,omitempty"`
+	Removed []Topic `bson:"removed,omitempty"`
+	// Reset is set when the whole list of topics was replaced
+	Reset bool `bson:"reset,omitempty"`
 }
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccount_ActiveTopics(t *testing.T) {
+	account := Account{
+		Topics: []Topic{"s/1", "s/2", "s/3"},
+		Expiring: []TopicExpiry{
+			{Topic: "s/2", Expires: 10},
+			{Topic: "s/3", Expires: 30},
+		},
+	}
+	topics, expires := account.ActiveTopics(20)
+	assert.Equal(t, []Topic{"s/1", "s/3"}, topics)
+	assert.Equal(t, map[Topic]int64{"s/3": 30}, expires)
+}
@@ -0,0 +1,8 @@
+package domain
+
+// Ban forbids the account to send notifications
+type Ban struct {
+	AccountId string `bson:"_id"`
+	Reason    string `bson:"reason"`
+	Created   int64  `bson:"created"`
+}
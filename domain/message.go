@@ -1,8 +1,10 @@
 package domain
 
 type Message struct {
-	Tokens   []string
-	Data     map[string]string
-	Platform Platform
-	Silent   bool
+	Tokens []string
+	// AccountIds holds the recipient account of the token with the same index, one token may be bound to several accounts
+	AccountIds []string
+	Data       map[string]string
+	Platform   Platform
+	Silent     bool
 }
@@ -0,0 +1,11 @@
+package domain
+
+// Report is a user complaint about a received notification
+type Report struct {
+	ReporterId string `bson:"reporterId"`
+	GroupId    string `bson:"groupId"`
+	// SenderId is the account that sent the notification, if known to the reporter
+	SenderId string `bson:"senderId,omitempty"`
+	Reason   string `bson:"reason"`
+	Created  int64  `bson:"created"`
+}
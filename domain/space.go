@@ -1,7 +1,71 @@
 package domain
 
+import (
+	"slices"
+
+	"github.com/anyproto/anytype-push-server/pushclient/pushapi"
+)
+
+type SpaceType uint8
+
+const (
+	SpaceTypeRegular  = SpaceType(pushapi.SpaceType_Regular)
+	SpaceTypeOneToOne = SpaceType(pushapi.SpaceType_OneToOne)
+)
+
+func (t SpaceType) String() string {
+	switch t {
+	case SpaceTypeRegular:
+		return "regular"
+	case SpaceTypeOneToOne:
+		return "oneToOne"
+	default:
+		return "unknown"
+	}
+}
+
 type Space struct {
-	Id      string `bson:"_id"`
-	Author  string `bson:"author"`
-	Created int64  `bson:"created"`
+	Id      string    `bson:"_id"`
+	Author  string    `bson:"author"`
+	Created int64     `bson:"created"`
+	Type    SpaceType `bson:"type"`
+	// Members are accounts that registered a 1-1 space, the author is one of them
+	Members []string `bson:"members,omitempty"`
+	// Admins are accounts allowed to manage the space registration
+	Admins []string    `bson:"admins,omitempty"`
+	Policy SpacePolicy `bson:"policy"`
+}
+
+type SpacePolicyMode uint8
+
+const (
+	SpacePolicyAll          = SpacePolicyMode(pushapi.SpacePolicyMode_AllNotifications)
+	SpacePolicyMentionsOnly = SpacePolicyMode(pushapi.SpacePolicyMode_MentionsOnly)
+	SpacePolicyDisabled     = SpacePolicyMode(pushapi.SpacePolicyMode_NoNotifications)
+)
+
+// SpacePolicy restricts notifications of the space, the zero value allows everything
+type SpacePolicy struct {
+	Mode SpacePolicyMode `bson:"mode"`
+	// MaxFanOutPerMinute limits notified subscribers of the space per minute, zero means no limit
+	MaxFanOutPerMinute int `bson:"maxFanOutPerMinute"`
+}
+
+func (p SpacePolicy) AllowsTopic(topic Topic) bool {
+	switch p.Mode {
+	case SpacePolicyDisabled:
+		return false
+	case SpacePolicyMentionsOnly:
+		return topic.IsMention()
+	default:
+		return true
+	}
+}
+
+// IsAdmin reports whether the account manages the space, spaces without admins are managed by the author
+func (s Space) IsAdmin(accountId string) bool {
+	if s.Admins == nil {
+		return s.Author == accountId
+	}
+	return slices.Contains(s.Admins, accountId)
 }
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/anyproto/any-sync/testutil/accounttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpace_IsAdmin(t *testing.T) {
+	space := Space{Author: "a"}
+	assert.True(t, space.IsAdmin("a"))
+	assert.False(t, space.IsAdmin("b"))
+
+	space.Admins = []string{"b"}
+	assert.False(t, space.IsAdmin("a"))
+	assert.True(t, space.IsAdmin("b"))
+}
+
+func TestSpacePolicy_AllowsTopic(t *testing.T) {
+	spaceKey := make([]byte, 32)
+	_, _ = rand.Read(spaceKey)
+	as := accounttest.AccountTestService{}
+	_ = as.Init(nil)
+	mention := NewTopic(spaceKey, as.Account().SignKey.GetPublic().Account())
+	topic := NewTopic(spaceKey, "chat")
+
+	assert.True(t, SpacePolicy{}.AllowsTopic(topic))
+	assert.True(t, SpacePolicy{Mode: SpacePolicyMentionsOnly}.AllowsTopic(mention))
+	assert.False(t, SpacePolicy{Mode: SpacePolicyMentionsOnly}.AllowsTopic(topic))
+	assert.False(t, SpacePolicy{Mode: SpacePolicyDisabled}.AllowsTopic(mention))
+}
@@ -27,12 +27,81 @@ const (
 	TokenStatusInvalid
 )
 
+func (s TokenStatus) String() string {
+	switch s {
+	case TokenStatusValid:
+		return "valid"
+	case TokenStatusInvalid:
+		return "invalid"
+	default:
+		return "unknown"
+	}
+}
+
+type BuildChannel uint8
+
+const (
+	BuildChannelRelease = BuildChannel(pushapi.BuildChannel_Release)
+	BuildChannelBeta    = BuildChannel(pushapi.BuildChannel_Beta)
+	BuildChannelDev     = BuildChannel(pushapi.BuildChannel_Dev)
+)
+
+func (c BuildChannel) String() string {
+	switch c {
+	case BuildChannelRelease:
+		return "release"
+	case BuildChannelBeta:
+		return "beta"
+	case BuildChannelDev:
+		return "dev"
+	default:
+		return "unknown"
+	}
+}
+
+type ApnsEnvironment uint8
+
+const (
+	ApnsEnvironmentProduction = ApnsEnvironment(pushapi.ApnsEnvironment_Production)
+	ApnsEnvironmentSandbox    = ApnsEnvironment(pushapi.ApnsEnvironment_Sandbox)
+)
+
+func (e ApnsEnvironment) String() string {
+	switch e {
+	case ApnsEnvironmentProduction:
+		return "production"
+	case ApnsEnvironmentSandbox:
+		return "sandbox"
+	default:
+		return "unknown"
+	}
+}
+
+type TokenInvalidReason string
+
+const (
+	TokenInvalidReasonUnregistered    TokenInvalidReason = "unregistered"
+	TokenInvalidReasonInvalidArgument TokenInvalidReason = "invalidArgument"
+	TokenInvalidReasonSenderMismatch  TokenInvalidReason = "senderMismatch"
+)
+
 type Token struct {
-	Id        string      `bson:"_id"`
+	// Id is a provider token, the same token may be bound to several accounts
+	Id        string      `bson:"token"`
 	AccountId string      `bson:"accountId"`
 	PeerId    string      `bson:"peerId"`
 	Platform  Platform    `bson:"platform"`
 	Status    TokenStatus `bson:"status"`
 	Created   int64       `bson:"created"`
 	Updated   int64       `bson:"updated"`
+	Refreshed int64       `bson:"refreshed"`
+	// client metadata reported with SetToken
+	AppVersion      string          `bson:"appVersion"`
+	OsVersion       string          `bson:"osVersion"`
+	BuildChannel    BuildChannel    `bson:"buildChannel"`
+	ApnsEnvironment ApnsEnvironment `bson:"apnsEnvironment"`
+	// quarantine info, filled when a provider reports the token as invalid
+	FailCount     int                `bson:"failCount"`
+	InvalidReason TokenInvalidReason `bson:"invalidReason,omitempty"`
+	InvalidSince  int64              `bson:"invalidSince,omitempty"`
 }
@@ -1,17 +1,59 @@
 package domain
 
 import (
+	"slices"
 	"strings"
 
+	"github.com/anyproto/any-sync/util/crypto"
 	"github.com/mr-tron/base58"
 )
 
+// TopicWildcard subscribes to every topic of the space
+const TopicWildcard = "*"
+
 func NewTopic(spaceKey []byte, topic string) Topic {
 	return Topic(base58.Encode(spaceKey) + "/" + topic)
 }
 
 type Topic string
 
+func (t Topic) IsWildcard() bool {
+	return t.Topic() == TopicWildcard
+}
+
+// IsMention reports whether the topic is addressed to a single account
+func (t Topic) IsMention() bool {
+	_, err := crypto.DecodeAccountAddress(t.Topic())
+	return err == nil
+}
+
+// SpaceWildcard returns the wildcard topic of the topic's space
+func (t Topic) SpaceWildcard() Topic {
+	return Topic(t.SpaceKeyBase58() + "/" + TopicWildcard)
+}
+
+// WithSpaceWildcards adds the wildcard topic of every space to the list
+func WithSpaceWildcards(topics []Topic) []Topic {
+	result := slices.Clone(topics)
+	for _, topic := range topics {
+		if wildcard := topic.SpaceWildcard(); !slices.Contains(result, wildcard) {
+			result = append(result, wildcard)
+		}
+	}
+	return result
+}
+
+// SpaceKeys returns unique space keys of the topics
+func SpaceKeys(topics []Topic) []string {
+	var spaceKeys []string
+	for _, topic := range topics {
+		if spaceKey := topic.SpaceKeyBase58(); !slices.Contains(spaceKeys, spaceKey) {
+			spaceKeys = append(spaceKeys, spaceKey)
+		}
+	}
+	return spaceKeys
+}
+
 func (t Topic) SpaceKeyRaw() ([]byte, error) {
 	return base58.Decode(t.SpaceKeyBase58())
 
@@ -15,3 +15,22 @@ func TestTopic_SpaceKey(t *testing.T) {
 	res := topic.SpaceKeyBase58()
 	assert.Equal(t, base58.Encode(spaceKey), res)
 }
+
+func TestTopic_Wildcard(t *testing.T) {
+	spaceKey := make([]byte, 32)
+	_, _ = rand.Read(spaceKey)
+	topic := NewTopic(spaceKey, "topic")
+	assert.False(t, topic.IsWildcard())
+	wildcard := topic.SpaceWildcard()
+	assert.Equal(t, NewTopic(spaceKey, TopicWildcard), wildcard)
+	assert.True(t, wildcard.IsWildcard())
+	assert.Equal(t, topic.SpaceKeyBase58(), wildcard.SpaceKeyBase58())
+}
+
+func TestWithSpaceWildcards(t *testing.T) {
+	spaceKey := make([]byte, 32)
+	_, _ = rand.Read(spaceKey)
+	topics := []Topic{NewTopic(spaceKey, "t1"), NewTopic(spaceKey, "t2"), NewTopic(spaceKey, TopicWildcard)}
+	assert.Equal(t, topics, WithSpaceWildcards(topics))
+	assert.Equal(t, topics, WithSpaceWildcards(topics[:2]))
+}
@@ -20,6 +20,7 @@ require (
 	go.uber.org/zap v1.27.1
 	google.golang.org/api v0.262.0
 	gopkg.in/yaml.v3 v3.0.1
+	storj.io/drpc v0.0.34
 )
 
 require (
@@ -130,7 +131,6 @@ require (
 	google.golang.org/grpc v1.78.0 // indirect
 	google.golang.org/protobuf v1.36.11 // indirect
 	lukechampine.com/blake3 v1.4.1 // indirect
-	storj.io/drpc v0.0.34 // indirect
 )
 
 replace github.com/anyproto/anytype-push-server/pushclient => ./pushclient
@@ -0,0 +1,36 @@
+package push
+
+const (
+	defaultMaxTopicsPerAccount = 5000
+	defaultMaxTopicsPerRequest = 1000
+	defaultMaxTopicLength      = 256
+	defaultMaxSpacesPerAccount = 1000
+)
+
+type configSource interface {
+	GetPush() Config
+}
+
+type Config struct {
+	// ValidateTokens enables a provider dry-run send before a token is stored
+	ValidateTokens bool         `yaml:"validateTokens"`
+	Limits         LimitsConfig `yaml:"limits"`
+	// SpaceVerification controls the gradual rollout of the registered space check
+	SpaceVerification SpaceVerificationConfig `yaml:"spaceVerification"`
+}
+
+type LimitsConfig struct {
+	MaxTopicsPerAccount int `yaml:"maxTopicsPerAccount"`
+	MaxTopicsPerRequest int `yaml:"maxTopicsPerRequest"`
+	// MaxTopicLength is a max length of the topic name without the space key
+	MaxTopicLength      int `yaml:"maxTopicLength"`
+	MaxSpacesPerAccount int `yaml:"maxSpacesPerAccount"`
+}
+
+type SpaceVerificationConfig struct {
+	// RolloutPercent is a share of spaces whose topics require a registered space, 0 disables the check, 100 enables it for all spaces
+	RolloutPercent int `yaml:"rolloutPercent"`
+	// Enabled and Disabled are space keys that override the rollout percent
+	Enabled  []string `yaml:"enabled"`
+	Disabled []string `yaml:"disabled"`
+}
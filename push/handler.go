@@ -26,7 +26,7 @@ func (h *handler) CreateSpace(ctx context.Context, req *pushapi.CreateSpaceReque
 			zap.Error(err),
 		)
 	}()
-	if err = h.p.CreateSpace(ctx, req.SpaceKey, req.AccountSignature); err != nil {
+	if err = h.p.CreateSpace(ctx, req.SpaceKey, req.AccountSignature, req.SpaceType); err != nil {
 		return
 	}
 	return &pushapi.Ok{}, nil
@@ -47,6 +47,97 @@ func (h *handler) RemoveSpace(ctx context.Context, req *pushapi.RemoveSpaceReque
 	return &pushapi.Ok{}, nil
 }
 
+func (h *handler) RotateSpaceKey(ctx context.Context, req *pushapi.RotateSpaceKeyRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.rotateSpaceKey",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.RotateSpaceKey(ctx, req); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
+func (h *handler) TransferSpace(ctx context.Context, req *pushapi.TransferSpaceRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.transferSpace",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.TransferSpace(ctx, req); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
+func (h *handler) AddSpaceAdmin(ctx context.Context, req *pushapi.AddSpaceAdminRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.addSpaceAdmin",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.AddSpaceAdmin(ctx, req); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
+func (h *handler) RemoveSpaceAdmin(ctx context.Context, req *pushapi.RemoveSpaceAdminRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.removeSpaceAdmin",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.RemoveSpaceAdmin(ctx, req); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
+func (h *handler) GetSpacePolicy(ctx context.Context, req *pushapi.GetSpacePolicyRequest) (resp *pushapi.GetSpacePolicyResponse, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.getSpacePolicy",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	policy, err := h.p.GetSpacePolicy(ctx, req.SpaceKey, req.AccountSignature)
+	if err != nil {
+		return
+	}
+	return &pushapi.GetSpacePolicyResponse{Policy: policy}, nil
+}
+
+func (h *handler) SetSpacePolicy(ctx context.Context, req *pushapi.SetSpacePolicyRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.setSpacePolicy",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.SetSpacePolicy(ctx, req); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
 func (h *handler) Subscriptions(ctx context.Context, req *pushapi.SubscriptionsRequest) (resp *pushapi.SubscriptionsResponse, err error) {
 	st := time.Now()
 	defer func() {
@@ -56,15 +147,29 @@ func (h *handler) Subscriptions(ctx context.Context, req *pushapi.SubscriptionsR
 			zap.Error(err),
 		)
 	}()
-	topics, err := h.p.Subscriptions(ctx)
+	topics, version, err := h.p.Subscriptions(ctx)
 	if err != nil {
 		return
 	}
 	return &pushapi.SubscriptionsResponse{
-		Topics: topics,
+		Topics:  topics,
+		Version: version,
 	}, nil
 }
 
+func (h *handler) SyncSubscriptions(ctx context.Context, req *pushapi.SyncSubscriptionsRequest) (resp *pushapi.SyncSubscriptionsResponse, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.syncSubscriptions",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Int64("version", req.Version),
+			zap.Error(err),
+		)
+	}()
+	return h.p.SyncSubscriptions(ctx, req.Version)
+}
+
 func (h *handler) Subscribe(ctx context.Context, req *pushapi.SubscribeRequest) (resp *pushapi.Ok, err error) {
 	st := time.Now()
 	defer func() {
@@ -169,3 +274,143 @@ func (h *handler) NotifySilent(ctx context.Context, req *pushapi.NotifyRequest)
 	}
 	return &pushapi.Ok{}, nil
 }
+
+func (h *handler) NotifyPeer(ctx context.Context, req *pushapi.NotifyPeerRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.notifyPeer",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.NotifyPeer(ctx, req); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
+func (h *handler) ListDevices(ctx context.Context, req *pushapi.ListDevicesRequest) (resp *pushapi.ListDevicesResponse, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.listDevices",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	devices, err := h.p.ListDevices(ctx)
+	if err != nil {
+		return
+	}
+	return &pushapi.ListDevicesResponse{
+		Devices: devices,
+	}, nil
+}
+
+func (h *handler) RevokeDevice(ctx context.Context, req *pushapi.RevokeDeviceRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.revokeDevice",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.String("peerId", req.PeerId),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.RevokeDevice(ctx, req.PeerId); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
+func (h *handler) DeleteAccount(ctx context.Context, req *pushapi.DeleteAccountRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.deleteAccount",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.DeleteAccount(ctx); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
+func (h *handler) ExportAccountData(ctx context.Context, req *pushapi.ExportAccountDataRequest) (resp *pushapi.ExportAccountDataResponse, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.exportAccountData",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	return h.p.ExportAccountData(ctx, req.RevealTokens)
+}
+
+func (h *handler) Block(ctx context.Context, req *pushapi.BlockRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.block",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.Block(ctx, req.AccountId); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
+func (h *handler) Unblock(ctx context.Context, req *pushapi.UnblockRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.unblock",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.Unblock(ctx, req.AccountId); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
+
+func (h *handler) ListBlocked(ctx context.Context, req *pushapi.ListBlockedRequest) (resp *pushapi.ListBlockedResponse, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.listBlocked",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.Error(err),
+		)
+	}()
+	accountIds, err := h.p.ListBlocked(ctx)
+	if err != nil {
+		return
+	}
+	return &pushapi.ListBlockedResponse{
+		AccountIds: accountIds,
+	}, nil
+}
+
+func (h *handler) ReportNotification(ctx context.Context, req *pushapi.ReportNotificationRequest) (resp *pushapi.Ok, err error) {
+	st := time.Now()
+	defer func() {
+		h.p.metric.RequestLog(ctx, "push.reportNotification",
+			metric.TotalDur(time.Since(st)),
+			zap.String("addr", peer.CtxPeerAddr(ctx)),
+			zap.String("groupId", req.GroupId),
+			zap.Error(err),
+		)
+	}()
+	if err = h.p.ReportNotification(ctx, req); err != nil {
+		return
+	}
+	return &pushapi.Ok{}, nil
+}
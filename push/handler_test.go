@@ -2,14 +2,18 @@ package push
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/anyproto/any-sync/app"
 	"github.com/anyproto/any-sync/metric"
 	"github.com/anyproto/any-sync/net/peer"
+	"github.com/anyproto/any-sync/net/rpc/rpcerr"
 	"github.com/anyproto/any-sync/net/rpc/rpctest"
 	"github.com/anyproto/any-sync/util/crypto"
 	"github.com/mr-tron/base58"
@@ -17,18 +21,34 @@ import (
 	"github.com/anyproto/any-sync/testutil/accounttest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/mock/gomock"
+	"storj.io/drpc/drpcerr"
 
+	"github.com/anyproto/anytype-push-server/accountdata"
+	"github.com/anyproto/anytype-push-server/accountdata/mock_accountdata"
+	"github.com/anyproto/anytype-push-server/db"
+	"github.com/anyproto/anytype-push-server/db/mock_db"
 	"github.com/anyproto/anytype-push-server/domain"
 	"github.com/anyproto/anytype-push-server/pushclient/pushapi"
 	"github.com/anyproto/anytype-push-server/queue"
 	"github.com/anyproto/anytype-push-server/queue/mock_queue"
+	"github.com/anyproto/anytype-push-server/ratelimit"
+	"github.com/anyproto/anytype-push-server/ratelimit/mock_ratelimit"
+	"github.com/anyproto/anytype-push-server/replay"
+	"github.com/anyproto/anytype-push-server/replay/mock_replay"
 	"github.com/anyproto/anytype-push-server/repo/accountrepo"
 	"github.com/anyproto/anytype-push-server/repo/accountrepo/mock_accountrepo"
+	"github.com/anyproto/anytype-push-server/repo/banrepo"
+	"github.com/anyproto/anytype-push-server/repo/banrepo/mock_banrepo"
+	"github.com/anyproto/anytype-push-server/repo/reportrepo"
+	"github.com/anyproto/anytype-push-server/repo/reportrepo/mock_reportrepo"
 	"github.com/anyproto/anytype-push-server/repo/spacerepo"
 	"github.com/anyproto/anytype-push-server/repo/spacerepo/mock_spacerepo"
 	"github.com/anyproto/anytype-push-server/repo/tokenrepo"
 	"github.com/anyproto/anytype-push-server/repo/tokenrepo/mock_tokenrepo"
+	"github.com/anyproto/anytype-push-server/sender"
+	"github.com/anyproto/anytype-push-server/sender/mock_sender"
 )
 
 var ctx = context.Background()
@@ -42,6 +62,7 @@ func TestHandler_SetToken(t *testing.T) {
 		accKey, _ := acc.GetPublic().Marshall()
 		pCtx = peer.CtxWithIdentity(pCtx, accKey)
 
+		fx.sender.EXPECT().ValidateToken(pCtx, domain.PlatformAndroid, domain.ApnsEnvironmentProduction, token).Return(nil)
 		fx.tokenRepo.EXPECT().AddToken(pCtx, domain.Token{
 			Id:        token,
 			AccountId: acc.GetPublic().Account(),
@@ -57,205 +78,1803 @@ func TestHandler_SetToken(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
+	t.Run("client metadata", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := peer.CtxWithPeerId(ctx, "p1")
+		accKey, _ := acc.GetPublic().Marshall()
+		pCtx = peer.CtxWithIdentity(pCtx, accKey)
+
+		fx.sender.EXPECT().ValidateToken(pCtx, domain.PlatformIOS, domain.ApnsEnvironmentSandbox, "token").Return(nil)
+		fx.tokenRepo.EXPECT().AddToken(pCtx, domain.Token{
+			Id:              "token",
+			AccountId:       acc.GetPublic().Account(),
+			PeerId:          "p1",
+			Platform:        domain.PlatformIOS,
+			Status:          domain.TokenStatusValid,
+			AppVersion:      "0.41.0-beta",
+			OsVersion:       "18.1",
+			BuildChannel:    domain.BuildChannelBeta,
+			ApnsEnvironment: domain.ApnsEnvironmentSandbox,
+		}).Return(nil)
+
+		_, err := fx.handler.SetToken(pCtx, &pushapi.SetTokenRequest{
+			Platform:        pushapi.Platform_IOS,
+			Token:           "token",
+			AppVersion:      "0.41.0-beta",
+			OsVersion:       "18.1",
+			BuildChannel:    pushapi.BuildChannel_Beta,
+			ApnsEnvironment: pushapi.ApnsEnvironment_Sandbox,
+		})
+		require.NoError(t, err)
+	})
+	t.Run("invalid token", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := peer.CtxWithPeerId(ctx, "p1")
+		accKey, _ := acc.GetPublic().Marshall()
+		pCtx = peer.CtxWithIdentity(pCtx, accKey)
+
+		fx.sender.EXPECT().ValidateToken(pCtx, domain.PlatformAndroid, domain.ApnsEnvironmentProduction, "garbage").Return(sender.ErrInvalidToken)
+
+		_, err := fx.handler.SetToken(pCtx, &pushapi.SetTokenRequest{
+			Platform: pushapi.Platform_Android,
+			Token:    "garbage",
+		})
+		assert.ErrorIs(t, err, pushapi.ErrInvalidToken)
+	})
+	t.Run("invalid client metadata", func(t *testing.T) {
+		for name, req := range map[string]*pushapi.SetTokenRequest{
+			"long app version": {Token: "token", AppVersion: strings.Repeat("1", maxClientVersionLength+1)},
+			"long os version":  {Token: "token", OsVersion: strings.Repeat("1", maxClientVersionLength+1)},
+			"build channel":    {Token: "token", BuildChannel: 100},
+			"apns environment": {Token: "token", ApnsEnvironment: 100},
+		} {
+			t.Run(name, func(t *testing.T) {
+				fx := newFixture(t)
+				acc := newAccount()
+				pCtx := peer.CtxWithPeerId(ctx, "p1")
+				accKey, _ := acc.GetPublic().Marshall()
+				pCtx = peer.CtxWithIdentity(pCtx, accKey)
+
+				_, err := fx.handler.SetToken(pCtx, req)
+				require.Error(t, err)
+			})
+		}
+	})
+	t.Run("empty token", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := peer.CtxWithPeerId(ctx, "p1")
+		accKey, _ := acc.GetPublic().Marshall()
+		pCtx = peer.CtxWithIdentity(pCtx, accKey)
+
+		_, err := fx.handler.SetToken(pCtx, &pushapi.SetTokenRequest{
+			Platform: pushapi.Platform_Android,
+		})
+		assert.ErrorIs(t, err, pushapi.ErrInvalidToken)
+	})
+	t.Run("provider unavailable", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := peer.CtxWithPeerId(ctx, "p1")
+		accKey, _ := acc.GetPublic().Marshall()
+		pCtx = peer.CtxWithIdentity(pCtx, accKey)
+
+		fx.sender.EXPECT().ValidateToken(pCtx, domain.PlatformAndroid, domain.ApnsEnvironmentProduction, "token").Return(errors.New("unavailable"))
+		fx.tokenRepo.EXPECT().AddToken(pCtx, gomock.Any()).Return(nil)
+
+		_, err := fx.handler.SetToken(pCtx, &pushapi.SetTokenRequest{
+			Platform: pushapi.Platform_Android,
+			Token:    "token",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestHandler_ListDevices(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.tokenRepo.EXPECT().GetTokensByAccountId(pCtx, acc.GetPublic().Account()).Return([]domain.Token{
+			{
+				Id:         "t1",
+				AccountId:  acc.GetPublic().Account(),
+				PeerId:     "p1",
+				Platform:   domain.PlatformIOS,
+				Status:     domain.TokenStatusValid,
+				AppVersion: "0.40.0",
+				Created:    1,
+				Updated:    2,
+			},
+			{
+				Id:        "t2",
+				AccountId: acc.GetPublic().Account(),
+				PeerId:    "p2",
+				Platform:  domain.PlatformAndroid,
+				Status:    domain.TokenStatusInvalid,
+			},
+		}, nil)
+
+		resp, err := fx.handler.ListDevices(pCtx, &pushapi.ListDevicesRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Devices, 2)
+		assert.Equal(t, &pushapi.Device{
+			PeerId:     "p1",
+			Platform:   pushapi.Platform_IOS,
+			Created:    1,
+			Updated:    2,
+			AppVersion: "0.40.0",
+			Status:     pushapi.TokenStatus_Valid,
+		}, resp.Devices[0])
+		assert.Equal(t, "p2", resp.Devices[1].PeerId)
+		assert.Equal(t, pushapi.TokenStatus_Invalid, resp.Devices[1].Status)
+	})
+}
+
+func TestHandler_RevokeDevice(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(peer.CtxWithPeerId(ctx, "p1"), ak)
+
+		fx.tokenRepo.EXPECT().RemoveDeviceTokens(pCtx, acc.GetPublic().Account(), "p2").Return(nil)
+
+		resp, err := fx.handler.RevokeDevice(pCtx, &pushapi.RevokeDeviceRequest{PeerId: "p2"})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("not found", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.tokenRepo.EXPECT().RemoveDeviceTokens(pCtx, acc.GetPublic().Account(), "p2").Return(tokenrepo.ErrTokenNotFound)
+
+		resp, err := fx.handler.RevokeDevice(pCtx, &pushapi.RevokeDeviceRequest{PeerId: "p2"})
+		require.ErrorIs(t, err, pushapi.ErrDeviceNotFound)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestHandler_Block(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+		blocked := newAccount().GetPublic().Account()
+
+		fx.accountRepo.EXPECT().BlockAccount(pCtx, acc.GetPublic().Account(), blocked).Return(nil)
+
+		resp, err := fx.handler.Block(pCtx, &pushapi.BlockRequest{AccountId: blocked})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("invalid account id", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		resp, err := fx.handler.Block(pCtx, &pushapi.BlockRequest{AccountId: "invalid"})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+	t.Run("own account", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		resp, err := fx.handler.Block(pCtx, &pushapi.BlockRequest{AccountId: acc.GetPublic().Account()})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestHandler_Unblock(t *testing.T) {
+	fx := newFixture(t)
+	acc := newAccount()
+	ak, _ := acc.GetPublic().Marshall()
+	pCtx := peer.CtxWithIdentity(ctx, ak)
+
+	fx.accountRepo.EXPECT().UnblockAccount(pCtx, acc.GetPublic().Account(), "blocked").Return(nil)
+
+	resp, err := fx.handler.Unblock(pCtx, &pushapi.UnblockRequest{AccountId: "blocked"})
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
+func TestHandler_ListBlocked(t *testing.T) {
+	fx := newFixture(t)
+	acc := newAccount()
+	ak, _ := acc.GetPublic().Marshall()
+	pCtx := peer.CtxWithIdentity(ctx, ak)
+
+	fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{
+		Id:      acc.GetPublic().Account(),
+		Blocked: []string{"b1", "b2"},
+	}, nil)
+
+	resp, err := fx.handler.ListBlocked(pCtx, &pushapi.ListBlockedRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b1", "b2"}, resp.AccountIds)
+}
+
+func TestHandler_DeleteAccount(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountData.EXPECT().Delete(pCtx, acc.GetPublic().Account()).Return(nil)
+
+		resp, err := fx.handler.DeleteAccount(pCtx, &pushapi.DeleteAccountRequest{})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("error", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountData.EXPECT().Delete(pCtx, acc.GetPublic().Account()).Return(errors.New("tx error"))
+
+		resp, err := fx.handler.DeleteAccount(pCtx, &pushapi.DeleteAccountRequest{})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestHandler_ExportAccountData(t *testing.T) {
+	fx := newFixture(t)
+	acc := newAccount()
+	ak, _ := acc.GetPublic().Marshall()
+	pCtx := peer.CtxWithIdentity(ctx, ak)
+
+	export := &accountdata.Export{
+		Version:   accountdata.ExportVersion,
+		AccountId: acc.GetPublic().Account(),
+		Topics:    []string{"s1/t1"},
+	}
+	fx.accountData.EXPECT().Export(pCtx, acc.GetPublic().Account(), true).Return(export, nil)
+
+	resp, err := fx.handler.ExportAccountData(pCtx, &pushapi.ExportAccountDataRequest{RevealTokens: true})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(accountdata.ExportVersion), resp.Version)
+
+	var decoded accountdata.Export
+	require.NoError(t, json.Unmarshal(resp.Data, &decoded))
+	assert.Equal(t, *export, decoded)
+}
+
+func TestHandler_SubscribeAll(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		var rawTopics = &pushapi.Topics{}
+		var topics []domain.Topic
+		for i := range 2 {
+			rawTopic := newTopic(fmt.Sprintf("topic%d", i))
+			rawTopics.Topics = append(rawTopics.Topics, rawTopic)
+			topics = append(topics, domain.Topic(base58.Encode(rawTopic.SpaceKey)+"/"+rawTopic.Topic))
+		}
+
+		req := &pushapi.SubscribeAllRequest{
+			Topics: rawTopics,
+		}
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().SetAccountTopics(pCtx, acc.GetPublic().Account(), topics).Return(nil)
+
+		resp, err := fx.handler.SubscribeAll(pCtx, req)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("expected version", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		rawTopic := newTopic("topic")
+		version := int64(2)
+		req := &pushapi.SubscribeAllRequest{
+			Topics:          &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}},
+			ExpectedVersion: &version,
+		}
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		topics := []domain.Topic{domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)}
+		fx.accountRepo.EXPECT().SetAccountTopicsIfVersion(pCtx, acc.GetPublic().Account(), topics, version).Return(nil)
+		_, err := fx.handler.SubscribeAll(pCtx, req)
+		require.NoError(t, err)
+
+		fx.accountRepo.EXPECT().SetAccountTopicsIfVersion(pCtx, acc.GetPublic().Account(), topics, version).Return(accountrepo.ErrVersionConflict)
+		_, err = fx.handler.SubscribeAll(pCtx, req)
+		assert.ErrorIs(t, err, pushapi.ErrVersionConflict)
+	})
+	t.Run("expiring", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		rawTopic := newTopic("topic")
+		rawTopic.Expires = time.Now().Add(time.Hour).Unix()
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		_, err := fx.handler.SubscribeAll(pCtx, &pushapi.SubscribeAllRequest{Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}}})
+		require.Error(t, err)
+	})
+}
+
+func TestHandler_Subscriptions(t *testing.T) {
+	fx := newFixture(t)
+	acc := newAccount()
+	ak, _ := acc.GetPublic().Marshall()
+	pCtx := peer.CtxWithIdentity(ctx, ak)
+	rawTopic := newTopic("topic")
+
+	fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{
+		Topics:  []domain.Topic{domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)},
+		Version: 3,
+	}, nil)
+
+	resp, err := fx.handler.Subscriptions(pCtx, &pushapi.SubscriptionsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Version)
+	require.Len(t, resp.Topics.Topics, 1)
+	assert.Equal(t, rawTopic.SpaceKey, resp.Topics.Topics[0].SpaceKey)
+	assert.Equal(t, rawTopic.Topic, resp.Topics.Topics[0].Topic)
+
+	t.Run("expiring", func(t *testing.T) {
+		temporary := domain.NewTopic(newTopic("temporary").SpaceKey, "temporary")
+		expired := domain.NewTopic(newTopic("expired").SpaceKey, "expired")
+		expires := time.Now().Add(time.Hour).Unix()
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{
+			Topics: []domain.Topic{temporary, expired},
+			Expiring: []domain.TopicExpiry{
+				{Topic: temporary, Expires: expires},
+				{Topic: expired, Expires: 1},
+			},
+		}, nil)
+
+		resp, err := fx.handler.Subscriptions(pCtx, &pushapi.SubscriptionsRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Topics.Topics, 1)
+		assert.Equal(t, "temporary", resp.Topics.Topics[0].Topic)
+		assert.Equal(t, expires, resp.Topics.Topics[0].Expires)
+	})
+}
+
+func TestHandler_SyncSubscriptions(t *testing.T) {
+	var topics = make([]domain.Topic, 3)
+	for i := range topics {
+		rawTopic := newTopic(fmt.Sprintf("topic%d", i))
+		topics[i] = domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+	}
+	apiTopics := func(topics ...domain.Topic) *pushapi.Topics {
+		res, err := convertToApiTopics(topics, nil)
+		require.NoError(t, err)
+		return res
+	}
+	account := domain.Account{
+		Topics:  []domain.Topic{topics[1], topics[2]},
+		Version: 5,
+		Changes: []domain.TopicsChange{
+			// version 3
+			{Added: []domain.Topic{topics[0], topics[1]}},
+			// version 4
+			{Added: []domain.Topic{topics[2]}},
+			// version 5
+			{Removed: []domain.Topic{topics[0]}},
+		},
+	}
+	sync := func(t *testing.T, account domain.Account, version int64) *pushapi.SyncSubscriptionsResponse {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(account, nil)
+		resp, err := fx.handler.SyncSubscriptions(pCtx, &pushapi.SyncSubscriptionsRequest{Version: version})
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("up to date", func(t *testing.T) {
+		resp := sync(t, account, 5)
+		assert.Equal(t, &pushapi.SyncSubscriptionsResponse{Version: 5, Added: apiTopics(), Removed: apiTopics()}, resp)
+	})
+	t.Run("changes", func(t *testing.T) {
+		resp := sync(t, account, 3)
+		assert.Equal(t, &pushapi.SyncSubscriptionsResponse{
+			Version: 5,
+			Added:   apiTopics(topics[2]),
+			Removed: apiTopics(topics[0]),
+		}, resp)
+	})
+	t.Run("changes from the log start", func(t *testing.T) {
+		resp := sync(t, account, 2)
+		assert.Equal(t, &pushapi.SyncSubscriptionsResponse{
+			Version: 5,
+			Added:   apiTopics(topics[1], topics[2]),
+			Removed: apiTopics(topics[0]),
+		}, resp)
+	})
+	t.Run("version is out of the log", func(t *testing.T) {
+		resp := sync(t, account, 1)
+		assert.Equal(t, &pushapi.SyncSubscriptionsResponse{
+			Version: 5,
+			Full:    true,
+			Added:   apiTopics(topics[1], topics[2]),
+			Removed: apiTopics(),
+		}, resp)
+	})
+	t.Run("expired topics", func(t *testing.T) {
+		expiredAccount := account
+		expiredAccount.Expiring = []domain.TopicExpiry{
+			{Topic: topics[1], Expires: time.Now().Add(-time.Minute).Unix()},
+			{Topic: topics[2], Expires: time.Now().Add(time.Hour).Unix()},
+		}
+		resp := sync(t, expiredAccount, 2)
+		added, err := convertToApiTopics([]domain.Topic{topics[2]}, map[domain.Topic]int64{topics[2]: expiredAccount.Expiring[1].Expires})
+		require.NoError(t, err)
+		assert.Equal(t, &pushapi.SyncSubscriptionsResponse{
+			Version: 5,
+			Added:   added,
+			Removed: apiTopics(topics[0], topics[1]),
+		}, resp)
+	})
+	t.Run("legacy account", func(t *testing.T) {
+		// topics were stored before versioning
+		legacyAccount := domain.Account{Topics: []domain.Topic{topics[0], topics[1]}}
+		resp := sync(t, legacyAccount, 0)
+		assert.Equal(t, &pushapi.SyncSubscriptionsResponse{
+			Full:    true,
+			Added:   apiTopics(topics[0], topics[1]),
+			Removed: apiTopics(),
+		}, resp)
+
+		// the log starts after the topics stored before versioning
+		legacyAccount.Topics = append(legacyAccount.Topics, topics[2])
+		legacyAccount.Version = 1
+		legacyAccount.Changes = []domain.TopicsChange{{Added: []domain.Topic{topics[2]}}}
+		resp = sync(t, legacyAccount, 0)
+		assert.True(t, resp.Full)
+		assert.Equal(t, apiTopics(topics[0], topics[1], topics[2]), resp.Added)
+	})
+	t.Run("reset", func(t *testing.T) {
+		resetAccount := account
+		resetAccount.Version = 6
+		resetAccount.Changes = append(slices.Clone(account.Changes), domain.TopicsChange{Reset: true})
+		resp := sync(t, resetAccount, 4)
+		assert.True(t, resp.Full)
+		assert.Equal(t, int64(6), resp.Version)
+		assert.Equal(t, apiTopics(topics[1], topics[2]), resp.Added)
+	})
+}
+
+func TestHandler_RequestLimits(t *testing.T) {
+	newCtx := func() context.Context {
+		ak, _ := newAccount().GetPublic().Marshall()
+		return peer.CtxWithIdentity(ctx, ak)
+	}
+	unsignedTopics := func(names ...string) *pushapi.Topics {
+		topics := &pushapi.Topics{}
+		for _, name := range names {
+			topic := newTopic(name)
+			topic.Signature = nil
+			topics.Topics = append(topics.Topics, topic)
+		}
+		return topics
+	}
+	t.Run("too many topics in request", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.Limits.MaxTopicsPerRequest = 1
+		topics := unsignedTopics("t1", "t2")
+
+		_, err := fx.handler.Subscribe(newCtx(), &pushapi.SubscribeRequest{Topics: topics})
+		assert.ErrorIs(t, err, pushapi.ErrTooManyTopicsInRequest)
+		_, err = fx.handler.Unsubscribe(newCtx(), &pushapi.UnsubscribeRequest{Topics: topics})
+		assert.ErrorIs(t, err, pushapi.ErrTooManyTopicsInRequest)
+		_, err = fx.handler.SubscribeAll(newCtx(), &pushapi.SubscribeAllRequest{Topics: topics})
+		assert.ErrorIs(t, err, pushapi.ErrTooManyTopicsInRequest)
+		_, err = fx.handler.Notify(newCtx(), &pushapi.NotifyRequest{Topics: topics})
+		assert.ErrorIs(t, err, pushapi.ErrTooManyTopicsInRequest)
+	})
+	t.Run("topic too long", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.Limits.MaxTopicLength = 3
+		topics := unsignedTopics("long")
+
+		_, err := fx.handler.Subscribe(newCtx(), &pushapi.SubscribeRequest{Topics: topics})
+		assert.ErrorIs(t, err, pushapi.ErrTopicTooLong)
+		_, err = fx.handler.NotifySilent(newCtx(), &pushapi.NotifyRequest{Topics: topics})
+		assert.ErrorIs(t, err, pushapi.ErrTopicTooLong)
+	})
+	t.Run("subscribe all", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.Limits.MaxTopicsPerAccount = 1
+		_, err := fx.handler.SubscribeAll(newCtx(), &pushapi.SubscribeAllRequest{Topics: unsignedTopics("t1", "t2")})
+		assert.ErrorIs(t, err, pushapi.ErrTooManyTopics)
+
+		fx.conf.Limits.MaxTopicsPerAccount = 10
+		fx.conf.Limits.MaxSpacesPerAccount = 1
+		_, err = fx.handler.SubscribeAll(newCtx(), &pushapi.SubscribeAllRequest{Topics: unsignedTopics("t1", "t2")})
+		assert.ErrorIs(t, err, pushapi.ErrTooManySpaces)
+	})
+}
+
+func TestHandler_CreateSpace(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().Create(pCtx, domain.Space{
+			Id:     base58.Encode(spaceKey),
+			Author: acc.GetPublic().Account(),
+		}).Return(nil)
+
+		resp, err := fx.handler.CreateSpace(pCtx, &pushapi.CreateSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("one to one", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().AddOneToOneMember(pCtx, base58.Encode(spaceKey), acc.GetPublic().Account()).Return(nil)
+
+		resp, err := fx.handler.CreateSpace(pCtx, &pushapi.CreateSpaceRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			SpaceType:        pushapi.SpaceType_OneToOne,
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("space exists", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().Create(pCtx, gomock.Any()).Return(spacerepo.ErrSpaceExists)
+
+		resp, err := fx.handler.CreateSpace(pCtx, &pushapi.CreateSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.ErrorIs(t, err, pushapi.ErrSpaceExists)
+		assert.Nil(t, resp)
+	})
+	t.Run("one to one space is full", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().AddOneToOneMember(pCtx, base58.Encode(spaceKey), acc.GetPublic().Account()).Return(spacerepo.ErrSpaceExists)
+
+		resp, err := fx.handler.CreateSpace(pCtx, &pushapi.CreateSpaceRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			SpaceType:        pushapi.SpaceType_OneToOne,
+		})
+		require.ErrorIs(t, err, pushapi.ErrSpaceExists)
+		assert.Nil(t, resp)
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, _ := newSpaceKey(acc)
+		_, signature := newSpaceKey(acc)
+
+		resp, err := fx.handler.CreateSpace(pCtx, &pushapi.CreateSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.ErrorIs(t, err, pushapi.ErrInvalidSignature)
+		assert.Nil(t, resp)
+	})
+	t.Run("invalid space key", func(t *testing.T) {
+		fx := newFixture(t)
+		pCtx := newAccountCtx(newAccount())
+
+		resp, err := fx.handler.CreateSpace(pCtx, &pushapi.CreateSpaceRequest{SpaceKey: []byte{1, 2, 3}})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+	t.Run("repo error", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+		repoErr := errors.New("mongo error")
+
+		fx.spaceRepo.EXPECT().Create(pCtx, gomock.Any()).Return(repoErr)
+
+		resp, err := fx.handler.CreateSpace(pCtx, &pushapi.CreateSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.ErrorIs(t, err, repoErr)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestHandler_RemoveSpace(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().Remove(gomock.Any(), domain.Space{
+			Id:     base58.Encode(spaceKey),
+			Author: acc.GetPublic().Account(),
+		}).Return(nil)
+		fx.accountRepo.EXPECT().RemoveSpaceTopics(gomock.Any(), base58.Encode(spaceKey)).Return(nil)
+
+		resp, err := fx.handler.RemoveSpace(pCtx, &pushapi.RemoveSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("not found", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().Remove(gomock.Any(), gomock.Any()).Return(spacerepo.ErrSpaceNotFound)
+
+		resp, err := fx.handler.RemoveSpace(pCtx, &pushapi.RemoveSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.ErrorIs(t, err, pushapi.ErrSpaceNotFound)
+		assert.Nil(t, resp)
+	})
+	t.Run("not author", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().Remove(gomock.Any(), gomock.Any()).Return(spacerepo.ErrNotSpaceAdmin)
+
+		resp, err := fx.handler.RemoveSpace(pCtx, &pushapi.RemoveSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.ErrorIs(t, err, pushapi.ErrNotSpaceAdmin)
+		assert.Nil(t, resp)
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, _ := newSpaceKey(acc)
+		_, signature := newSpaceKey(acc)
+
+		resp, err := fx.handler.RemoveSpace(pCtx, &pushapi.RemoveSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.ErrorIs(t, err, pushapi.ErrInvalidSignature)
+		assert.Nil(t, resp)
+	})
+	t.Run("invalid space key", func(t *testing.T) {
+		fx := newFixture(t)
+		pCtx := newAccountCtx(newAccount())
+
+		resp, err := fx.handler.RemoveSpace(pCtx, &pushapi.RemoveSpaceRequest{SpaceKey: []byte{1, 2, 3}})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+	t.Run("topics removal error", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+		repoErr := errors.New("mongo error")
+
+		fx.spaceRepo.EXPECT().Remove(gomock.Any(), gomock.Any()).Return(nil)
+		fx.accountRepo.EXPECT().RemoveSpaceTopics(gomock.Any(), base58.Encode(spaceKey)).Return(repoErr)
+
+		resp, err := fx.handler.RemoveSpace(pCtx, &pushapi.RemoveSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.ErrorIs(t, err, repoErr)
+		assert.Nil(t, resp)
+	})
+	t.Run("repo error", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		pCtx := newAccountCtx(acc)
+		spaceKey, signature := newSpaceKey(acc)
+		repoErr := errors.New("mongo error")
+
+		fx.spaceRepo.EXPECT().Remove(gomock.Any(), gomock.Any()).Return(repoErr)
+
+		resp, err := fx.handler.RemoveSpace(pCtx, &pushapi.RemoveSpaceRequest{SpaceKey: spaceKey, AccountSignature: signature})
+		require.ErrorIs(t, err, repoErr)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestHandler_RotateSpaceKey(t *testing.T) {
+	newRequest := func(acc crypto.PrivKey) *pushapi.RotateSpaceKeyRequest {
+		spaceKey, signature := newSpaceKey(acc)
+		newKey, newSignature := newSpaceKey(acc)
+		return &pushapi.RotateSpaceKeyRequest{
+			SpaceKey:            spaceKey,
+			AccountSignature:    signature,
+			NewSpaceKey:         newKey,
+			NewAccountSignature: newSignature,
+		}
+	}
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		req := newRequest(acc)
+
+		fx.spaceRepo.EXPECT().Rekey(gomock.Any(), domain.Space{
+			Id:     base58.Encode(req.SpaceKey),
+			Author: acc.GetPublic().Account(),
+		}, base58.Encode(req.NewSpaceKey)).Return(nil)
+		fx.accountRepo.EXPECT().RenameSpaceTopics(gomock.Any(), base58.Encode(req.SpaceKey), base58.Encode(req.NewSpaceKey)).Return(nil)
+
+		resp, err := fx.handler.RotateSpaceKey(newAccountCtx(acc), req)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("invalid old key signature", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		req := newRequest(acc)
+		req.AccountSignature = req.NewAccountSignature
+
+		resp, err := fx.handler.RotateSpaceKey(newAccountCtx(acc), req)
+		require.ErrorIs(t, err, pushapi.ErrInvalidSignature)
+		assert.Nil(t, resp)
+	})
+	t.Run("invalid new key signature", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		req := newRequest(acc)
+		req.NewAccountSignature = req.AccountSignature
+
+		resp, err := fx.handler.RotateSpaceKey(newAccountCtx(acc), req)
+		require.ErrorIs(t, err, pushapi.ErrInvalidSignature)
+		assert.Nil(t, resp)
+	})
+	t.Run("not found", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+
+		fx.spaceRepo.EXPECT().Rekey(gomock.Any(), gomock.Any(), gomock.Any()).Return(spacerepo.ErrSpaceNotFound)
+
+		resp, err := fx.handler.RotateSpaceKey(newAccountCtx(acc), newRequest(acc))
+		require.ErrorIs(t, err, pushapi.ErrSpaceNotFound)
+		assert.Nil(t, resp)
+	})
+	t.Run("not author", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+
+		fx.spaceRepo.EXPECT().Rekey(gomock.Any(), gomock.Any(), gomock.Any()).Return(spacerepo.ErrNotSpaceAdmin)
+
+		resp, err := fx.handler.RotateSpaceKey(newAccountCtx(acc), newRequest(acc))
+		require.ErrorIs(t, err, pushapi.ErrNotSpaceAdmin)
+		assert.Nil(t, resp)
+	})
+	t.Run("new key exists", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+
+		fx.spaceRepo.EXPECT().Rekey(gomock.Any(), gomock.Any(), gomock.Any()).Return(spacerepo.ErrSpaceExists)
+
+		resp, err := fx.handler.RotateSpaceKey(newAccountCtx(acc), newRequest(acc))
+		require.ErrorIs(t, err, pushapi.ErrSpaceExists)
+		assert.Nil(t, resp)
+	})
+	t.Run("topics rename error", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		repoErr := errors.New("mongo error")
+
+		fx.spaceRepo.EXPECT().Rekey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		fx.accountRepo.EXPECT().RenameSpaceTopics(gomock.Any(), gomock.Any(), gomock.Any()).Return(repoErr)
+
+		resp, err := fx.handler.RotateSpaceKey(newAccountCtx(acc), newRequest(acc))
+		require.ErrorIs(t, err, repoErr)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestHandler_TransferSpace(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		newAuthor := newAccount().GetPublic().Account()
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().Transfer(gomock.Any(), domain.Space{
+			Id:     base58.Encode(spaceKey),
+			Author: acc.GetPublic().Account(),
+		}, newAuthor).Return(nil)
+
+		resp, err := fx.handler.TransferSpace(newAccountCtx(acc), &pushapi.TransferSpaceRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			AccountId:        newAuthor,
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("invalid accountId", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spaceKey, signature := newSpaceKey(acc)
+
+		resp, err := fx.handler.TransferSpace(newAccountCtx(acc), &pushapi.TransferSpaceRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			AccountId:        "invalid",
+		})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spaceKey, _ := newSpaceKey(acc)
+		_, signature := newSpaceKey(acc)
+
+		resp, err := fx.handler.TransferSpace(newAccountCtx(acc), &pushapi.TransferSpaceRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			AccountId:        newAccount().GetPublic().Account(),
+		})
+		require.ErrorIs(t, err, pushapi.ErrInvalidSignature)
+		assert.Nil(t, resp)
+	})
+	t.Run("not admin", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any()).Return(spacerepo.ErrNotSpaceAdmin)
+
+		resp, err := fx.handler.TransferSpace(newAccountCtx(acc), &pushapi.TransferSpaceRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			AccountId:        newAccount().GetPublic().Account(),
+		})
+		require.ErrorIs(t, err, pushapi.ErrNotSpaceAdmin)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestHandler_AddSpaceAdmin(t *testing.T) {
+	fx := newFixture(t)
+	acc := newAccount()
+	admin := newAccount().GetPublic().Account()
+	spaceKey, signature := newSpaceKey(acc)
+
+	fx.spaceRepo.EXPECT().AddAdmin(gomock.Any(), domain.Space{
+		Id:     base58.Encode(spaceKey),
+		Author: acc.GetPublic().Account(),
+	}, admin).Return(nil)
+
+	resp, err := fx.handler.AddSpaceAdmin(newAccountCtx(acc), &pushapi.AddSpaceAdminRequest{
+		SpaceKey:         spaceKey,
+		AccountSignature: signature,
+		AccountId:        admin,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
+func TestHandler_RemoveSpaceAdmin(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		admin := newAccount().GetPublic().Account()
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().RemoveAdmin(gomock.Any(), domain.Space{
+			Id:     base58.Encode(spaceKey),
+			Author: acc.GetPublic().Account(),
+		}, admin).Return(nil)
+
+		resp, err := fx.handler.RemoveSpaceAdmin(newAccountCtx(acc), &pushapi.RemoveSpaceAdminRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			AccountId:        admin,
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("last admin", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().RemoveAdmin(gomock.Any(), gomock.Any(), gomock.Any()).Return(spacerepo.ErrLastSpaceAdmin)
+
+		resp, err := fx.handler.RemoveSpaceAdmin(newAccountCtx(acc), &pushapi.RemoveSpaceAdminRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			AccountId:        acc.GetPublic().Account(),
+		})
+		require.ErrorIs(t, err, pushapi.ErrLastSpaceAdmin)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestHandler_GetSpacePolicy(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().Get(gomock.Any(), base58.Encode(spaceKey)).Return(domain.Space{
+			Policy: domain.SpacePolicy{Mode: domain.SpacePolicyMentionsOnly, MaxFanOutPerMinute: 100},
+		}, nil)
+
+		resp, err := fx.handler.GetSpacePolicy(newAccountCtx(acc), &pushapi.GetSpacePolicyRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &pushapi.SpacePolicy{Mode: pushapi.SpacePolicyMode_MentionsOnly, MaxFanOutPerMinute: 100}, resp.Policy)
+	})
+	t.Run("not found", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(domain.Space{}, spacerepo.ErrSpaceNotFound)
+
+		resp, err := fx.handler.GetSpacePolicy(newAccountCtx(acc), &pushapi.GetSpacePolicyRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+		})
+		require.ErrorIs(t, err, pushapi.ErrSpaceNotFound)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestHandler_SetSpacePolicy(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().SetPolicy(gomock.Any(), domain.Space{
+			Id:     base58.Encode(spaceKey),
+			Author: acc.GetPublic().Account(),
+		}, domain.SpacePolicy{Mode: domain.SpacePolicyDisabled}).Return(nil)
+
+		resp, err := fx.handler.SetSpacePolicy(newAccountCtx(acc), &pushapi.SetSpacePolicyRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			Policy:           &pushapi.SpacePolicy{Mode: pushapi.SpacePolicyMode_NoNotifications},
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("unexpected mode", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spaceKey, signature := newSpaceKey(acc)
+
+		resp, err := fx.handler.SetSpacePolicy(newAccountCtx(acc), &pushapi.SetSpacePolicyRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			Policy:           &pushapi.SpacePolicy{Mode: 100},
+		})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+	t.Run("not admin", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spaceKey, signature := newSpaceKey(acc)
+
+		fx.spaceRepo.EXPECT().SetPolicy(gomock.Any(), gomock.Any(), gomock.Any()).Return(spacerepo.ErrNotSpaceAdmin)
+
+		resp, err := fx.handler.SetSpacePolicy(newAccountCtx(acc), &pushapi.SetSpacePolicyRequest{
+			SpaceKey:         spaceKey,
+			AccountSignature: signature,
+			Policy:           &pushapi.SpacePolicy{},
+		})
+		require.ErrorIs(t, err, pushapi.ErrNotSpaceAdmin)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestPush_VerifySpace(t *testing.T) {
+	fx := newFixture(t)
+	assert.False(t, fx.verifySpace("s1"))
+
+	fx.conf.SpaceVerification.Enabled = []string{"s1", "s2"}
+	fx.conf.SpaceVerification.Disabled = []string{"s2"}
+	assert.True(t, fx.verifySpace("s1"))
+	assert.False(t, fx.verifySpace("s2"))
+	assert.False(t, fx.verifySpace("s3"))
+
+	fx.conf.SpaceVerification.RolloutPercent = 100
+	assert.True(t, fx.verifySpace("s3"))
+	assert.False(t, fx.verifySpace("s2"))
+}
+
+func TestHandler_Subscribe(t *testing.T) {
+	t.Run("unregistered spaces are filtered", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.SpaceVerification.RolloutPercent = 100
+		acc := newAccount()
+		registered := newTopic("chat")
+		unregistered := newTopic("call")
+		unregistered.Expires = time.Now().Add(time.Hour).Unix()
+		registeredTopic := domain.NewTopic(registered.SpaceKey, registered.Topic)
+		unregisteredTopic := domain.NewTopic(unregistered.SpaceKey, unregistered.Topic)
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{}, nil)
+		fx.spaceRepo.EXPECT().
+			ExistedSpaces(pCtx, []string{registeredTopic.SpaceKeyBase58(), unregisteredTopic.SpaceKeyBase58()}).
+			Return([]string{registeredTopic.SpaceKeyBase58()}, nil)
+		fx.accountRepo.EXPECT().AddAccountTopics(pCtx, acc.GetPublic().Account(), []domain.Topic{registeredTopic}, nil).Return(nil)
+
+		_, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: &pushapi.Topics{Topics: []*pushapi.Topic{registered, unregistered}}})
+		require.NoError(t, err)
+	})
+	t.Run("no valid topics", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.SpaceVerification.RolloutPercent = 100
+		acc := newAccount()
+		rawTopic := newTopic("chat")
+		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{}, nil)
+		fx.spaceRepo.EXPECT().ExistedSpaces(pCtx, []string{topic.SpaceKeyBase58()}).Return(nil, nil)
+
+		_, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}}})
+		require.ErrorIs(t, err, pushapi.ErrNoValidTopics)
+	})
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+
+		var rawTopics = &pushapi.Topics{}
+		var topics []domain.Topic
+		for i := range 2 {
+			rawTopic := newTopic(fmt.Sprintf("topic%d", i))
+			rawTopics.Topics = append(rawTopics.Topics, rawTopic)
+			topics = append(topics, domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic))
+		}
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{}, nil)
+		fx.accountRepo.EXPECT().AddAccountTopics(pCtx, acc.GetPublic().Account(), topics, nil).Return(nil)
+
+		resp, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: rawTopics})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("wildcard", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		rawTopic := newTopic(domain.TopicWildcard)
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{}, nil)
+		fx.accountRepo.EXPECT().AddAccountTopics(pCtx, acc.GetPublic().Account(), []domain.Topic{
+			domain.NewTopic(rawTopic.SpaceKey, domain.TopicWildcard),
+		}, nil).Return(nil)
+
+		_, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}}})
+		require.NoError(t, err)
+	})
+	t.Run("expiring", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		permanent := newTopic("chat")
+		temporary := newTopic("call")
+		temporary.Expires = time.Now().Add(time.Hour).Unix()
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		temporaryTopic := domain.NewTopic(temporary.SpaceKey, temporary.Topic)
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{}, nil)
+		fx.accountRepo.EXPECT().AddAccountTopics(pCtx, acc.GetPublic().Account(), []domain.Topic{
+			domain.NewTopic(permanent.SpaceKey, permanent.Topic),
+			temporaryTopic,
+		}, []domain.TopicExpiry{{Topic: temporaryTopic, Expires: temporary.Expires}}).Return(nil)
+
+		_, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: &pushapi.Topics{Topics: []*pushapi.Topic{permanent, temporary}}})
+		require.NoError(t, err)
+	})
+	t.Run("expired", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		for name, expires := range map[string]int64{"past": 100, "negative": -1} {
+			t.Run(name, func(t *testing.T) {
+				rawTopic := newTopic("call")
+				rawTopic.Expires = expires
+				_, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}}})
+				require.Error(t, err)
+			})
+		}
+	})
+	t.Run("too many topics", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.Limits.MaxTopicsPerAccount = 2
+		acc := newAccount()
+		rawTopic := newTopic("topic")
+		// the signature is not verified before limits are checked
+		rawTopic.Signature = nil
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{
+			Topics: []domain.Topic{"s1/t1", "s1/t2"},
+		}, nil)
+
+		_, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}}})
+		assert.ErrorIs(t, err, pushapi.ErrTooManyTopics)
+	})
+	t.Run("too many spaces", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.Limits.MaxSpacesPerAccount = 2
+		acc := newAccount()
+		rawTopic := newTopic("topic")
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{
+			Topics: []domain.Topic{"s1/t1", "s2/t1"},
+		}, nil)
+
+		_, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}}})
+		assert.ErrorIs(t, err, pushapi.ErrTooManySpaces)
+	})
+	t.Run("expired topics are not counted", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.Limits.MaxTopicsPerAccount = 2
+		acc := newAccount()
+		rawTopic := newTopic("topic")
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().GetAccount(pCtx, acc.GetPublic().Account()).Return(domain.Account{
+			Topics:   []domain.Topic{"s1/t1", "s1/t2"},
+			Expiring: []domain.TopicExpiry{{Topic: "s1/t2", Expires: 1}},
+		}, nil)
+		fx.accountRepo.EXPECT().AddAccountTopics(pCtx, acc.GetPublic().Account(), gomock.Any(), nil).Return(nil)
+
+		_, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}}})
+		require.NoError(t, err)
+	})
+	t.Run("empty-topics-subscribe", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		resp, err := fx.handler.Subscribe(pCtx, &pushapi.SubscribeRequest{Topics: &pushapi.Topics{}})
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+}
+
+func TestHandler_Unsubscribe(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+
+		var rawTopics = &pushapi.Topics{}
+		var topicsToUnsubscribe []domain.Topic
+
+		for i := range 2 {
+			rawTopic := newTopic(fmt.Sprintf("topic%d", i))
+			rawTopics.Topics = append(rawTopics.Topics, rawTopic)
+
+			topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+			topicsToUnsubscribe = append(topicsToUnsubscribe, topic)
+		}
+
+		req := &pushapi.UnsubscribeRequest{
+			Topics: rawTopics,
+		}
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().RemoveAccountTopics(pCtx, acc.GetPublic().Account(), topicsToUnsubscribe).Return(nil)
+
+		resp, err := fx.handler.Unsubscribe(pCtx, req)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+
+	t.Run("remove-topics-error", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+
+		rawTopic := newTopic("topicX")
+
+		req := &pushapi.UnsubscribeRequest{
+			Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}},
+		}
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.accountRepo.EXPECT().RemoveAccountTopics(pCtx, acc.GetPublic().Account(), gomock.Any()).Return(errors.New("db error"))
+
+		resp, err := fx.handler.Unsubscribe(pCtx, req)
+		require.Error(t, err)
+		assert.Nil(t, resp)
+		assert.Contains(t, err.Error(), "db error")
+	})
+
+	t.Run("empty-topics-unsubscribe", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+
+		req := &pushapi.UnsubscribeRequest{
+			Topics: &pushapi.Topics{},
+		}
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		resp, err := fx.handler.Unsubscribe(pCtx, req)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
 }
 
-func TestHandler_SubscribeAll(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
+func TestHandler_Notify(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.SpaceVerification.RolloutPercent = 100
+		acc := newAccount()
+		rawTopic := newTopic("topicX")
+		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+		req := newNotifyRequest(acc, []byte{1, 2, 3}, rawTopic)
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.spaceRepo.EXPECT().ExistedSpaces(pCtx, []string{topic.SpaceKeyBase58()}).Return([]string{topic.SpaceKeyBase58()}, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(pCtx, []string{topic.SpaceKeyBase58()}).Return(nil, nil)
+		fx.rateLimit.EXPECT().Allow(pCtx,
+			ratelimit.Key{Scope: ratelimit.ScopeAccount, Id: acc.GetPublic().Account()},
+			ratelimit.Key{Scope: ratelimit.ScopeSpace, Id: topic.SpaceKeyBase58()},
+			ratelimit.Key{Scope: ratelimit.ScopeTopic, Id: string(topic)},
+		)
+		fx.queue.EXPECT().Add(pCtx, gomock.Cond[queue.Message](func(x queue.Message) bool {
+			exp := queue.Message{
+				IgnoreAccountId: acc.GetPublic().Account(),
+				KeyId:           req.Message.KeyId,
+				Payload:         req.Message.Payload,
+				Signature:       req.Message.Signature,
+				Topics:          []domain.Topic{topic},
+				GroupId:         "groupId",
+				Silent:          false,
+				SenderAccountId: acc.GetPublic().Account(),
+			}
+			x.Created = time.Time{}
+			return assert.Equal(t, exp, x)
+		})).Return(nil)
+
+		resp, err := fx.handler.Notify(pCtx, req)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("success silent", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.SpaceVerification.RolloutPercent = 100
+		acc := newAccount()
+		rawTopic := newTopic(acc.GetPublic().Account())
+		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+
+		invalidRawTopic := newTopic("1")
+
+		req := newNotifyRequest(acc, nil, rawTopic, invalidRawTopic)
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		// the space of the filtered out topic is not looked up
+		fx.spaceRepo.EXPECT().ExistedSpaces(pCtx, []string{topic.SpaceKeyBase58()}).Return([]string{topic.SpaceKeyBase58()}, nil)
+		// the filtered out topic doesn't spend the budget
+		fx.rateLimit.EXPECT().Allow(pCtx,
+			ratelimit.Key{Scope: ratelimit.ScopeAccount, Id: acc.GetPublic().Account()},
+			ratelimit.Key{Scope: ratelimit.ScopeSpace, Id: topic.SpaceKeyBase58()},
+			ratelimit.Key{Scope: ratelimit.ScopeTopic, Id: string(topic)},
+		)
+		fx.queue.EXPECT().Add(pCtx, gomock.Cond[queue.Message](func(x queue.Message) bool {
+			exp := queue.Message{
+				// expect only the valid topic where the topic field equals identity
+				Topics:          []domain.Topic{topic},
+				GroupId:         "groupId",
+				Silent:          true,
+				SenderAccountId: acc.GetPublic().Account(),
+			}
+			x.Created = time.Time{}
+			return assert.Equal(t, exp, x)
+		})).Return(nil)
+
+		resp, err := fx.handler.NotifySilent(pCtx, req)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+	t.Run("wildcard topic", func(t *testing.T) {
 		fx := newFixture(t)
+		fx.conf.SpaceVerification.RolloutPercent = 100
 		acc := newAccount()
-		var rawTopics = &pushapi.Topics{}
-		var topics []domain.Topic
-		for i := range 2 {
-			rawTopic := newTopic(fmt.Sprintf("topic%d", i))
-			rawTopics.Topics = append(rawTopics.Topics, rawTopic)
-			topics = append(topics, domain.Topic(base58.Encode(rawTopic.SpaceKey)+"/"+rawTopic.Topic))
-		}
+		req := newNotifyRequest(acc, []byte{1, 2, 3}, newTopic(domain.TopicWildcard))
 
-		req := &pushapi.SubscribeAllRequest{
-			Topics: rawTopics,
-		}
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+
+		// the message is not queued
+		resp, err := fx.handler.Notify(pCtx, req)
+		require.ErrorIs(t, err, pushapi.ErrNoValidTopics)
+		assert.Nil(t, resp)
+	})
+	t.Run("unregistered space", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.SpaceVerification.RolloutPercent = 100
+		acc := newAccount()
+		rawTopic := newTopic("topicX")
+		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+		req := newNotifyRequest(acc, []byte{1, 2, 3}, rawTopic)
 
 		ak, _ := acc.GetPublic().Marshall()
 		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		fx.accountRepo.EXPECT().SetAccountTopics(pCtx, acc.GetPublic().Account(), topics).Return(nil)
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.spaceRepo.EXPECT().ExistedSpaces(pCtx, []string{topic.SpaceKeyBase58()}).Return(nil, nil)
 
-		resp, err := fx.handler.SubscribeAll(pCtx, req)
+		resp, err := fx.handler.Notify(pCtx, req)
+		require.ErrorIs(t, err, pushapi.ErrNoValidTopics)
+		assert.Nil(t, resp)
+	})
+	t.Run("verification disabled", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		rawTopic := newTopic("topicX")
+		req := newNotifyRequest(acc, []byte{1, 2, 3}, rawTopic)
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(pCtx, gomock.Any()).Return(nil, nil)
+		fx.rateLimit.EXPECT().Allow(pCtx, gomock.Any(), gomock.Any(), gomock.Any())
+		fx.queue.EXPECT().Add(pCtx, gomock.Any()).Return(nil)
+
+		resp, err := fx.handler.Notify(pCtx, req)
 		require.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
-}
-
-func TestHandler_Unsubscribe(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
+	t.Run("account rate limited", func(t *testing.T) {
 		fx := newFixture(t)
+		fx.conf.SpaceVerification.RolloutPercent = 100
 		acc := newAccount()
+		rawTopic := newTopic("topicX")
+		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+		req := newNotifyRequest(acc, []byte{1, 2, 3}, rawTopic)
 
-		var rawTopics = &pushapi.Topics{}
-		var topicsToUnsubscribe []domain.Topic
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		for i := range 2 {
-			rawTopic := newTopic(fmt.Sprintf("topic%d", i))
-			rawTopics.Topics = append(rawTopics.Topics, rawTopic)
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.spaceRepo.EXPECT().ExistedSpaces(pCtx, gomock.Any()).Return([]string{topic.SpaceKeyBase58()}, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(pCtx, gomock.Any()).Return(nil, nil)
+		fx.rateLimit.EXPECT().Allow(pCtx, gomock.Any(), gomock.Any(), gomock.Any()).Return(1500*time.Millisecond, nil)
 
-			topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
-			topicsToUnsubscribe = append(topicsToUnsubscribe, topic)
-		}
+		_, err := fx.handler.Notify(pCtx, req)
+		assertRetryAfter(t, err, 1500*time.Millisecond)
+	})
+	t.Run("space rate limited", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.conf.SpaceVerification.RolloutPercent = 100
+		acc := newAccount()
+		rawTopic := newTopic("topicX")
+		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+		req := newNotifyRequest(acc, []byte{1, 2, 3}, rawTopic)
 
-		currentTopics := []domain.Topic{
-			topicsToUnsubscribe[0],
-			"otherSpaceKey/otherTopic1",
-			topicsToUnsubscribe[1],
-			"otherSpaceKey/otherTopic2",
-		}
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		expectedRemainingTopics := []domain.Topic{
-			"otherSpaceKey/otherTopic1",
-			"otherSpaceKey/otherTopic2",
-		}
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.spaceRepo.EXPECT().ExistedSpaces(pCtx, []string{topic.SpaceKeyBase58()}).Return([]string{topic.SpaceKeyBase58()}, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(pCtx, gomock.Any()).Return(nil, nil)
+		// the account budget is checked in the same call, so it's not spent when the space budget is exceeded
+		fx.rateLimit.EXPECT().Allow(pCtx,
+			ratelimit.Key{Scope: ratelimit.ScopeAccount, Id: acc.GetPublic().Account()},
+			ratelimit.Key{Scope: ratelimit.ScopeSpace, Id: topic.SpaceKeyBase58()},
+			ratelimit.Key{Scope: ratelimit.ScopeTopic, Id: string(topic)},
+		).Return(time.Second, nil)
 
-		req := &pushapi.UnsubscribeRequest{
-			Topics: rawTopics,
+		// the message is not queued
+		_, err := fx.handler.Notify(pCtx, req)
+		assertRetryAfter(t, err, time.Second)
+	})
+	t.Run("mentions only policy", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		spacePrivKey, spacePubKey, _ := crypto.GenerateRandomEd25519KeyPair()
+		spaceKey, _ := spacePubKey.Raw()
+		var rawTopics []*pushapi.Topic
+		for _, name := range []string{newAccount().GetPublic().Account(), "topicX"} {
+			signature, _ := spacePrivKey.Sign([]byte(name))
+			rawTopics = append(rawTopics, &pushapi.Topic{SpaceKey: spaceKey, Topic: name, Signature: signature})
 		}
+		mention := domain.NewTopic(spaceKey, rawTopics[0].Topic)
+		req := newNotifyRequest(acc, []byte{1, 2, 3}, rawTopics...)
 
 		ak, _ := acc.GetPublic().Marshall()
 		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		fx.accountRepo.EXPECT().GetTopicsByAccountId(pCtx, acc.GetPublic().Account()).Return(currentTopics, nil)
-		fx.accountRepo.EXPECT().SetAccountTopics(pCtx, acc.GetPublic().Account(), expectedRemainingTopics).Return(nil)
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(pCtx, []string{mention.SpaceKeyBase58()}).Return(map[string]domain.SpacePolicy{
+			mention.SpaceKeyBase58(): {Mode: domain.SpacePolicyMentionsOnly},
+		}, nil)
+		fx.rateLimit.EXPECT().Allow(pCtx, gomock.Any(), gomock.Any(), gomock.Any())
+		fx.queue.EXPECT().Add(pCtx, gomock.Cond[queue.Message](func(x queue.Message) bool {
+			return assert.Equal(t, []domain.Topic{mention}, x.Topics)
+		})).Return(nil)
 
-		resp, err := fx.handler.Unsubscribe(pCtx, req)
+		resp, err := fx.handler.Notify(pCtx, req)
 		require.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
+	t.Run("disabled policy", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		rawTopic := newTopic("topicX")
+		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+		req := newNotifyRequest(acc, []byte{1, 2, 3}, rawTopic)
+
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-	t.Run("unsubscribe-nonexistent-topic", func(t *testing.T) {
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(pCtx, gomock.Any()).Return(map[string]domain.SpacePolicy{
+			topic.SpaceKeyBase58(): {Mode: domain.SpacePolicyDisabled},
+		}, nil)
+
+		resp, err := fx.handler.Notify(pCtx, req)
+		require.ErrorIs(t, err, pushapi.ErrSpacePolicyRestricted)
+		assert.Nil(t, resp)
+	})
+	t.Run("fan-out budget spent", func(t *testing.T) {
 		fx := newFixture(t)
 		acc := newAccount()
+		rawTopic := newTopic("topicX")
+		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+		req := newNotifyRequest(acc, []byte{1, 2, 3}, rawTopic)
 
-		rawTopic := newTopic("nonexistentTopic")
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		req := &pushapi.UnsubscribeRequest{
-			Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}},
-		}
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(pCtx, gomock.Any()).Return(map[string]domain.SpacePolicy{
+			topic.SpaceKeyBase58(): {MaxFanOutPerMinute: 10},
+		}, nil)
+		fx.rateLimit.EXPECT().AllowN(pCtx,
+			ratelimit.Key{Scope: ratelimit.ScopeSpaceFanOut, Id: topic.SpaceKeyBase58()},
+			0,
+			ratelimit.PerMinute(10),
+		).Return(0, time.Second, nil)
 
-		currentTopics := []domain.Topic{
-			"otherSpaceKey/otherTopic1",
-			"otherSpaceKey/otherTopic2",
-		}
+		_, err := fx.handler.Notify(pCtx, req)
+		assertRetryAfter(t, err, time.Second)
+	})
+}
+
+// assertRetryAfter checks the rate limited error as the drpc client gets it, with the message and the code only
+func assertRetryAfter(t *testing.T, err error, expected time.Duration) {
+	require.ErrorIs(t, err, pushapi.ErrRateLimited)
+	wireErr := drpcerr.WithCode(errors.New(err.Error()), rpcerr.Code(err))
+	assert.ErrorIs(t, rpcerr.Unwrap(wireErr), pushapi.ErrRateLimited)
+	retryAfter, ok := pushapi.RetryAfter(wireErr)
+	assert.True(t, ok)
+	assert.Equal(t, expected, retryAfter)
+}
+
+func TestHandler_NotifyBanned(t *testing.T) {
+	fx := newFixture(t)
+	acc := newAccount()
+	req := newNotifyRequest(acc, []byte{1, 2, 3}, newTopic("topicX"))
 
-		expectedRemainingTopics := currentTopics
+	ak, _ := acc.GetPublic().Marshall()
+	pCtx := peer.CtxWithIdentity(ctx, ak)
 
+	fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(true, nil)
+
+	resp, err := fx.handler.Notify(pCtx, req)
+	require.ErrorIs(t, err, pushapi.ErrAccountBanned)
+	assert.Nil(t, resp)
+}
+
+func TestHandler_ReportNotification(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
 		ak, _ := acc.GetPublic().Marshall()
 		pCtx := peer.CtxWithIdentity(ctx, ak)
+		senderId := newAccount().GetPublic().Account()
 
-		fx.accountRepo.EXPECT().GetTopicsByAccountId(pCtx, acc.GetPublic().Account()).Return(currentTopics, nil)
-		fx.accountRepo.EXPECT().SetAccountTopics(pCtx, acc.GetPublic().Account(), expectedRemainingTopics).Return(nil)
+		fx.reportRepo.EXPECT().Add(pCtx, domain.Report{
+			ReporterId: acc.GetPublic().Account(),
+			GroupId:    "groupId",
+			SenderId:   senderId,
+			Reason:     "spam",
+		}).Return(nil)
 
-		resp, err := fx.handler.Unsubscribe(pCtx, req)
+		resp, err := fx.handler.ReportNotification(pCtx, &pushapi.ReportNotificationRequest{
+			GroupId:   "groupId",
+			AccountId: senderId,
+			Reason:    "spam",
+		})
 		require.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
+	t.Run("no groupId", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-	t.Run("get-topics-error", func(t *testing.T) {
+		resp, err := fx.handler.ReportNotification(pCtx, &pushapi.ReportNotificationRequest{})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+	t.Run("invalid accountId", func(t *testing.T) {
 		fx := newFixture(t)
 		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		rawTopic := newTopic("topicX")
+		resp, err := fx.handler.ReportNotification(pCtx, &pushapi.ReportNotificationRequest{GroupId: "groupId", AccountId: "invalid"})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
 
-		req := &pushapi.UnsubscribeRequest{
-			Topics: &pushapi.Topics{Topics: []*pushapi.Topic{rawTopic}},
+func TestHandler_NotifyEnvelope(t *testing.T) {
+	newRequest := func(acc crypto.PrivKey, envelope *pushapi.PayloadEnvelope) *pushapi.NotifyPeerRequest {
+		payload, _ := envelope.MarshalVT()
+		sig, _ := acc.Sign(payload)
+		return &pushapi.NotifyPeerRequest{
+			PeerId: "p2",
+			Message: &pushapi.Message{
+				KeyId:     "key1",
+				Payload:   payload,
+				Signature: sig,
+				Format:    pushapi.PayloadFormat_Envelope,
+			},
+			GroupId: "groupId",
 		}
+	}
+	envelope := &pushapi.PayloadEnvelope{
+		Payload:   []byte{1, 2, 3},
+		Timestamp: time.Now().Unix(),
+		Nonce:     []byte("0123456789abcdef"),
+	}
 
+	t.Run("success", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.requireEnvelope = true
+		acc := newAccount()
+		req := newRequest(acc, envelope)
 		ak, _ := acc.GetPublic().Marshall()
 		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		fx.accountRepo.EXPECT().GetTopicsByAccountId(pCtx, acc.GetPublic().Account()).Return(nil, errors.New("db error"))
+		fx.replay.EXPECT().Check(envelope.Timestamp, envelope.Nonce).Return(nil)
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.rateLimit.EXPECT().Allow(pCtx, ratelimit.Key{Scope: ratelimit.ScopeAccount, Id: acc.GetPublic().Account()})
+		fx.replay.EXPECT().Remember(pCtx, acc.GetPublic().Account(), envelope.Nonce).Return(nil)
+		fx.queue.EXPECT().Add(pCtx, gomock.Cond[queue.Message](func(x queue.Message) bool {
+			return assert.True(t, x.Envelope) && assert.Equal(t, req.Message.Payload, x.Payload)
+		})).Return(nil)
 
-		resp, err := fx.handler.Unsubscribe(pCtx, req)
-		require.Error(t, err)
-		assert.Nil(t, resp)
-		assert.Contains(t, err.Error(), "db error")
+		resp, err := fx.handler.NotifyPeer(pCtx, req)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
 	})
+	t.Run("replayed", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-	t.Run("empty-topics-unsubscribe", func(t *testing.T) {
+		fx.replay.EXPECT().Check(envelope.Timestamp, envelope.Nonce).Return(nil)
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.rateLimit.EXPECT().Allow(pCtx, ratelimit.Key{Scope: ratelimit.ScopeAccount, Id: acc.GetPublic().Account()})
+		fx.replay.EXPECT().Remember(pCtx, acc.GetPublic().Account(), envelope.Nonce).Return(replay.ErrReplayed)
+
+		resp, err := fx.handler.NotifyPeer(pCtx, newRequest(acc, envelope))
+		require.ErrorIs(t, err, pushapi.ErrMessageReplayed)
+		assert.Nil(t, resp)
+	})
+	t.Run("expired", func(t *testing.T) {
 		fx := newFixture(t)
 		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		req := &pushapi.UnsubscribeRequest{
-			Topics: &pushapi.Topics{},
+		fx.replay.EXPECT().Check(envelope.Timestamp, envelope.Nonce).Return(replay.ErrExpired)
+
+		resp, err := fx.handler.NotifyPeer(pCtx, newRequest(acc, envelope))
+		require.ErrorIs(t, err, pushapi.ErrMessageExpired)
+		assert.Nil(t, resp)
+	})
+	t.Run("rejected message keeps the nonce", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+		peerReq := newRequest(acc, envelope)
+		req := &pushapi.NotifyRequest{
+			Topics:  &pushapi.Topics{Topics: []*pushapi.Topic{newTopic("topicX")}},
+			Message: peerReq.Message,
+			GroupId: peerReq.GroupId,
 		}
 
+		fx.replay.EXPECT().Check(envelope.Timestamp, envelope.Nonce).Return(nil)
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(true, nil)
+
+		resp, err := fx.handler.Notify(pCtx, req)
+		require.ErrorIs(t, err, pushapi.ErrAccountBanned)
+		assert.Nil(t, resp)
+	})
+	t.Run("invalid envelope", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
 		ak, _ := acc.GetPublic().Marshall()
 		pCtx := peer.CtxWithIdentity(ctx, ak)
+		req := newRequest(acc, envelope)
+		req.Message.Payload = []byte{0xff, 0xff}
+		req.Message.Signature, _ = acc.Sign(req.Message.Payload)
 
-		resp, err := fx.handler.Unsubscribe(pCtx, req)
-		require.NoError(t, err)
-		assert.NotNil(t, resp)
+		resp, err := fx.handler.NotifyPeer(pCtx, req)
+		require.ErrorIs(t, err, pushapi.ErrInvalidEnvelope)
+		assert.Nil(t, resp)
+	})
+	t.Run("legacy not allowed", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.requireEnvelope = true
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+		req := newRequest(acc, envelope)
+		req.Message.Format = pushapi.PayloadFormat_Legacy
+
+		resp, err := fx.handler.NotifyPeer(pCtx, req)
+		require.ErrorIs(t, err, pushapi.ErrInvalidEnvelope)
+		assert.Nil(t, resp)
 	})
 }
 
-func TestHandler_Notify(t *testing.T) {
+func TestHandler_NotifyPeer(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		fx := newFixture(t)
 		acc := newAccount()
-		rawTopic := newTopic("topicX")
-		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
-		req := newNotifyRequest(acc, []byte{1, 2, 3}, rawTopic)
+		payload := []byte{1, 2, 3}
+		sig, _ := acc.Sign(payload)
+		req := &pushapi.NotifyPeerRequest{
+			PeerId: "p2",
+			Message: &pushapi.Message{
+				KeyId:     "key1",
+				Payload:   payload,
+				Signature: sig,
+			},
+			GroupId: "groupId",
+		}
 
 		ak, _ := acc.GetPublic().Marshall()
 		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		fx.spaceRepo.EXPECT().ExistedSpaces(pCtx, []string{topic.SpaceKeyBase58()}).Return([]string{topic.SpaceKeyBase58()}, nil)
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.rateLimit.EXPECT().Allow(pCtx, ratelimit.Key{Scope: ratelimit.ScopeAccount, Id: acc.GetPublic().Account()})
 		fx.queue.EXPECT().Add(pCtx, gomock.Cond[queue.Message](func(x queue.Message) bool {
 			exp := queue.Message{
-				IgnoreAccountId: acc.GetPublic().Account(),
-				KeyId:           req.Message.KeyId,
-				Payload:         req.Message.Payload,
-				Signature:       req.Message.Signature,
-				Topics:          []domain.Topic{topic},
-				GroupId:         "groupId",
-				Silent:          false,
+				KeyId:     req.Message.KeyId,
+				Payload:   req.Message.Payload,
+				Signature: req.Message.Signature,
+				GroupId:   "groupId",
+				Silent:    true,
+				AccountId: acc.GetPublic().Account(),
+				PeerId:    "p2",
 			}
 			x.Created = time.Time{}
 			return assert.Equal(t, exp, x)
 		})).Return(nil)
 
-		resp, err := fx.handler.Notify(pCtx, req)
+		resp, err := fx.handler.NotifyPeer(pCtx, req)
 		require.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
-	t.Run("success silent", func(t *testing.T) {
+	t.Run("banned", func(t *testing.T) {
 		fx := newFixture(t)
 		acc := newAccount()
-		rawTopic := newTopic(acc.GetPublic().Account())
-		topic := domain.NewTopic(rawTopic.SpaceKey, rawTopic.Topic)
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		invalidRawTopic := newTopic("1")
-		invalidTopic := domain.NewTopic(invalidRawTopic.SpaceKey, invalidRawTopic.Topic)
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(true, nil)
 
-		req := newNotifyRequest(acc, nil, rawTopic, invalidRawTopic)
+		resp, err := fx.handler.NotifyPeer(pCtx, &pushapi.NotifyPeerRequest{PeerId: "p2"})
+		require.ErrorIs(t, err, pushapi.ErrAccountBanned)
+		assert.Nil(t, resp)
+	})
+	t.Run("rate limited", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		fx.banRepo.EXPECT().IsBanned(pCtx, acc.GetPublic().Account()).Return(false, nil)
+		fx.rateLimit.EXPECT().Allow(pCtx, ratelimit.Key{Scope: ratelimit.ScopeAccount, Id: acc.GetPublic().Account()}).Return(time.Second, nil)
+
+		// the message is not queued
+		resp, err := fx.handler.NotifyPeer(pCtx, &pushapi.NotifyPeerRequest{PeerId: "p2"})
+		assertRetryAfter(t, err, time.Second)
+		assert.Nil(t, resp)
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
+		req := &pushapi.NotifyPeerRequest{
+			PeerId: "p2",
+			Message: &pushapi.Message{
+				Payload:   []byte{1, 2, 3},
+				Signature: []byte{4, 5, 6},
+			},
+		}
 
 		ak, _ := acc.GetPublic().Marshall()
 		pCtx := peer.CtxWithIdentity(ctx, ak)
 
-		fx.spaceRepo.EXPECT().
-			ExistedSpaces(pCtx, []string{topic.SpaceKeyBase58(), invalidTopic.SpaceKeyBase58()}).
-			Return([]string{topic.SpaceKeyBase58(), invalidTopic.SpaceKeyBase58()}, nil)
-		fx.queue.EXPECT().Add(pCtx, gomock.Cond[queue.Message](func(x queue.Message) bool {
-			exp := queue.Message{
-				// expect only the valid topic where the topic field equals identity
-				Topics:  []domain.Topic{topic},
-				GroupId: "groupId",
-				Silent:  true,
-			}
-			x.Created = time.Time{}
-			return assert.Equal(t, exp, x)
-		})).Return(nil)
+		resp, err := fx.handler.NotifyPeer(pCtx, req)
+		require.ErrorIs(t, err, pushapi.ErrInvalidSignature)
+		assert.Nil(t, resp)
+	})
+	t.Run("empty peerId", func(t *testing.T) {
+		fx := newFixture(t)
+		acc := newAccount()
 
-		resp, err := fx.handler.NotifySilent(pCtx, req)
-		require.NoError(t, err)
-		assert.NotNil(t, resp)
+		ak, _ := acc.GetPublic().Marshall()
+		pCtx := peer.CtxWithIdentity(ctx, ak)
+
+		resp, err := fx.handler.NotifyPeer(pCtx, &pushapi.NotifyPeerRequest{})
+		require.Error(t, err)
+		assert.Nil(t, resp)
 	})
 }
 
@@ -280,11 +1899,19 @@ func newNotifyRequest(accKey crypto.PrivKey, payload []byte, rawTopics ...*pusha
 
 type fixture struct {
 	*push
-	tokenRepo   *mock_tokenrepo.MockTokenRepo
-	accountRepo *mock_accountrepo.MockAccountRepo
-	spaceRepo   *mock_spacerepo.MockSpaceRepo
-	queue       *mock_queue.MockQueue
-	a           *app.App
+	db              *mock_db.MockDatabase
+	tokenRepo       *mock_tokenrepo.MockTokenRepo
+	accountRepo     *mock_accountrepo.MockAccountRepo
+	spaceRepo       *mock_spacerepo.MockSpaceRepo
+	banRepo         *mock_banrepo.MockBanRepo
+	reportRepo      *mock_reportrepo.MockReportRepo
+	accountData     *mock_accountdata.MockAccountData
+	queue           *mock_queue.MockQueue
+	sender          *mock_sender.MockSender
+	rateLimit       *mock_ratelimit.MockRateLimit
+	replay          *mock_replay.MockReplay
+	requireEnvelope bool
+	a               *app.App
 }
 
 func newFixture(t *testing.T) *fixture {
@@ -292,11 +1919,23 @@ func newFixture(t *testing.T) *fixture {
 	fx := &fixture{
 		push:        New().(*push),
 		a:           new(app.App),
+		db:          mock_db.NewMockDatabase(ctrl),
 		tokenRepo:   mock_tokenrepo.NewMockTokenRepo(ctrl),
 		accountRepo: mock_accountrepo.NewMockAccountRepo(ctrl),
 		spaceRepo:   mock_spacerepo.NewMockSpaceRepo(ctrl),
+		banRepo:     mock_banrepo.NewMockBanRepo(ctrl),
+		reportRepo:  mock_reportrepo.NewMockReportRepo(ctrl),
+		accountData: mock_accountdata.NewMockAccountData(ctrl),
 		queue:       mock_queue.NewMockQueue(ctrl),
+		sender:      mock_sender.NewMockSender(ctrl),
+		rateLimit:   mock_ratelimit.NewMockRateLimit(ctrl),
+		replay:      mock_replay.NewMockReplay(ctrl),
 	}
+	fx.db.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.db.EXPECT().Name().Return(db.CName).AnyTimes()
+	fx.db.EXPECT().Tx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, f func(txCtx mongo.SessionContext) error) error {
+		return f(mongo.NewSessionContext(ctx, nil))
+	}).AnyTimes()
 	fx.tokenRepo.EXPECT().Name().Return(tokenrepo.CName).AnyTimes()
 	fx.tokenRepo.EXPECT().Init(gomock.Any()).AnyTimes()
 	fx.tokenRepo.EXPECT().Run(gomock.Any()).AnyTimes()
@@ -309,15 +1948,43 @@ func newFixture(t *testing.T) *fixture {
 	fx.spaceRepo.EXPECT().Name().Return(spacerepo.CName).AnyTimes()
 	fx.spaceRepo.EXPECT().Run(gomock.Any()).AnyTimes()
 	fx.spaceRepo.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.banRepo.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.banRepo.EXPECT().Name().Return(banrepo.CName).AnyTimes()
+	fx.banRepo.EXPECT().Run(gomock.Any()).AnyTimes()
+	fx.banRepo.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.reportRepo.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.reportRepo.EXPECT().Name().Return(reportrepo.CName).AnyTimes()
+	fx.reportRepo.EXPECT().Run(gomock.Any()).AnyTimes()
+	fx.reportRepo.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.accountData.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.accountData.EXPECT().Name().Return(accountdata.CName).AnyTimes()
 	fx.queue.EXPECT().Init(gomock.Any()).AnyTimes()
 	fx.queue.EXPECT().Name().Return(queue.CName).AnyTimes()
 	fx.queue.EXPECT().Run(gomock.Any()).AnyTimes()
 	fx.queue.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.sender.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.sender.EXPECT().Name().Return(sender.CName).AnyTimes()
+	fx.sender.EXPECT().Run(gomock.Any()).AnyTimes()
+	fx.sender.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.rateLimit.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.rateLimit.EXPECT().Name().Return(ratelimit.CName).AnyTimes()
+	fx.replay.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.replay.EXPECT().Name().Return(replay.CName).AnyTimes()
+	fx.replay.EXPECT().RequireEnvelope().DoAndReturn(func() bool {
+		return fx.requireEnvelope
+	}).AnyTimes()
 
-	fx.a.Register(fx.tokenRepo).
+	fx.a.Register(fx.db).
+		Register(fx.tokenRepo).
 		Register(fx.accountRepo).
 		Register(fx.spaceRepo).
+		Register(fx.banRepo).
+		Register(fx.reportRepo).
+		Register(fx.accountData).
 		Register(fx.queue).
+		Register(fx.sender).
+		Register(fx.rateLimit).
+		Register(fx.replay).
 		Register(metric.New()).
 		Register(&testConfig{}).
 		Register(fx.push).
@@ -336,6 +2003,19 @@ func newAccount() crypto.PrivKey {
 	return as.Account().SignKey
 }
 
+func newAccountCtx(acc crypto.PrivKey) context.Context {
+	ak, _ := acc.GetPublic().Marshall()
+	return peer.CtxWithIdentity(ctx, ak)
+}
+
+// newSpaceKey returns a raw space key and its signature of the account identity
+func newSpaceKey(acc crypto.PrivKey) (spaceKey, signature []byte) {
+	privKey, pubKey, _ := crypto.GenerateRandomEd25519KeyPair()
+	spaceKey, _ = pubKey.Raw()
+	signature, _ = privKey.Sign([]byte(acc.GetPublic().Account()))
+	return
+}
+
 func newTopic(topic string) *pushapi.Topic {
 	privKey, pubKey, _ := crypto.GenerateRandomEd25519KeyPair()
 	signature, _ := privKey.Sign([]byte(topic))
@@ -360,3 +2040,7 @@ func (t testConfig) Name() (name string) {
 func (t testConfig) GetMetric() metric.Config {
 	return metric.Config{}
 }
+
+func (t testConfig) GetPush() Config {
+	return Config{ValidateTokens: true}
+}
@@ -0,0 +1,43 @@
+package push
+
+import (
+	"github.com/anyproto/anytype-push-server/domain"
+	"github.com/anyproto/anytype-push-server/pushclient/pushapi"
+)
+
+// checkRequestLimits validates the request size, it's called before the signature verification
+func (p *push) checkRequestLimits(topics *pushapi.Topics) error {
+	if len(topics.GetTopics()) > p.conf.Limits.MaxTopicsPerRequest {
+		return pushapi.ErrTooManyTopicsInRequest
+	}
+	for _, topic := range topics.GetTopics() {
+		if len(topic.Topic) > p.conf.Limits.MaxTopicLength {
+			return pushapi.ErrTopicTooLong
+		}
+	}
+	return nil
+}
+
+// checkAccountLimits validates the account topics after adding the requested ones
+func (p *push) checkAccountLimits(current []domain.Topic, topics *pushapi.Topics) error {
+	var (
+		all    = make(map[domain.Topic]struct{}, len(current)+len(topics.GetTopics()))
+		spaces = make(map[string]struct{})
+	)
+	for _, topic := range current {
+		all[topic] = struct{}{}
+		spaces[topic.SpaceKeyBase58()] = struct{}{}
+	}
+	for _, topic := range topics.GetTopics() {
+		dTopic := domain.NewTopic(topic.SpaceKey, topic.Topic)
+		all[dTopic] = struct{}{}
+		spaces[dTopic.SpaceKeyBase58()] = struct{}{}
+	}
+	if len(all) > p.conf.Limits.MaxTopicsPerAccount {
+		return pushapi.ErrTooManyTopics
+	}
+	if len(spaces) > p.conf.Limits.MaxSpacesPerAccount {
+		return pushapi.ErrTooManySpaces
+	}
+	return nil
+}
@@ -2,9 +2,11 @@ package push
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/anyproto/any-sync/app"
 	"github.com/anyproto/any-sync/app/logger"
@@ -13,15 +15,23 @@ import (
 	"github.com/anyproto/any-sync/net/rpc/server"
 	"github.com/anyproto/any-sync/util/crypto"
 	"github.com/mr-tron/base58"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"go.uber.org/zap"
 
+	"github.com/anyproto/anytype-push-server/accountdata"
+	"github.com/anyproto/anytype-push-server/db"
 	"github.com/anyproto/anytype-push-server/domain"
 	"github.com/anyproto/anytype-push-server/pushclient/pushapi"
 	"github.com/anyproto/anytype-push-server/queue"
+	"github.com/anyproto/anytype-push-server/ratelimit"
+	"github.com/anyproto/anytype-push-server/replay"
 	"github.com/anyproto/anytype-push-server/repo/accountrepo"
+	"github.com/anyproto/anytype-push-server/repo/banrepo"
+	"github.com/anyproto/anytype-push-server/repo/reportrepo"
 	"github.com/anyproto/anytype-push-server/repo/spacerepo"
 	"github.com/anyproto/anytype-push-server/repo/tokenrepo"
+	"github.com/anyproto/anytype-push-server/sender"
 )
 
 const CName = "push"
@@ -37,19 +47,47 @@ type Push interface {
 }
 
 type push struct {
+	db          db.Database
 	tokenRepo   tokenrepo.TokenRepo
 	accountRepo accountrepo.AccountRepo
 	spaceRepo   spacerepo.SpaceRepo
+	banRepo     banrepo.BanRepo
+	reportRepo  reportrepo.ReportRepo
+	accountData accountdata.AccountData
 	queue       queue.Queue
+	sender      sender.Sender
+	rateLimit   ratelimit.RateLimit
+	replay      replay.Replay
 	metric      metric.Metric
+	conf        Config
 	handler     *handler
 }
 
 func (p *push) Init(a *app.App) (err error) {
+	p.db = a.MustComponent(db.CName).(db.Database)
 	p.tokenRepo = a.MustComponent(tokenrepo.CName).(tokenrepo.TokenRepo)
 	p.accountRepo = a.MustComponent(accountrepo.CName).(accountrepo.AccountRepo)
 	p.spaceRepo = a.MustComponent(spacerepo.CName).(spacerepo.SpaceRepo)
+	p.banRepo = a.MustComponent(banrepo.CName).(banrepo.BanRepo)
+	p.reportRepo = a.MustComponent(reportrepo.CName).(reportrepo.ReportRepo)
+	p.accountData = a.MustComponent(accountdata.CName).(accountdata.AccountData)
 	p.queue = a.MustComponent(queue.CName).(queue.Queue)
+	p.sender = a.MustComponent(sender.CName).(sender.Sender)
+	p.rateLimit = a.MustComponent(ratelimit.CName).(ratelimit.RateLimit)
+	p.replay = a.MustComponent(replay.CName).(replay.Replay)
+	p.conf = a.MustComponent("config").(configSource).GetPush()
+	if p.conf.Limits.MaxTopicsPerAccount <= 0 {
+		p.conf.Limits.MaxTopicsPerAccount = defaultMaxTopicsPerAccount
+	}
+	if p.conf.Limits.MaxTopicsPerRequest <= 0 {
+		p.conf.Limits.MaxTopicsPerRequest = defaultMaxTopicsPerRequest
+	}
+	if p.conf.Limits.MaxTopicLength <= 0 {
+		p.conf.Limits.MaxTopicLength = defaultMaxTopicLength
+	}
+	if p.conf.Limits.MaxSpacesPerAccount <= 0 {
+		p.conf.Limits.MaxSpacesPerAccount = defaultMaxSpacesPerAccount
+	}
 	p.metric = a.MustComponent(metric.CName).(metric.Metric)
 	p.handler = &handler{p: p}
 	return pushapi.DRPCRegisterPush(a.MustComponent(server.CName).(server.DRPCServer), p.handler)
@@ -68,15 +106,50 @@ func (p *push) AddToken(ctx context.Context, req *pushapi.SetTokenRequest) error
 	if err != nil {
 		return err
 	}
+	if req.Token == "" {
+		return pushapi.ErrInvalidToken
+	}
+	if err = checkClientInfo(req); err != nil {
+		return err
+	}
+	if p.conf.ValidateTokens {
+		if err = p.sender.ValidateToken(ctx, domain.Platform(req.Platform), domain.ApnsEnvironment(req.ApnsEnvironment), req.Token); err != nil {
+			if errors.Is(err, sender.ErrInvalidToken) || errors.Is(err, sender.ErrUnexpectedPlatform) {
+				return pushapi.ErrInvalidToken
+			}
+			// provider is unavailable, an invalid token will be caught on send
+			log.Warn("token validation error", zap.Error(err))
+		}
+	}
 	return p.tokenRepo.AddToken(ctx, domain.Token{
-		Id:        req.Token,
-		AccountId: accPubKey.Account(),
-		PeerId:    peerId,
-		Platform:  domain.Platform(req.Platform),
-		Status:    domain.TokenStatusValid,
+		Id:              req.Token,
+		AccountId:       accPubKey.Account(),
+		PeerId:          peerId,
+		Platform:        domain.Platform(req.Platform),
+		Status:          domain.TokenStatusValid,
+		AppVersion:      req.AppVersion,
+		OsVersion:       req.OsVersion,
+		BuildChannel:    domain.BuildChannel(req.BuildChannel),
+		ApnsEnvironment: domain.ApnsEnvironment(req.ApnsEnvironment),
 	})
 }
 
+// maxClientVersionLength bounds client reported versions stored with the token
+const maxClientVersionLength = 64
+
+func checkClientInfo(req *pushapi.SetTokenRequest) error {
+	if len(req.AppVersion) > maxClientVersionLength || len(req.OsVersion) > maxClientVersionLength {
+		return fmt.Errorf("push: version is too long")
+	}
+	if _, ok := pushapi.BuildChannel_name[int32(req.BuildChannel)]; !ok {
+		return fmt.Errorf("push: unexpected build channel: %d", req.BuildChannel)
+	}
+	if _, ok := pushapi.ApnsEnvironment_name[int32(req.ApnsEnvironment)]; !ok {
+		return fmt.Errorf("push: unexpected apns environment: %d", req.ApnsEnvironment)
+	}
+	return nil
+}
+
 func (p *push) RevokeToken(ctx context.Context) error {
 	accPubKey, err := peer.CtxPubKey(ctx)
 	if err != nil {
@@ -89,16 +162,176 @@ func (p *push) RevokeToken(ctx context.Context) error {
 	return p.tokenRepo.RevokeToken(ctx, accPubKey.Account(), peerId)
 }
 
+func (p *push) ListDevices(ctx context.Context) (devices []*pushapi.Device, err error) {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := p.tokenRepo.GetTokensByAccountId(ctx, accPubKey.Account())
+	if err != nil {
+		return nil, err
+	}
+	devices = make([]*pushapi.Device, len(tokens))
+	for i, token := range tokens {
+		devices[i] = &pushapi.Device{
+			PeerId:          token.PeerId,
+			Platform:        pushapi.Platform(token.Platform),
+			Created:         token.Created,
+			Updated:         token.Updated,
+			AppVersion:      token.AppVersion,
+			Status:          pushapi.TokenStatus(token.Status),
+			OsVersion:       token.OsVersion,
+			BuildChannel:    pushapi.BuildChannel(token.BuildChannel),
+			ApnsEnvironment: pushapi.ApnsEnvironment(token.ApnsEnvironment),
+		}
+	}
+	return
+}
+
+func (p *push) RevokeDevice(ctx context.Context, peerId string) error {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return err
+	}
+	err = p.tokenRepo.RemoveDeviceTokens(ctx, accPubKey.Account(), peerId)
+	if errors.Is(err, tokenrepo.ErrTokenNotFound) {
+		return pushapi.ErrDeviceNotFound
+	}
+	return err
+}
+
+func (p *push) DeleteAccount(ctx context.Context) error {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return err
+	}
+	return p.accountData.Delete(ctx, accPubKey.Account())
+}
+
+func (p *push) ExportAccountData(ctx context.Context, revealTokens bool) (resp *pushapi.ExportAccountDataResponse, err error) {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	export, err := p.accountData.Export(ctx, accPubKey.Account(), revealTokens)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(export)
+	if err != nil {
+		return nil, err
+	}
+	return &pushapi.ExportAccountDataResponse{
+		Version: uint32(export.Version),
+		Data:    data,
+	}, nil
+}
+
+func (p *push) Block(ctx context.Context, accountId string) error {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return err
+	}
+	if err = checkBlockedAccountId(accPubKey, accountId); err != nil {
+		return err
+	}
+	return p.accountRepo.BlockAccount(ctx, accPubKey.Account(), accountId)
+}
+
+func (p *push) Unblock(ctx context.Context, accountId string) error {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return err
+	}
+	return p.accountRepo.UnblockAccount(ctx, accPubKey.Account(), accountId)
+}
+
+func (p *push) ListBlocked(ctx context.Context) (accountIds []string, err error) {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account, err := p.accountRepo.GetAccount(ctx, accPubKey.Account())
+	if err != nil {
+		return nil, err
+	}
+	return account.Blocked, nil
+}
+
+const maxReportReasonLength = 1024
+
+func (p *push) ReportNotification(ctx context.Context, req *pushapi.ReportNotificationRequest) error {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return err
+	}
+	if req.GroupId == "" {
+		return fmt.Errorf("push: groupId is required")
+	}
+	if req.AccountId != "" {
+		if _, err = crypto.DecodeAccountAddress(req.AccountId); err != nil {
+			return fmt.Errorf("push: invalid accountId: %w", err)
+		}
+	}
+	if len(req.Reason) > maxReportReasonLength {
+		return fmt.Errorf("push: reason is too long")
+	}
+	return p.reportRepo.Add(ctx, domain.Report{
+		ReporterId: accPubKey.Account(),
+		GroupId:    req.GroupId,
+		SenderId:   req.AccountId,
+		Reason:     req.Reason,
+	})
+}
+
+func checkBlockedAccountId(accPubKey crypto.PubKey, accountId string) error {
+	if _, err := crypto.DecodeAccountAddress(accountId); err != nil {
+		return fmt.Errorf("push: invalid accountId: %w", err)
+	}
+	if accountId == accPubKey.Account() {
+		return fmt.Errorf("push: can't block own account")
+	}
+	return nil
+}
+
 func (p *push) SubscribeAll(ctx context.Context, req *pushapi.SubscribeAllRequest) error {
 	accPubKey, err := peer.CtxPubKey(ctx)
 	if err != nil {
 		return err
 	}
+	if err = p.checkRequestLimits(req.Topics); err != nil {
+		return err
+	}
+	// topics are replaced as a whole, expiring subscriptions are added with Subscribe
+	for _, topic := range req.Topics.GetTopics() {
+		if topic.Expires != 0 {
+			return fmt.Errorf("push: expiring topics are not supported by SubscribeAll")
+		}
+	}
+	// topics are replaced, so only the requested ones count
+	if err = p.checkAccountLimits(nil, req.Topics); err != nil {
+		return err
+	}
 	topics, err := convertTopics(req.Topics)
 	if err != nil {
 		return err
 	}
-	return p.accountRepo.SetAccountTopics(ctx, accPubKey.Account(), topics)
+	if len(topics) > 0 {
+		if topics, err = p.filterRegisteredTopics(ctx, topics); err != nil {
+			return err
+		}
+		if len(topics) == 0 {
+			return pushapi.ErrNoValidTopics
+		}
+	}
+	if req.ExpectedVersion == nil {
+		return p.accountRepo.SetAccountTopics(ctx, accPubKey.Account(), topics)
+	}
+	err = p.accountRepo.SetAccountTopicsIfVersion(ctx, accPubKey.Account(), topics, *req.ExpectedVersion)
+	if errors.Is(err, accountrepo.ErrVersionConflict) {
+		return pushapi.ErrVersionConflict
+	}
+	return err
 }
 
 func (p *push) Notify(ctx context.Context, req *pushapi.NotifyRequest, silent bool) error {
@@ -106,6 +339,9 @@ func (p *push) Notify(ctx context.Context, req *pushapi.NotifyRequest, silent bo
 	if err != nil {
 		return err
 	}
+	if err = p.checkRequestLimits(req.Topics); err != nil {
+		return err
+	}
 	topics, err := convertTopics(req.Topics)
 	if err != nil {
 		return err
@@ -113,62 +349,195 @@ func (p *push) Notify(ctx context.Context, req *pushapi.NotifyRequest, silent bo
 	if !silent && req.Message == nil {
 		return fmt.Errorf("push: message is required")
 	}
-	if req.Message != nil {
-		valid, err := accPubKey.Verify(req.Message.Payload, req.Message.Signature)
-		if err != nil {
-			return err
-		}
-		if !valid {
-			return pushapi.ErrInvalidSignature
-		}
-	}
-	// mak a list of unique spaceKeys
-	var spaceKeys = make([]string, 0, len(topics))
-	for _, topic := range topics {
-		spaceKey := topic.SpaceKeyBase58()
-		if !slices.Contains(spaceKeys, spaceKey) {
-			spaceKeys = append(spaceKeys, spaceKey)
-		}
-	}
-	validSpaceKeys, err := p.spaceRepo.ExistedSpaces(ctx, spaceKeys)
+	nonce, err := p.checkMessage(accPubKey, req.Message)
 	if err != nil {
 		return err
 	}
-	// filter by registered space keys
+	if err = p.checkBanned(ctx, accPubKey.Account()); err != nil {
+		return err
+	}
 	var filteredTopics = topics[:0]
 	for _, topic := range topics {
 		if silent && topic.Topic() != accPubKey.Account() {
 			continue
 		}
-		if slices.Contains(validSpaceKeys, topic.SpaceKeyBase58()) {
-			filteredTopics = append(filteredTopics, topic)
+		// wildcards are only for subscriptions, a message is sent to a concrete topic
+		if topic.IsWildcard() {
+			continue
 		}
+		filteredTopics = append(filteredTopics, topic)
+	}
+	if topics, err = p.filterRegisteredTopics(ctx, filteredTopics); err != nil {
+		return err
 	}
-	topics = filteredTopics
-
 	if len(topics) == 0 {
-		return nil
+		return pushapi.ErrNoValidTopics
+	}
+	// silent notifications wake up the sender's own devices and aren't restricted by the space
+	if !silent {
+		if topics, err = p.applySpacePolicies(ctx, topics); err != nil {
+			return err
+		}
+	}
+
+	// all budgets are checked in one call, so a rejected call spends none of them,
+	// space and topic budgets are checked only for verified topics of registered spaces
+	var limitKeys = []ratelimit.Key{{Scope: ratelimit.ScopeAccount, Id: accPubKey.Account()}}
+	for _, topic := range topics {
+		spaceKey := ratelimit.Key{Scope: ratelimit.ScopeSpace, Id: topic.SpaceKeyBase58()}
+		if !slices.Contains(limitKeys, spaceKey) {
+			limitKeys = append(limitKeys, spaceKey)
+		}
+	}
+	for _, topic := range topics {
+		limitKeys = append(limitKeys, ratelimit.Key{Scope: ratelimit.ScopeTopic, Id: string(topic)})
+	}
+	if err = p.allow(ctx, limitKeys...); err != nil {
+		return err
 	}
 
 	message := queue.Message{
-		GroupId: req.GroupId,
-		Topics:  topics,
-		Silent:  silent,
+		GroupId:         req.GroupId,
+		Topics:          topics,
+		Silent:          silent,
+		SenderAccountId: accPubKey.Account(),
 	}
 
 	if req.Message != nil {
 		message.KeyId = req.Message.KeyId
 		message.Payload = req.Message.Payload
 		message.Signature = req.Message.Signature
+		message.Envelope = req.Message.Format == pushapi.PayloadFormat_Envelope
 	}
 
 	if !silent {
 		message.IgnoreAccountId = accPubKey.Account()
 	}
+	if err = p.rememberNonce(ctx, accPubKey.Account(), nonce); err != nil {
+		return err
+	}
 	return p.queue.Add(ctx, message)
 }
 
-func (p *push) CreateSpace(ctx context.Context, key []byte, signature []byte) (err error) {
+func (p *push) allow(ctx context.Context, keys ...ratelimit.Key) error {
+	retryAfter, err := p.rateLimit.Allow(ctx, keys...)
+	if err != nil {
+		return err
+	}
+	if retryAfter > 0 {
+		return pushapi.NewRateLimitedError(retryAfter)
+	}
+	return nil
+}
+
+func (p *push) NotifyPeer(ctx context.Context, req *pushapi.NotifyPeerRequest) error {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return err
+	}
+	if req.PeerId == "" {
+		return fmt.Errorf("push: peerId is required")
+	}
+	nonce, err := p.checkMessage(accPubKey, req.Message)
+	if err != nil {
+		return err
+	}
+	if err = p.checkBanned(ctx, accPubKey.Account()); err != nil {
+		return err
+	}
+	if err = p.allow(ctx, ratelimit.Key{Scope: ratelimit.ScopeAccount, Id: accPubKey.Account()}); err != nil {
+		return err
+	}
+	message := queue.Message{
+		GroupId:   req.GroupId,
+		Silent:    true,
+		AccountId: accPubKey.Account(),
+		PeerId:    req.PeerId,
+	}
+	if req.Message != nil {
+		message.KeyId = req.Message.KeyId
+		message.Payload = req.Message.Payload
+		message.Signature = req.Message.Signature
+		message.Envelope = req.Message.Format == pushapi.PayloadFormat_Envelope
+	}
+	if err = p.rememberNonce(ctx, accPubKey.Account(), nonce); err != nil {
+		return err
+	}
+	return p.queue.Add(ctx, message)
+}
+
+func (p *push) checkBanned(ctx context.Context, accountId string) error {
+	banned, err := p.banRepo.IsBanned(ctx, accountId)
+	if err != nil {
+		return err
+	}
+	if banned {
+		return pushapi.ErrAccountBanned
+	}
+	return nil
+}
+
+// checkMessage verifies the signature and the envelope timestamp, returns the envelope nonce to remember before queueing
+func (p *push) checkMessage(accPubKey crypto.PubKey, msg *pushapi.Message) (nonce []byte, err error) {
+	if msg == nil {
+		return nil, nil
+	}
+	if err = checkMessageSignature(accPubKey, msg); err != nil {
+		return nil, err
+	}
+	switch msg.Format {
+	case pushapi.PayloadFormat_Legacy:
+		if p.replay.RequireEnvelope() {
+			return nil, fmt.Errorf("%w: legacy payload format is not allowed", pushapi.ErrInvalidEnvelope)
+		}
+		return nil, nil
+	case pushapi.PayloadFormat_Envelope:
+		envelope := &pushapi.PayloadEnvelope{}
+		if err = envelope.UnmarshalVT(msg.Payload); err != nil {
+			return nil, fmt.Errorf("%w: %w", pushapi.ErrInvalidEnvelope, err)
+		}
+		err = p.replay.Check(envelope.Timestamp, envelope.Nonce)
+		switch {
+		case errors.Is(err, replay.ErrInvalidNonce):
+			return nil, fmt.Errorf("%w: %w", pushapi.ErrInvalidEnvelope, err)
+		case errors.Is(err, replay.ErrExpired):
+			return nil, pushapi.ErrMessageExpired
+		case err != nil:
+			return nil, err
+		}
+		return envelope.Nonce, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected payload format", pushapi.ErrInvalidEnvelope)
+	}
+}
+
+// rememberNonce rejects replayed envelopes, it's the last check before the message is queued
+func (p *push) rememberNonce(ctx context.Context, accountId string, nonce []byte) error {
+	if nonce == nil {
+		return nil
+	}
+	err := p.replay.Remember(ctx, accountId, nonce)
+	if errors.Is(err, replay.ErrReplayed) {
+		return pushapi.ErrMessageReplayed
+	}
+	return err
+}
+
+func checkMessageSignature(accPubKey crypto.PubKey, msg *pushapi.Message) error {
+	if msg == nil {
+		return nil
+	}
+	valid, err := accPubKey.Verify(msg.Payload, msg.Signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return pushapi.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (p *push) CreateSpace(ctx context.Context, key []byte, signature []byte, spaceType pushapi.SpaceType) (err error) {
 	accPubKey, err := peer.CtxPubKey(ctx)
 	if err != nil {
 		return err
@@ -176,14 +545,20 @@ func (p *push) CreateSpace(ctx context.Context, key []byte, signature []byte) (e
 	if err = checkSpaceSignature(accPubKey.Account(), key, signature); err != nil {
 		return
 	}
-	err = p.spaceRepo.Create(ctx, domain.Space{
-		Id:     base58.Encode(key),
-		Author: accPubKey.Account(),
-	})
+	switch spaceType {
+	case pushapi.SpaceType_OneToOne:
+		// both participants own the space key, so each of them registers the space
+		err = p.spaceRepo.AddOneToOneMember(ctx, base58.Encode(key), accPubKey.Account())
+	default:
+		err = p.spaceRepo.Create(ctx, domain.Space{
+			Id:     base58.Encode(key),
+			Author: accPubKey.Account(),
+		})
+	}
 	if errors.Is(err, spacerepo.ErrSpaceExists) {
-		err = pushapi.ErrSpaceExists
+		return pushapi.ErrSpaceExists
 	}
-	return nil
+	return err
 }
 
 func (p *push) RemoveSpace(ctx context.Context, key []byte, signature []byte) (err error) {
@@ -194,14 +569,140 @@ func (p *push) RemoveSpace(ctx context.Context, key []byte, signature []byte) (e
 	if err = checkSpaceSignature(accPubKey.Account(), key, signature); err != nil {
 		return
 	}
-	err = p.spaceRepo.Remove(ctx, domain.Space{
-		Id:     base58.Encode(key),
-		Author: accPubKey.Account(),
+	spaceKey := base58.Encode(key)
+	err = p.db.Tx(ctx, func(txCtx mongo.SessionContext) error {
+		if err := p.spaceRepo.Remove(txCtx, domain.Space{
+			Id:     spaceKey,
+			Author: accPubKey.Account(),
+		}); err != nil {
+			return err
+		}
+		return p.accountRepo.RemoveSpaceTopics(txCtx, spaceKey)
 	})
-	if errors.Is(err, spacerepo.ErrSpaceExists) {
-		err = pushapi.ErrSpaceExists
+	return spaceErr(err)
+}
+
+func (p *push) RotateSpaceKey(ctx context.Context, req *pushapi.RotateSpaceKeyRequest) (err error) {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return err
 	}
-	return nil
+	if err = checkSpaceSignature(accPubKey.Account(), req.SpaceKey, req.AccountSignature); err != nil {
+		return
+	}
+	if err = checkSpaceSignature(accPubKey.Account(), req.NewSpaceKey, req.NewAccountSignature); err != nil {
+		return
+	}
+	oldSpaceKey, newSpaceKey := base58.Encode(req.SpaceKey), base58.Encode(req.NewSpaceKey)
+	err = p.db.Tx(ctx, func(txCtx mongo.SessionContext) error {
+		if err := p.spaceRepo.Rekey(txCtx, domain.Space{
+			Id:     oldSpaceKey,
+			Author: accPubKey.Account(),
+		}, newSpaceKey); err != nil {
+			return err
+		}
+		return p.accountRepo.RenameSpaceTopics(txCtx, oldSpaceKey, newSpaceKey)
+	})
+	return spaceErr(err)
+}
+
+func (p *push) TransferSpace(ctx context.Context, req *pushapi.TransferSpaceRequest) (err error) {
+	space, err := checkSpaceAdminRequest(ctx, req.SpaceKey, req.AccountSignature, req.AccountId)
+	if err != nil {
+		return
+	}
+	return spaceErr(p.spaceRepo.Transfer(ctx, space, req.AccountId))
+}
+
+func (p *push) AddSpaceAdmin(ctx context.Context, req *pushapi.AddSpaceAdminRequest) (err error) {
+	space, err := checkSpaceAdminRequest(ctx, req.SpaceKey, req.AccountSignature, req.AccountId)
+	if err != nil {
+		return
+	}
+	return spaceErr(p.spaceRepo.AddAdmin(ctx, space, req.AccountId))
+}
+
+func (p *push) RemoveSpaceAdmin(ctx context.Context, req *pushapi.RemoveSpaceAdminRequest) (err error) {
+	space, err := checkSpaceAdminRequest(ctx, req.SpaceKey, req.AccountSignature, req.AccountId)
+	if err != nil {
+		return
+	}
+	return spaceErr(p.spaceRepo.RemoveAdmin(ctx, space, req.AccountId))
+}
+
+func (p *push) GetSpacePolicy(ctx context.Context, key []byte, signature []byte) (policy *pushapi.SpacePolicy, err error) {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return
+	}
+	if err = checkSpaceSignature(accPubKey.Account(), key, signature); err != nil {
+		return
+	}
+	space, err := p.spaceRepo.Get(ctx, base58.Encode(key))
+	if err != nil {
+		return nil, spaceErr(err)
+	}
+	return &pushapi.SpacePolicy{
+		Mode:               pushapi.SpacePolicyMode(space.Policy.Mode),
+		MaxFanOutPerMinute: uint32(space.Policy.MaxFanOutPerMinute),
+	}, nil
+}
+
+func (p *push) SetSpacePolicy(ctx context.Context, req *pushapi.SetSpacePolicyRequest) (err error) {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return
+	}
+	if err = checkSpaceSignature(accPubKey.Account(), req.SpaceKey, req.AccountSignature); err != nil {
+		return
+	}
+	if req.Policy == nil {
+		return fmt.Errorf("push: policy is required")
+	}
+	if _, ok := pushapi.SpacePolicyMode_name[int32(req.Policy.Mode)]; !ok {
+		return fmt.Errorf("push: unexpected policy mode: %d", req.Policy.Mode)
+	}
+	return spaceErr(p.spaceRepo.SetPolicy(ctx, domain.Space{
+		Id:     base58.Encode(req.SpaceKey),
+		Author: accPubKey.Account(),
+	}, domain.SpacePolicy{
+		Mode:               domain.SpacePolicyMode(req.Policy.Mode),
+		MaxFanOutPerMinute: int(req.Policy.MaxFanOutPerMinute),
+	}))
+}
+
+// checkSpaceAdminRequest verifies the space signature of the caller and the target account,
+// the returned space holds the caller as the author to match against the space admins
+func checkSpaceAdminRequest(ctx context.Context, key, signature []byte, accountId string) (space domain.Space, err error) {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return
+	}
+	if err = checkSpaceSignature(accPubKey.Account(), key, signature); err != nil {
+		return
+	}
+	if _, err = crypto.DecodeAccountAddress(accountId); err != nil {
+		err = fmt.Errorf("push: invalid accountId: %w", err)
+		return
+	}
+	return domain.Space{
+		Id:     base58.Encode(key),
+		Author: accPubKey.Account(),
+	}, nil
+}
+
+func spaceErr(err error) error {
+	switch {
+	case errors.Is(err, spacerepo.ErrSpaceNotFound):
+		return pushapi.ErrSpaceNotFound
+	case errors.Is(err, spacerepo.ErrNotSpaceAdmin):
+		return pushapi.ErrNotSpaceAdmin
+	case errors.Is(err, spacerepo.ErrLastSpaceAdmin):
+		return pushapi.ErrLastSpaceAdmin
+	case errors.Is(err, spacerepo.ErrSpaceExists):
+		return pushapi.ErrSpaceExists
+	}
+	return err
 }
 
 func checkSpaceSignature(identity string, spaceKey, signature []byte) error {
@@ -219,32 +720,104 @@ func checkSpaceSignature(identity string, spaceKey, signature []byte) error {
 	return nil
 }
 
-func (p *push) Subscriptions(ctx context.Context) (topics *pushapi.Topics, err error) {
+func (p *push) Subscriptions(ctx context.Context) (topics *pushapi.Topics, version int64, err error) {
+	accPubKey, err := peer.CtxPubKey(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	account, err := p.accountRepo.GetAccount(ctx, accPubKey.Account())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	activeTopics, expires := account.ActiveTopics(time.Now().Unix())
+	if topics, err = convertToApiTopics(activeTopics, expires); err != nil {
+		return nil, 0, err
+	}
+	return topics, account.Version, nil
+}
+
+func (p *push) SyncSubscriptions(ctx context.Context, version int64) (resp *pushapi.SyncSubscriptionsResponse, err error) {
 	accPubKey, err := peer.CtxPubKey(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	dTopics, err := p.accountRepo.GetTopicsByAccountId(ctx, accPubKey.Account())
+	account, err := p.accountRepo.GetAccount(ctx, accPubKey.Account())
 	if err != nil {
 		return nil, err
 	}
 
-	topics = &pushapi.Topics{
-		Topics: make([]*pushapi.Topic, len(dTopics)),
+	resp = &pushapi.SyncSubscriptionsResponse{Version: account.Version}
+	activeTopics, expires := account.ActiveTopics(time.Now().Unix())
+	added, removed, ok := topicsChangesSince(account, version)
+	if !ok {
+		resp.Full = true
+		added = activeTopics
+	} else {
+		// expired topics stay in the log until they are removed, the client gets them as removed
+		added = slices.DeleteFunc(added, func(topic domain.Topic) bool {
+			if !slices.Contains(activeTopics, topic) {
+				removed = append(removed, topic)
+				return true
+			}
+			return false
+		})
+	}
+	if resp.Added, err = convertToApiTopics(added, expires); err != nil {
+		return nil, err
+	}
+	if resp.Removed, err = convertToApiTopics(removed, nil); err != nil {
+		return nil, err
 	}
+	return
+}
 
-	for i, dtopic := range dTopics {
-		raw, err := dtopic.SpaceKeyRaw()
-		if err != nil {
-			return nil, err
+// topicsChangesSince folds the account change log after the given version,
+// returns false when the log doesn't cover the version or the topics were replaced after it
+func topicsChangesSince(account domain.Account, version int64) (added, removed []domain.Topic, ok bool) {
+	// accounts created before versioning have topics at version 0 without a log, so the log can't prove it covers 0
+	if version <= 0 {
+		return nil, nil, false
+	}
+	if version == account.Version {
+		return nil, nil, true
+	}
+	// the version of the first log entry
+	firstVersion := account.Version - int64(len(account.Changes)) + 1
+	if version > account.Version || version < firstVersion-1 {
+		return nil, nil, false
+	}
+	var (
+		order   []domain.Topic
+		present = make(map[domain.Topic]bool)
+	)
+	for _, change := range account.Changes[version-firstVersion+1:] {
+		if change.Reset {
+			return nil, nil, false
 		}
-		topics.Topics[i] = &pushapi.Topic{
-			SpaceKey: raw,
-			Topic:    dtopic.Topic(),
+		for _, topic := range change.Added {
+			if _, ok := present[topic]; !ok {
+				order = append(order, topic)
+			}
+			present[topic] = true
+		}
+		for _, topic := range change.Removed {
+			if _, ok := present[topic]; !ok {
+				order = append(order, topic)
+			}
+			present[topic] = false
 		}
 	}
-	return
+	for _, topic := range order {
+		if present[topic] {
+			added = append(added, topic)
+		} else {
+			removed = append(removed, topic)
+		}
+	}
+	return added, removed, true
 }
 
 func (p *push) Subscribe(ctx context.Context, topics *pushapi.Topics) error {
@@ -253,47 +826,70 @@ func (p *push) Subscribe(ctx context.Context, topics *pushapi.Topics) error {
 		return err
 	}
 
-	dTopics, err := convertTopics(topics)
-	if err != nil {
+	if len(topics.GetTopics()) == 0 {
+		return nil
+	}
+	if err = p.checkRequestLimits(topics); err != nil {
 		return err
 	}
-	return p.accountRepo.SetAccountTopics(ctx, accPubKey.Account(), dTopics)
-
-}
-
-func (p *push) Unsubscribe(ctx context.Context, topics *pushapi.Topics) error {
-	accPubKey, err := peer.CtxPubKey(ctx)
+	now := time.Now().Unix()
+	for _, topic := range topics.Topics {
+		if topic.Expires != 0 && topic.Expires <= now {
+			return fmt.Errorf("push: topic expiry is not in the future")
+		}
+	}
+	account, err := p.accountRepo.GetAccount(ctx, accPubKey.Account())
 	if err != nil {
 		return err
 	}
+	currentTopics, _ := account.ActiveTopics(now)
+	if err = p.checkAccountLimits(currentTopics, topics); err != nil {
+		return err
+	}
 
 	dTopics, err := convertTopics(topics)
 	if err != nil {
 		return err
 	}
-
+	if dTopics, err = p.filterRegisteredTopics(ctx, dTopics); err != nil {
+		return err
+	}
 	if len(dTopics) == 0 {
-		return nil
+		return pushapi.ErrNoValidTopics
+	}
+	var expiring []domain.TopicExpiry
+	for _, topic := range topics.Topics {
+		if topic.Expires == 0 {
+			continue
+		}
+		dTopic := domain.NewTopic(topic.SpaceKey, topic.Topic)
+		if slices.Contains(dTopics, dTopic) {
+			expiring = append(expiring, domain.TopicExpiry{Topic: dTopic, Expires: topic.Expires})
+		}
 	}
+	return p.accountRepo.AddAccountTopics(ctx, accPubKey.Account(), dTopics, expiring)
+}
 
-	currentTopics, err := p.accountRepo.GetTopicsByAccountId(ctx, accPubKey.Account())
+func (p *push) Unsubscribe(ctx context.Context, topics *pushapi.Topics) error {
+	accPubKey, err := peer.CtxPubKey(ctx)
 	if err != nil {
 		return err
 	}
 
-	removeSet := make(map[domain.Topic]struct{}, len(dTopics))
-	for _, t := range dTopics {
-		removeSet[t] = struct{}{}
+	if err = p.checkRequestLimits(topics); err != nil {
+		return err
 	}
 
-	remainingTopics := make([]domain.Topic, 0, len(currentTopics))
-	for _, t := range currentTopics {
-		if _, shouldRemove := removeSet[t]; !shouldRemove {
-			remainingTopics = append(remainingTopics, t)
-		}
+	dTopics, err := convertTopics(topics)
+	if err != nil {
+		return err
+	}
+
+	if len(dTopics) == 0 {
+		return nil
 	}
 
-	return p.accountRepo.SetAccountTopics(ctx, accPubKey.Account(), remainingTopics)
+	return p.accountRepo.RemoveAccountTopics(ctx, accPubKey.Account(), dTopics)
 }
 
 func convertTopics(topics *pushapi.Topics) (result []domain.Topic, err error) {
@@ -332,3 +928,21 @@ func convertTopics(topics *pushapi.Topics) (result []domain.Topic, err error) {
 	}
 	return
 }
+
+func convertToApiTopics(dTopics []domain.Topic, expires map[domain.Topic]int64) (topics *pushapi.Topics, err error) {
+	topics = &pushapi.Topics{
+		Topics: make([]*pushapi.Topic, len(dTopics)),
+	}
+	for i, dtopic := range dTopics {
+		raw, err := dtopic.SpaceKeyRaw()
+		if err != nil {
+			return nil, err
+		}
+		topics.Topics[i] = &pushapi.Topic{
+			SpaceKey: raw,
+			Topic:    dtopic.Topic(),
+			Expires:  expires[dtopic],
+		}
+	}
+	return
+}
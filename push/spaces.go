@@ -0,0 +1,90 @@
+package push
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+
+	"github.com/anyproto/anytype-push-server/domain"
+	"github.com/anyproto/anytype-push-server/pushclient/pushapi"
+	"github.com/anyproto/anytype-push-server/ratelimit"
+)
+
+// verifySpace reports whether topics of the space are accepted only when the space is registered.
+// Spaces listed in the config are checked first, others are enabled by their id hash,
+// so raising the rollout percent keeps the already verified spaces.
+func (p *push) verifySpace(spaceKey string) bool {
+	if slices.Contains(p.conf.SpaceVerification.Disabled, spaceKey) {
+		return false
+	}
+	if slices.Contains(p.conf.SpaceVerification.Enabled, spaceKey) {
+		return true
+	}
+	percent := p.conf.SpaceVerification.RolloutPercent
+	if percent <= 0 {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(spaceKey))
+	return int(h.Sum32()%100) < percent
+}
+
+// filterRegisteredTopics drops topics of unregistered spaces that are subject to verification
+func (p *push) filterRegisteredTopics(ctx context.Context, topics []domain.Topic) ([]domain.Topic, error) {
+	var spaceKeys []string
+	for _, topic := range topics {
+		spaceKey := topic.SpaceKeyBase58()
+		if p.verifySpace(spaceKey) && !slices.Contains(spaceKeys, spaceKey) {
+			spaceKeys = append(spaceKeys, spaceKey)
+		}
+	}
+	if len(spaceKeys) == 0 {
+		return topics, nil
+	}
+	validSpaceKeys, err := p.spaceRepo.ExistedSpaces(ctx, spaceKeys)
+	if err != nil {
+		return nil, err
+	}
+	var filtered = make([]domain.Topic, 0, len(topics))
+	for _, topic := range topics {
+		spaceKey := topic.SpaceKeyBase58()
+		if !slices.Contains(spaceKeys, spaceKey) || slices.Contains(validSpaceKeys, spaceKey) {
+			filtered = append(filtered, topic)
+		}
+	}
+	return filtered, nil
+}
+
+// applySpacePolicies drops topics restricted by the space policies.
+// Spaces that spent the fan-out budget are rejected here, the budget itself is spent by the sender
+// that caps subscribers to what is left of it.
+func (p *push) applySpacePolicies(ctx context.Context, topics []domain.Topic) ([]domain.Topic, error) {
+	policies, err := p.spaceRepo.GetPolicies(ctx, domain.SpaceKeys(topics))
+	if err != nil {
+		return nil, err
+	}
+	topics = slices.DeleteFunc(topics, func(topic domain.Topic) bool {
+		return !policies[topic.SpaceKeyBase58()].AllowsTopic(topic)
+	})
+	if len(topics) == 0 {
+		return nil, pushapi.ErrSpacePolicyRestricted
+	}
+	for _, spaceKey := range domain.SpaceKeys(topics) {
+		maxFanOut := policies[spaceKey].MaxFanOutPerMinute
+		if maxFanOut <= 0 {
+			continue
+		}
+		key := ratelimit.Key{Scope: ratelimit.ScopeSpaceFanOut, Id: spaceKey}
+		_, retryAfter, err := p.rateLimit.AllowN(ctx, key, 0, ratelimit.PerMinute(maxFanOut))
+		if err != nil {
+			return nil, err
+		}
+		if retryAfter > 0 {
+			return nil, pushapi.NewRateLimitedError(retryAfter)
+		}
+	}
+	return topics, nil
+}
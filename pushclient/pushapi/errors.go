@@ -2,6 +2,10 @@ package pushapi
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/anyproto/any-sync/net/rpc/rpcerr"
 )
@@ -9,9 +13,53 @@ import (
 var (
 	errGroup = rpcerr.ErrGroup(ErrCodes_ErrorOffset)
 
-	ErrUnexpected            = errGroup.Register(errors.New("unexpected error"), uint64(ErrCodes_Unexpected))
-	ErrInvalidSignature      = errGroup.Register(errors.New("invalid signature"), uint64(ErrCodes_InvalidSignature))
-	ErrInvalidTopicSignature = errGroup.Register(errors.New("invalid topic signature"), uint64(ErrCodes_InvalidTopicSignature))
-	ErrSpaceExists           = errGroup.Register(errors.New("space already exists"), uint64(ErrCodes_SpaceExists))
-	ErrNoValidTopics         = errGroup.Register(errors.New("no valid topics"), uint64(ErrCodes_NoValidTopics))
+	ErrUnexpected             = errGroup.Register(errors.New("unexpected error"), uint64(ErrCodes_Unexpected))
+	ErrInvalidSignature       = errGroup.Register(errors.New("invalid signature"), uint64(ErrCodes_InvalidSignature))
+	ErrInvalidTopicSignature  = errGroup.Register(errors.New("invalid topic signature"), uint64(ErrCodes_InvalidTopicSignature))
+	ErrSpaceExists            = errGroup.Register(errors.New("space already exists"), uint64(ErrCodes_SpaceExists))
+	ErrNoValidTopics          = errGroup.Register(errors.New("no valid topics"), uint64(ErrCodes_NoValidTopics))
+	ErrDeviceNotFound         = errGroup.Register(errors.New("device not found"), uint64(ErrCodes_DeviceNotFound))
+	ErrInvalidToken           = errGroup.Register(errors.New("invalid token"), uint64(ErrCodes_InvalidToken))
+	ErrVersionConflict        = errGroup.Register(errors.New("version conflict"), uint64(ErrCodes_VersionConflict))
+	ErrTooManyTopics          = errGroup.Register(errors.New("too many topics"), uint64(ErrCodes_TooManyTopics))
+	ErrTooManyTopicsInRequest = errGroup.Register(errors.New("too many topics in request"), uint64(ErrCodes_TooManyTopicsInRequest))
+	ErrTopicTooLong           = errGroup.Register(errors.New("topic too long"), uint64(ErrCodes_TopicTooLong))
+	ErrTooManySpaces          = errGroup.Register(errors.New("too many spaces"), uint64(ErrCodes_TooManySpaces))
+	ErrRateLimited            = errGroup.Register(errors.New("rate limited"), uint64(ErrCodes_RateLimited))
+	ErrAccountBanned          = errGroup.Register(errors.New("account banned"), uint64(ErrCodes_AccountBanned))
+	ErrInvalidEnvelope        = errGroup.Register(errors.New("invalid envelope"), uint64(ErrCodes_InvalidEnvelope))
+	ErrMessageExpired         = errGroup.Register(errors.New("message expired"), uint64(ErrCodes_MessageExpired))
+	ErrMessageReplayed        = errGroup.Register(errors.New("message replayed"), uint64(ErrCodes_MessageReplayed))
+	ErrSpaceNotFound          = errGroup.Register(errors.New("space not found"), uint64(ErrCodes_SpaceNotFound))
+	ErrNotSpaceAdmin          = errGroup.Register(errors.New("not a space admin"), uint64(ErrCodes_NotSpaceAdmin))
+	ErrLastSpaceAdmin         = errGroup.Register(errors.New("can't remove the last space admin"), uint64(ErrCodes_LastSpaceAdmin))
+	ErrSpacePolicyRestricted  = errGroup.Register(errors.New("restricted by the space policy"), uint64(ErrCodes_SpacePolicyRestricted))
 )
+
+const retryAfterPrefix = "retry after "
+
+// NewRateLimitedError returns ErrRateLimited with the retry-after hint in the message
+func NewRateLimitedError(retryAfter time.Duration) error {
+	return fmt.Errorf("%w: %s%dms", ErrRateLimited, retryAfterPrefix, retryAfter.Milliseconds())
+}
+
+// RetryAfter extracts the retry-after hint from the error created by NewRateLimitedError.
+// On the client it must be called with the error returned by the drpc client, rpcerr.Unwrap drops the message.
+func RetryAfter(err error) (retryAfter time.Duration, ok bool) {
+	if err == nil {
+		return
+	}
+	msg := err.Error()
+	idx := strings.LastIndex(msg, retryAfterPrefix)
+	if idx == -1 {
+		return
+	}
+	ms, err := strconv.ParseInt(strings.TrimSuffix(msg[idx+len(retryAfterPrefix):], "ms"), 10, 64)
+	if err != nil {
+		return
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// Deprecated: use ErrNotSpaceAdmin, spaces may have several admins besides the author
+var ErrNotSpaceAuthor = ErrNotSpaceAdmin
@@ -24,31 +24,84 @@ const (
 type ErrCodes int32
 
 const (
-	ErrCodes_Unexpected            ErrCodes = 0
-	ErrCodes_InvalidSignature      ErrCodes = 1
-	ErrCodes_InvalidTopicSignature ErrCodes = 2
-	ErrCodes_SpaceExists           ErrCodes = 3
-	ErrCodes_NoValidTopics         ErrCodes = 4
+	ErrCodes_Unexpected             ErrCodes = 0
+	ErrCodes_InvalidSignature       ErrCodes = 1
+	ErrCodes_InvalidTopicSignature  ErrCodes = 2
+	ErrCodes_SpaceExists            ErrCodes = 3
+	ErrCodes_NoValidTopics          ErrCodes = 4
+	ErrCodes_DeviceNotFound         ErrCodes = 5
+	ErrCodes_InvalidToken           ErrCodes = 6
+	ErrCodes_VersionConflict        ErrCodes = 7
+	ErrCodes_TooManyTopics          ErrCodes = 8
+	ErrCodes_TooManyTopicsInRequest ErrCodes = 9
+	ErrCodes_TopicTooLong           ErrCodes = 10
+	ErrCodes_TooManySpaces          ErrCodes = 11
+	// the error message carries the retry-after hint, see pushapi.RetryAfter
+	ErrCodes_RateLimited     ErrCodes = 12
+	ErrCodes_AccountBanned   ErrCodes = 13
+	ErrCodes_InvalidEnvelope ErrCodes = 14
+	ErrCodes_MessageExpired  ErrCodes = 15
+	ErrCodes_MessageReplayed ErrCodes = 16
+	ErrCodes_SpaceNotFound   ErrCodes = 17
+	ErrCodes_NotSpaceAdmin   ErrCodes = 18
+	// deprecated: renamed to NotSpaceAdmin
+	ErrCodes_NotSpaceAuthor        ErrCodes = 18
+	ErrCodes_LastSpaceAdmin        ErrCodes = 19
+	ErrCodes_SpacePolicyRestricted ErrCodes = 20
 	ErrCodes_ErrorOffset           ErrCodes = 1200
 )
 
 // Enum value maps for ErrCodes.
 var (
 	ErrCodes_name = map[int32]string{
-		0:    "Unexpected",
-		1:    "InvalidSignature",
-		2:    "InvalidTopicSignature",
-		3:    "SpaceExists",
-		4:    "NoValidTopics",
+		0:  "Unexpected",
+		1:  "InvalidSignature",
+		2:  "InvalidTopicSignature",
+		3:  "SpaceExists",
+		4:  "NoValidTopics",
+		5:  "DeviceNotFound",
+		6:  "InvalidToken",
+		7:  "VersionConflict",
+		8:  "TooManyTopics",
+		9:  "TooManyTopicsInRequest",
+		10: "TopicTooLong",
+		11: "TooManySpaces",
+		12: "RateLimited",
+		13: "AccountBanned",
+		14: "InvalidEnvelope",
+		15: "MessageExpired",
+		16: "MessageReplayed",
+		17: "SpaceNotFound",
+		18: "NotSpaceAdmin",
+		// Duplicate value: 18: "NotSpaceAuthor",
+		19:   "LastSpaceAdmin",
+		20:   "SpacePolicyRestricted",
 		1200: "ErrorOffset",
 	}
 	ErrCodes_value = map[string]int32{
-		"Unexpected":            0,
-		"InvalidSignature":      1,
-		"InvalidTopicSignature": 2,
-		"SpaceExists":           3,
-		"NoValidTopics":         4,
-		"ErrorOffset":           1200,
+		"Unexpected":             0,
+		"InvalidSignature":       1,
+		"InvalidTopicSignature":  2,
+		"SpaceExists":            3,
+		"NoValidTopics":          4,
+		"DeviceNotFound":         5,
+		"InvalidToken":           6,
+		"VersionConflict":        7,
+		"TooManyTopics":          8,
+		"TooManyTopicsInRequest": 9,
+		"TopicTooLong":           10,
+		"TooManySpaces":          11,
+		"RateLimited":            12,
+		"AccountBanned":          13,
+		"InvalidEnvelope":        14,
+		"MessageExpired":         15,
+		"MessageReplayed":        16,
+		"SpaceNotFound":          17,
+		"NotSpaceAdmin":          18,
+		"NotSpaceAuthor":         18,
+		"LastSpaceAdmin":         19,
+		"SpacePolicyRestricted":  20,
+		"ErrorOffset":            1200,
 	}
 )
 
@@ -125,6 +178,291 @@ func (Platform) EnumDescriptor() ([]byte, []int) {
 	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{1}
 }
 
+type TokenStatus int32
+
+const (
+	TokenStatus_Valid   TokenStatus = 0
+	TokenStatus_Invalid TokenStatus = 1
+)
+
+// Enum value maps for TokenStatus.
+var (
+	TokenStatus_name = map[int32]string{
+		0: "Valid",
+		1: "Invalid",
+	}
+	TokenStatus_value = map[string]int32{
+		"Valid":   0,
+		"Invalid": 1,
+	}
+)
+
+func (x TokenStatus) Enum() *TokenStatus {
+	p := new(TokenStatus)
+	*p = x
+	return p
+}
+
+func (x TokenStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TokenStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_pushclient_pushapi_protos_push_proto_enumTypes[2].Descriptor()
+}
+
+func (TokenStatus) Type() protoreflect.EnumType {
+	return &file_pushclient_pushapi_protos_push_proto_enumTypes[2]
+}
+
+func (x TokenStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TokenStatus.Descriptor instead.
+func (TokenStatus) EnumDescriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{2}
+}
+
+type BuildChannel int32
+
+const (
+	BuildChannel_Release BuildChannel = 0
+	BuildChannel_Beta    BuildChannel = 1
+	BuildChannel_Dev     BuildChannel = 2
+)
+
+// Enum value maps for BuildChannel.
+var (
+	BuildChannel_name = map[int32]string{
+		0: "Release",
+		1: "Beta",
+		2: "Dev",
+	}
+	BuildChannel_value = map[string]int32{
+		"Release": 0,
+		"Beta":    1,
+		"Dev":     2,
+	}
+)
+
+func (x BuildChannel) Enum() *BuildChannel {
+	p := new(BuildChannel)
+	*p = x
+	return p
+}
+
+func (x BuildChannel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BuildChannel) Descriptor() protoreflect.EnumDescriptor {
+	return file_pushclient_pushapi_protos_push_proto_enumTypes[3].Descriptor()
+}
+
+func (BuildChannel) Type() protoreflect.EnumType {
+	return &file_pushclient_pushapi_protos_push_proto_enumTypes[3]
+}
+
+func (x BuildChannel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BuildChannel.Descriptor instead.
+func (BuildChannel) EnumDescriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{3}
+}
+
+type ApnsEnvironment int32
+
+const (
+	ApnsEnvironment_Production ApnsEnvironment = 0
+	ApnsEnvironment_Sandbox    ApnsEnvironment = 1
+)
+
+// Enum value maps for ApnsEnvironment.
+var (
+	ApnsEnvironment_name = map[int32]string{
+		0: "Production",
+		1: "Sandbox",
+	}
+	ApnsEnvironment_value = map[string]int32{
+		"Production": 0,
+		"Sandbox":    1,
+	}
+)
+
+func (x ApnsEnvironment) Enum() *ApnsEnvironment {
+	p := new(ApnsEnvironment)
+	*p = x
+	return p
+}
+
+func (x ApnsEnvironment) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ApnsEnvironment) Descriptor() protoreflect.EnumDescriptor {
+	return file_pushclient_pushapi_protos_push_proto_enumTypes[4].Descriptor()
+}
+
+func (ApnsEnvironment) Type() protoreflect.EnumType {
+	return &file_pushclient_pushapi_protos_push_proto_enumTypes[4]
+}
+
+func (x ApnsEnvironment) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ApnsEnvironment.Descriptor instead.
+func (ApnsEnvironment) EnumDescriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{4}
+}
+
+type SpaceType int32
+
+const (
+	SpaceType_Regular SpaceType = 0
+	// both participants of a 1-1 space register it with the shared space key
+	SpaceType_OneToOne SpaceType = 1
+)
+
+// Enum value maps for SpaceType.
+var (
+	SpaceType_name = map[int32]string{
+		0: "Regular",
+		1: "OneToOne",
+	}
+	SpaceType_value = map[string]int32{
+		"Regular":  0,
+		"OneToOne": 1,
+	}
+)
+
+func (x SpaceType) Enum() *SpaceType {
+	p := new(SpaceType)
+	*p = x
+	return p
+}
+
+func (x SpaceType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SpaceType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pushclient_pushapi_protos_push_proto_enumTypes[5].Descriptor()
+}
+
+func (SpaceType) Type() protoreflect.EnumType {
+	return &file_pushclient_pushapi_protos_push_proto_enumTypes[5]
+}
+
+func (x SpaceType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SpaceType.Descriptor instead.
+func (SpaceType) EnumDescriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{5}
+}
+
+type SpacePolicyMode int32
+
+const (
+	SpacePolicyMode_AllNotifications SpacePolicyMode = 0
+	// only topics named by an account id are notified
+	SpacePolicyMode_MentionsOnly    SpacePolicyMode = 1
+	SpacePolicyMode_NoNotifications SpacePolicyMode = 2
+)
+
+// Enum value maps for SpacePolicyMode.
+var (
+	SpacePolicyMode_name = map[int32]string{
+		0: "AllNotifications",
+		1: "MentionsOnly",
+		2: "NoNotifications",
+	}
+	SpacePolicyMode_value = map[string]int32{
+		"AllNotifications": 0,
+		"MentionsOnly":     1,
+		"NoNotifications":  2,
+	}
+)
+
+func (x SpacePolicyMode) Enum() *SpacePolicyMode {
+	p := new(SpacePolicyMode)
+	*p = x
+	return p
+}
+
+func (x SpacePolicyMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SpacePolicyMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_pushclient_pushapi_protos_push_proto_enumTypes[6].Descriptor()
+}
+
+func (SpacePolicyMode) Type() protoreflect.EnumType {
+	return &file_pushclient_pushapi_protos_push_proto_enumTypes[6]
+}
+
+func (x SpacePolicyMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SpacePolicyMode.Descriptor instead.
+func (SpacePolicyMode) EnumDescriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{6}
+}
+
+type PayloadFormat int32
+
+const (
+	// payload is signed as is without replay protection
+	PayloadFormat_Legacy   PayloadFormat = 0
+	PayloadFormat_Envelope PayloadFormat = 1
+)
+
+// Enum value maps for PayloadFormat.
+var (
+	PayloadFormat_name = map[int32]string{
+		0: "Legacy",
+		1: "Envelope",
+	}
+	PayloadFormat_value = map[string]int32{
+		"Legacy":   0,
+		"Envelope": 1,
+	}
+)
+
+func (x PayloadFormat) Enum() *PayloadFormat {
+	p := new(PayloadFormat)
+	*p = x
+	return p
+}
+
+func (x PayloadFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PayloadFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_pushclient_pushapi_protos_push_proto_enumTypes[7].Descriptor()
+}
+
+func (PayloadFormat) Type() protoreflect.EnumType {
+	return &file_pushclient_pushapi_protos_push_proto_enumTypes[7]
+}
+
+func (x PayloadFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PayloadFormat.Descriptor instead.
+func (PayloadFormat) EnumDescriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{7}
+}
+
 type Topics struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Topics        []*Topic               `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
@@ -172,9 +510,13 @@ func (x *Topics) GetTopics() []*Topic {
 type Topic struct {
 	state    protoimpl.MessageState `protogen:"open.v1"`
 	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
-	Topic    string                 `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	// "*" subscribes to all topics of the space
+	Topic string `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
 	// space private key
-	Signature     []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// unix time after which the subscription is removed, 0 means a permanent subscription.
+	// Must be in the future, SubscribeAll rejects expiring topics
+	Expires       int64 `protobuf:"varint,4,opt,name=expires,proto3" json:"expires,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -230,12 +572,23 @@ func (x *Topic) GetSignature() []byte {
 	return nil
 }
 
+func (x *Topic) GetExpires() int64 {
+	if x != nil {
+		return x.Expires
+	}
+	return 0
+}
+
 type SetTokenRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Platform      Platform               `protobuf:"varint,1,opt,name=platform,proto3,enum=pushproto.Platform" json:"platform,omitempty"`
-	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Platform        Platform               `protobuf:"varint,1,opt,name=platform,proto3,enum=pushproto.Platform" json:"platform,omitempty"`
+	Token           string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	AppVersion      string                 `protobuf:"bytes,3,opt,name=appVersion,proto3" json:"appVersion,omitempty"`
+	OsVersion       string                 `protobuf:"bytes,4,opt,name=osVersion,proto3" json:"osVersion,omitempty"`
+	BuildChannel    BuildChannel           `protobuf:"varint,5,opt,name=buildChannel,proto3,enum=pushproto.BuildChannel" json:"buildChannel,omitempty"`
+	ApnsEnvironment ApnsEnvironment        `protobuf:"varint,6,opt,name=apnsEnvironment,proto3,enum=pushproto.ApnsEnvironment" json:"apnsEnvironment,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *SetTokenRequest) Reset() {
@@ -275,37 +628,1135 @@ func (x *SetTokenRequest) GetPlatform() Platform {
 	return Platform_IOS
 }
 
-func (x *SetTokenRequest) GetToken() string {
+func (x *SetTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *SetTokenRequest) GetAppVersion() string {
+	if x != nil {
+		return x.AppVersion
+	}
+	return ""
+}
+
+func (x *SetTokenRequest) GetOsVersion() string {
+	if x != nil {
+		return x.OsVersion
+	}
+	return ""
+}
+
+func (x *SetTokenRequest) GetBuildChannel() BuildChannel {
+	if x != nil {
+		return x.BuildChannel
+	}
+	return BuildChannel_Release
+}
+
+func (x *SetTokenRequest) GetApnsEnvironment() ApnsEnvironment {
+	if x != nil {
+		return x.ApnsEnvironment
+	}
+	return ApnsEnvironment_Production
+}
+
+type ListDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesRequest) ProtoMessage() {}
+
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{3}
+}
+
+type ListDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesResponse) ProtoMessage() {}
+
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type Device struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PeerId          string                 `protobuf:"bytes,1,opt,name=peerId,proto3" json:"peerId,omitempty"`
+	Platform        Platform               `protobuf:"varint,2,opt,name=platform,proto3,enum=pushproto.Platform" json:"platform,omitempty"`
+	Created         int64                  `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`
+	Updated         int64                  `protobuf:"varint,4,opt,name=updated,proto3" json:"updated,omitempty"`
+	AppVersion      string                 `protobuf:"bytes,5,opt,name=appVersion,proto3" json:"appVersion,omitempty"`
+	Status          TokenStatus            `protobuf:"varint,6,opt,name=status,proto3,enum=pushproto.TokenStatus" json:"status,omitempty"`
+	OsVersion       string                 `protobuf:"bytes,7,opt,name=osVersion,proto3" json:"osVersion,omitempty"`
+	BuildChannel    BuildChannel           `protobuf:"varint,8,opt,name=buildChannel,proto3,enum=pushproto.BuildChannel" json:"buildChannel,omitempty"`
+	ApnsEnvironment ApnsEnvironment        `protobuf:"varint,9,opt,name=apnsEnvironment,proto3,enum=pushproto.ApnsEnvironment" json:"apnsEnvironment,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Device) GetPeerId() string {
+	if x != nil {
+		return x.PeerId
+	}
+	return ""
+}
+
+func (x *Device) GetPlatform() Platform {
+	if x != nil {
+		return x.Platform
+	}
+	return Platform_IOS
+}
+
+func (x *Device) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *Device) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *Device) GetAppVersion() string {
+	if x != nil {
+		return x.AppVersion
+	}
+	return ""
+}
+
+func (x *Device) GetStatus() TokenStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TokenStatus_Valid
+}
+
+func (x *Device) GetOsVersion() string {
+	if x != nil {
+		return x.OsVersion
+	}
+	return ""
+}
+
+func (x *Device) GetBuildChannel() BuildChannel {
+	if x != nil {
+		return x.BuildChannel
+	}
+	return BuildChannel_Release
+}
+
+func (x *Device) GetApnsEnvironment() ApnsEnvironment {
+	if x != nil {
+		return x.ApnsEnvironment
+	}
+	return ApnsEnvironment_Production
+}
+
+type RevokeDeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PeerId        string                 `protobuf:"bytes,1,opt,name=peerId,proto3" json:"peerId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeDeviceRequest) Reset() {
+	*x = RevokeDeviceRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeDeviceRequest) ProtoMessage() {}
+
+func (x *RevokeDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeDeviceRequest.ProtoReflect.Descriptor instead.
+func (*RevokeDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RevokeDeviceRequest) GetPeerId() string {
+	if x != nil {
+		return x.PeerId
+	}
+	return ""
+}
+
+type DeleteAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountRequest) Reset() {
+	*x = DeleteAccountRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountRequest) ProtoMessage() {}
+
+func (x *DeleteAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAccountRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{7}
+}
+
+type ExportAccountDataRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// return token values as is instead of masked ones
+	RevealTokens  bool `protobuf:"varint,1,opt,name=revealTokens,proto3" json:"revealTokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportAccountDataRequest) Reset() {
+	*x = ExportAccountDataRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportAccountDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportAccountDataRequest) ProtoMessage() {}
+
+func (x *ExportAccountDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportAccountDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportAccountDataRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ExportAccountDataRequest) GetRevealTokens() bool {
+	if x != nil {
+		return x.RevealTokens
+	}
+	return false
+}
+
+type ExportAccountDataResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// version of the data format
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// json encoded export
+	Data          []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportAccountDataResponse) Reset() {
+	*x = ExportAccountDataResponse{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportAccountDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportAccountDataResponse) ProtoMessage() {}
+
+func (x *ExportAccountDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportAccountDataResponse.ProtoReflect.Descriptor instead.
+func (*ExportAccountDataResponse) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExportAccountDataResponse) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ExportAccountDataResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type BlockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// account whose notifications the caller doesn't want to receive
+	AccountId     string `protobuf:"bytes,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockRequest) Reset() {
+	*x = BlockRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockRequest) ProtoMessage() {}
+
+func (x *BlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockRequest.ProtoReflect.Descriptor instead.
+func (*BlockRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BlockRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type UnblockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnblockRequest) Reset() {
+	*x = UnblockRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnblockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnblockRequest) ProtoMessage() {}
+
+func (x *UnblockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnblockRequest.ProtoReflect.Descriptor instead.
+func (*UnblockRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UnblockRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type ListBlockedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedRequest) Reset() {
+	*x = ListBlockedRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedRequest) ProtoMessage() {}
+
+func (x *ListBlockedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockedRequest.ProtoReflect.Descriptor instead.
+func (*ListBlockedRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{12}
+}
+
+type ListBlockedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountIds    []string               `protobuf:"bytes,1,rep,name=accountIds,proto3" json:"accountIds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedResponse) Reset() {
+	*x = ListBlockedResponse{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedResponse) ProtoMessage() {}
+
+func (x *ListBlockedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockedResponse.ProtoReflect.Descriptor instead.
+func (*ListBlockedResponse) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListBlockedResponse) GetAccountIds() []string {
+	if x != nil {
+		return x.AccountIds
+	}
+	return nil
+}
+
+type ReportNotificationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// groupId of the reported notification
+	GroupId string `protobuf:"bytes,1,opt,name=groupId,proto3" json:"groupId,omitempty"`
+	// account that sent the notification, if known
+	AccountId     string `protobuf:"bytes,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportNotificationRequest) Reset() {
+	*x = ReportNotificationRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportNotificationRequest) ProtoMessage() {}
+
+func (x *ReportNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportNotificationRequest.ProtoReflect.Descriptor instead.
+func (*ReportNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReportNotificationRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *ReportNotificationRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ReportNotificationRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CreateSpaceRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
+	// spacePrivateKey.Sign(identity)
+	AccountSignature []byte    `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
+	SpaceType        SpaceType `protobuf:"varint,3,opt,name=spaceType,proto3,enum=pushproto.SpaceType" json:"spaceType,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateSpaceRequest) Reset() {
+	*x = CreateSpaceRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSpaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSpaceRequest) ProtoMessage() {}
+
+func (x *CreateSpaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSpaceRequest.ProtoReflect.Descriptor instead.
+func (*CreateSpaceRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateSpaceRequest) GetSpaceKey() []byte {
+	if x != nil {
+		return x.SpaceKey
+	}
+	return nil
+}
+
+func (x *CreateSpaceRequest) GetAccountSignature() []byte {
+	if x != nil {
+		return x.AccountSignature
+	}
+	return nil
+}
+
+func (x *CreateSpaceRequest) GetSpaceType() SpaceType {
+	if x != nil {
+		return x.SpaceType
+	}
+	return SpaceType_Regular
+}
+
+type RemoveSpaceRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
+	// spacePrivateKey.Sign(identity)
+	AccountSignature []byte `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RemoveSpaceRequest) Reset() {
+	*x = RemoveSpaceRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveSpaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveSpaceRequest) ProtoMessage() {}
+
+func (x *RemoveSpaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveSpaceRequest.ProtoReflect.Descriptor instead.
+func (*RemoveSpaceRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RemoveSpaceRequest) GetSpaceKey() []byte {
+	if x != nil {
+		return x.SpaceKey
+	}
+	return nil
+}
+
+func (x *RemoveSpaceRequest) GetAccountSignature() []byte {
+	if x != nil {
+		return x.AccountSignature
+	}
+	return nil
+}
+
+type RotateSpaceKeyRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
+	// spacePrivateKey.Sign(identity)
+	AccountSignature []byte `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
+	NewSpaceKey      []byte `protobuf:"bytes,3,opt,name=newSpaceKey,proto3" json:"newSpaceKey,omitempty"`
+	// newSpacePrivateKey.Sign(identity)
+	NewAccountSignature []byte `protobuf:"bytes,4,opt,name=newAccountSignature,proto3" json:"newAccountSignature,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RotateSpaceKeyRequest) Reset() {
+	*x = RotateSpaceKeyRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSpaceKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSpaceKeyRequest) ProtoMessage() {}
+
+func (x *RotateSpaceKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSpaceKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateSpaceKeyRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RotateSpaceKeyRequest) GetSpaceKey() []byte {
+	if x != nil {
+		return x.SpaceKey
+	}
+	return nil
+}
+
+func (x *RotateSpaceKeyRequest) GetAccountSignature() []byte {
+	if x != nil {
+		return x.AccountSignature
+	}
+	return nil
+}
+
+func (x *RotateSpaceKeyRequest) GetNewSpaceKey() []byte {
+	if x != nil {
+		return x.NewSpaceKey
+	}
+	return nil
+}
+
+func (x *RotateSpaceKeyRequest) GetNewAccountSignature() []byte {
+	if x != nil {
+		return x.NewAccountSignature
+	}
+	return nil
+}
+
+// TransferSpaceRequest makes the account the author of the space, it becomes an admin as well
+type TransferSpaceRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
+	// spacePrivateKey.Sign(identity)
+	AccountSignature []byte `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
+	AccountId        string `protobuf:"bytes,3,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransferSpaceRequest) Reset() {
+	*x = TransferSpaceRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferSpaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferSpaceRequest) ProtoMessage() {}
+
+func (x *TransferSpaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferSpaceRequest.ProtoReflect.Descriptor instead.
+func (*TransferSpaceRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TransferSpaceRequest) GetSpaceKey() []byte {
+	if x != nil {
+		return x.SpaceKey
+	}
+	return nil
+}
+
+func (x *TransferSpaceRequest) GetAccountSignature() []byte {
+	if x != nil {
+		return x.AccountSignature
+	}
+	return nil
+}
+
+func (x *TransferSpaceRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type AddSpaceAdminRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
+	// spacePrivateKey.Sign(identity)
+	AccountSignature []byte `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
+	AccountId        string `protobuf:"bytes,3,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AddSpaceAdminRequest) Reset() {
+	*x = AddSpaceAdminRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSpaceAdminRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSpaceAdminRequest) ProtoMessage() {}
+
+func (x *AddSpaceAdminRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSpaceAdminRequest.ProtoReflect.Descriptor instead.
+func (*AddSpaceAdminRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AddSpaceAdminRequest) GetSpaceKey() []byte {
+	if x != nil {
+		return x.SpaceKey
+	}
+	return nil
+}
+
+func (x *AddSpaceAdminRequest) GetAccountSignature() []byte {
+	if x != nil {
+		return x.AccountSignature
+	}
+	return nil
+}
+
+func (x *AddSpaceAdminRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type RemoveSpaceAdminRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
+	// spacePrivateKey.Sign(identity)
+	AccountSignature []byte `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
+	AccountId        string `protobuf:"bytes,3,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RemoveSpaceAdminRequest) Reset() {
+	*x = RemoveSpaceAdminRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveSpaceAdminRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveSpaceAdminRequest) ProtoMessage() {}
+
+func (x *RemoveSpaceAdminRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveSpaceAdminRequest.ProtoReflect.Descriptor instead.
+func (*RemoveSpaceAdminRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RemoveSpaceAdminRequest) GetSpaceKey() []byte {
+	if x != nil {
+		return x.SpaceKey
+	}
+	return nil
+}
+
+func (x *RemoveSpaceAdminRequest) GetAccountSignature() []byte {
+	if x != nil {
+		return x.AccountSignature
+	}
+	return nil
+}
+
+func (x *RemoveSpaceAdminRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type SpacePolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Mode  SpacePolicyMode        `protobuf:"varint,1,opt,name=mode,proto3,enum=pushproto.SpacePolicyMode" json:"mode,omitempty"`
+	// maximum number of notified subscribers of the space per minute, 0 means no limit
+	MaxFanOutPerMinute uint32 `protobuf:"varint,2,opt,name=maxFanOutPerMinute,proto3" json:"maxFanOutPerMinute,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SpacePolicy) Reset() {
+	*x = SpacePolicy{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpacePolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpacePolicy) ProtoMessage() {}
+
+func (x *SpacePolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpacePolicy.ProtoReflect.Descriptor instead.
+func (*SpacePolicy) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SpacePolicy) GetMode() SpacePolicyMode {
+	if x != nil {
+		return x.Mode
+	}
+	return SpacePolicyMode_AllNotifications
+}
+
+func (x *SpacePolicy) GetMaxFanOutPerMinute() uint32 {
+	if x != nil {
+		return x.MaxFanOutPerMinute
+	}
+	return 0
+}
+
+type GetSpacePolicyRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
+	// spacePrivateKey.Sign(identity)
+	AccountSignature []byte `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetSpacePolicyRequest) Reset() {
+	*x = GetSpacePolicyRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpacePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpacePolicyRequest) ProtoMessage() {}
+
+func (x *GetSpacePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpacePolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetSpacePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetSpacePolicyRequest) GetSpaceKey() []byte {
+	if x != nil {
+		return x.SpaceKey
+	}
+	return nil
+}
+
+func (x *GetSpacePolicyRequest) GetAccountSignature() []byte {
 	if x != nil {
-		return x.Token
+		return x.AccountSignature
 	}
-	return ""
+	return nil
 }
 
-type CreateSpaceRequest struct {
-	state    protoimpl.MessageState `protogen:"open.v1"`
-	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
-	// spacePrivateKey.Sign(identity)
-	AccountSignature []byte `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+type GetSpacePolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        *SpacePolicy           `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateSpaceRequest) Reset() {
-	*x = CreateSpaceRequest{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[3]
+func (x *GetSpacePolicyResponse) Reset() {
+	*x = GetSpacePolicyResponse{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateSpaceRequest) String() string {
+func (x *GetSpacePolicyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSpaceRequest) ProtoMessage() {}
+func (*GetSpacePolicyResponse) ProtoMessage() {}
 
-func (x *CreateSpaceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[3]
+func (x *GetSpacePolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -316,49 +1767,44 @@ func (x *CreateSpaceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSpaceRequest.ProtoReflect.Descriptor instead.
-func (*CreateSpaceRequest) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *CreateSpaceRequest) GetSpaceKey() []byte {
-	if x != nil {
-		return x.SpaceKey
-	}
-	return nil
+// Deprecated: Use GetSpacePolicyResponse.ProtoReflect.Descriptor instead.
+func (*GetSpacePolicyResponse) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *CreateSpaceRequest) GetAccountSignature() []byte {
+func (x *GetSpacePolicyResponse) GetPolicy() *SpacePolicy {
 	if x != nil {
-		return x.AccountSignature
+		return x.Policy
 	}
 	return nil
 }
 
-type RemoveSpaceRequest struct {
+// SetSpacePolicyRequest is allowed for space admins only
+type SetSpacePolicyRequest struct {
 	state    protoimpl.MessageState `protogen:"open.v1"`
 	SpaceKey []byte                 `protobuf:"bytes,1,opt,name=spaceKey,proto3" json:"spaceKey,omitempty"`
 	// spacePrivateKey.Sign(identity)
-	AccountSignature []byte `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
+	AccountSignature []byte       `protobuf:"bytes,2,opt,name=accountSignature,proto3" json:"accountSignature,omitempty"`
+	Policy           *SpacePolicy `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
 
-func (x *RemoveSpaceRequest) Reset() {
-	*x = RemoveSpaceRequest{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[4]
+func (x *SetSpacePolicyRequest) Reset() {
+	*x = SetSpacePolicyRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveSpaceRequest) String() string {
+func (x *SetSpacePolicyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveSpaceRequest) ProtoMessage() {}
+func (*SetSpacePolicyRequest) ProtoMessage() {}
 
-func (x *RemoveSpaceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[4]
+func (x *SetSpacePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -369,25 +1815,32 @@ func (x *RemoveSpaceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveSpaceRequest.ProtoReflect.Descriptor instead.
-func (*RemoveSpaceRequest) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use SetSpacePolicyRequest.ProtoReflect.Descriptor instead.
+func (*SetSpacePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *RemoveSpaceRequest) GetSpaceKey() []byte {
+func (x *SetSpacePolicyRequest) GetSpaceKey() []byte {
 	if x != nil {
 		return x.SpaceKey
 	}
 	return nil
 }
 
-func (x *RemoveSpaceRequest) GetAccountSignature() []byte {
+func (x *SetSpacePolicyRequest) GetAccountSignature() []byte {
 	if x != nil {
 		return x.AccountSignature
 	}
 	return nil
 }
 
+func (x *SetSpacePolicyRequest) GetPolicy() *SpacePolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
 type SubscriptionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -396,7 +1849,7 @@ type SubscriptionsRequest struct {
 
 func (x *SubscriptionsRequest) Reset() {
 	*x = SubscriptionsRequest{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[5]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -408,7 +1861,7 @@ func (x *SubscriptionsRequest) String() string {
 func (*SubscriptionsRequest) ProtoMessage() {}
 
 func (x *SubscriptionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[5]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -421,19 +1874,20 @@ func (x *SubscriptionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscriptionsRequest.ProtoReflect.Descriptor instead.
 func (*SubscriptionsRequest) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{5}
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{25}
 }
 
 type SubscriptionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Topics        *Topics                `protobuf:"bytes,1,opt,name=topics,proto3" json:"topics,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubscriptionsResponse) Reset() {
 	*x = SubscriptionsResponse{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[6]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -445,7 +1899,7 @@ func (x *SubscriptionsResponse) String() string {
 func (*SubscriptionsResponse) ProtoMessage() {}
 
 func (x *SubscriptionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[6]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -458,7 +1912,7 @@ func (x *SubscriptionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscriptionsResponse.ProtoReflect.Descriptor instead.
 func (*SubscriptionsResponse) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{6}
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *SubscriptionsResponse) GetTopics() *Topics {
@@ -468,6 +1922,13 @@ func (x *SubscriptionsResponse) GetTopics() *Topics {
 	return nil
 }
 
+func (x *SubscriptionsResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type SubscribeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Topics        *Topics                `protobuf:"bytes,1,opt,name=topics,proto3" json:"topics,omitempty"`
@@ -477,7 +1938,7 @@ type SubscribeRequest struct {
 
 func (x *SubscribeRequest) Reset() {
 	*x = SubscribeRequest{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[7]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -489,7 +1950,7 @@ func (x *SubscribeRequest) String() string {
 func (*SubscribeRequest) ProtoMessage() {}
 
 func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[7]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -502,7 +1963,7 @@ func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
 func (*SubscribeRequest) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{7}
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *SubscribeRequest) GetTopics() *Topics {
@@ -521,7 +1982,7 @@ type UnsubscribeRequest struct {
 
 func (x *UnsubscribeRequest) Reset() {
 	*x = UnsubscribeRequest{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[8]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -533,7 +1994,7 @@ func (x *UnsubscribeRequest) String() string {
 func (*UnsubscribeRequest) ProtoMessage() {}
 
 func (x *UnsubscribeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[8]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -546,7 +2007,7 @@ func (x *UnsubscribeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnsubscribeRequest.ProtoReflect.Descriptor instead.
 func (*UnsubscribeRequest) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{8}
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *UnsubscribeRequest) GetTopics() *Topics {
@@ -557,15 +2018,17 @@ func (x *UnsubscribeRequest) GetTopics() *Topics {
 }
 
 type SubscribeAllRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Topics        *Topics                `protobuf:"bytes,1,opt,name=topics,proto3" json:"topics,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Topics *Topics                `protobuf:"bytes,1,opt,name=topics,proto3" json:"topics,omitempty"`
+	// if set, topics are replaced only when the current version matches
+	ExpectedVersion *int64 `protobuf:"varint,2,opt,name=expectedVersion,proto3,oneof" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *SubscribeAllRequest) Reset() {
 	*x = SubscribeAllRequest{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[9]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -577,7 +2040,7 @@ func (x *SubscribeAllRequest) String() string {
 func (*SubscribeAllRequest) ProtoMessage() {}
 
 func (x *SubscribeAllRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[9]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -590,7 +2053,7 @@ func (x *SubscribeAllRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeAllRequest.ProtoReflect.Descriptor instead.
 func (*SubscribeAllRequest) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{9}
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *SubscribeAllRequest) GetTopics() *Topics {
@@ -600,6 +2063,127 @@ func (x *SubscribeAllRequest) GetTopics() *Topics {
 	return nil
 }
 
+func (x *SubscribeAllRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type SyncSubscriptionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// version known by the client, 0 on the first sync always gets the full list
+	Version       int64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncSubscriptionsRequest) Reset() {
+	*x = SyncSubscriptionsRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncSubscriptionsRequest) ProtoMessage() {}
+
+func (x *SyncSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*SyncSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SyncSubscriptionsRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type SyncSubscriptionsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// full is true when the changes can't be restored from the version, added contains all topics in this case
+	Full          bool    `protobuf:"varint,2,opt,name=full,proto3" json:"full,omitempty"`
+	Added         *Topics `protobuf:"bytes,3,opt,name=added,proto3" json:"added,omitempty"`
+	Removed       *Topics `protobuf:"bytes,4,opt,name=removed,proto3" json:"removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncSubscriptionsResponse) Reset() {
+	*x = SyncSubscriptionsResponse{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncSubscriptionsResponse) ProtoMessage() {}
+
+func (x *SyncSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*SyncSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SyncSubscriptionsResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SyncSubscriptionsResponse) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+func (x *SyncSubscriptionsResponse) GetAdded() *Topics {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *SyncSubscriptionsResponse) GetRemoved() *Topics {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
 type NotifyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Topics        *Topics                `protobuf:"bytes,1,opt,name=topics,proto3" json:"topics,omitempty"`
@@ -611,7 +2195,7 @@ type NotifyRequest struct {
 
 func (x *NotifyRequest) Reset() {
 	*x = NotifyRequest{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[10]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -623,7 +2207,7 @@ func (x *NotifyRequest) String() string {
 func (*NotifyRequest) ProtoMessage() {}
 
 func (x *NotifyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[10]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -636,7 +2220,7 @@ func (x *NotifyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NotifyRequest.ProtoReflect.Descriptor instead.
 func (*NotifyRequest) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{10}
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *NotifyRequest) GetTopics() *Topics {
@@ -660,18 +2244,81 @@ func (x *NotifyRequest) GetGroupId() string {
 	return ""
 }
 
+type NotifyPeerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// peerId of the caller's own device to wake up
+	PeerId        string   `protobuf:"bytes,1,opt,name=peerId,proto3" json:"peerId,omitempty"`
+	Message       *Message `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	GroupId       string   `protobuf:"bytes,3,opt,name=groupId,proto3" json:"groupId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotifyPeerRequest) Reset() {
+	*x = NotifyPeerRequest{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotifyPeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotifyPeerRequest) ProtoMessage() {}
+
+func (x *NotifyPeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotifyPeerRequest.ProtoReflect.Descriptor instead.
+func (*NotifyPeerRequest) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *NotifyPeerRequest) GetPeerId() string {
+	if x != nil {
+		return x.PeerId
+	}
+	return ""
+}
+
+func (x *NotifyPeerRequest) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *NotifyPeerRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
 type Message struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	KeyId         string                 `protobuf:"bytes,1,opt,name=keyId,proto3" json:"keyId,omitempty"`
-	Payload       []byte                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
-	Signature     []byte                 `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	KeyId string                 `protobuf:"bytes,1,opt,name=keyId,proto3" json:"keyId,omitempty"`
+	// payload is a marshaled PayloadEnvelope when format is Envelope
+	Payload       []byte        `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Signature     []byte        `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Format        PayloadFormat `protobuf:"varint,4,opt,name=format,proto3,enum=pushproto.PayloadFormat" json:"format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Message) Reset() {
 	*x = Message{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[11]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -683,7 +2330,7 @@ func (x *Message) String() string {
 func (*Message) ProtoMessage() {}
 
 func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[11]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -696,7 +2343,7 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Message.ProtoReflect.Descriptor instead.
 func (*Message) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{11}
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *Message) GetKeyId() string {
@@ -720,6 +2367,75 @@ func (x *Message) GetSignature() []byte {
 	return nil
 }
 
+func (x *Message) GetFormat() PayloadFormat {
+	if x != nil {
+		return x.Format
+	}
+	return PayloadFormat_Legacy
+}
+
+type PayloadEnvelope struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Payload []byte                 `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	// unix time in seconds when the envelope was created
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// random value unique for every envelope of the account
+	Nonce         []byte `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayloadEnvelope) Reset() {
+	*x = PayloadEnvelope{}
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayloadEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadEnvelope) ProtoMessage() {}
+
+func (x *PayloadEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadEnvelope.ProtoReflect.Descriptor instead.
+func (*PayloadEnvelope) Descriptor() ([]byte, []int) {
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *PayloadEnvelope) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *PayloadEnvelope) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PayloadEnvelope) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
 type Ok struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -728,7 +2444,7 @@ type Ok struct {
 
 func (x *Ok) Reset() {
 	*x = Ok{}
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[12]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -740,7 +2456,7 @@ func (x *Ok) String() string {
 func (*Ok) ProtoMessage() {}
 
 func (x *Ok) ProtoReflect() protoreflect.Message {
-	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[12]
+	mi := &file_pushclient_pushapi_protos_push_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -753,7 +2469,7 @@ func (x *Ok) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Ok.ProtoReflect.Descriptor instead.
 func (*Ok) Descriptor() ([]byte, []int) {
-	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{12}
+	return file_pushclient_pushapi_protos_push_proto_rawDescGZIP(), []int{36}
 }
 
 var File_pushclient_pushapi_protos_push_proto protoreflect.FileDescriptor
@@ -762,60 +2478,209 @@ const file_pushclient_pushapi_protos_push_proto_rawDesc = "" +
 	"\n" +
 	"$pushclient/pushapi/protos/push.proto\x12\tpushproto\"2\n" +
 	"\x06Topics\x12(\n" +
-	"\x06topics\x18\x01 \x03(\v2\x10.pushproto.TopicR\x06topics\"W\n" +
+	"\x06topics\x18\x01 \x03(\v2\x10.pushproto.TopicR\x06topics\"q\n" +
 	"\x05Topic\x12\x1a\n" +
 	"\bspaceKey\x18\x01 \x01(\fR\bspaceKey\x12\x14\n" +
 	"\x05topic\x18\x02 \x01(\tR\x05topic\x12\x1c\n" +
-	"\tsignature\x18\x03 \x01(\fR\tsignature\"X\n" +
+	"\tsignature\x18\x03 \x01(\fR\tsignature\x12\x18\n" +
+	"\aexpires\x18\x04 \x01(\x03R\aexpires\"\x99\x02\n" +
 	"\x0fSetTokenRequest\x12/\n" +
 	"\bplatform\x18\x01 \x01(\x0e2\x13.pushproto.PlatformR\bplatform\x12\x14\n" +
-	"\x05token\x18\x02 \x01(\tR\x05token\"\\\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x1e\n" +
+	"\n" +
+	"appVersion\x18\x03 \x01(\tR\n" +
+	"appVersion\x12\x1c\n" +
+	"\tosVersion\x18\x04 \x01(\tR\tosVersion\x12;\n" +
+	"\fbuildChannel\x18\x05 \x01(\x0e2\x17.pushproto.BuildChannelR\fbuildChannel\x12D\n" +
+	"\x0fapnsEnvironment\x18\x06 \x01(\x0e2\x1a.pushproto.ApnsEnvironmentR\x0fapnsEnvironment\"\x14\n" +
+	"\x12ListDevicesRequest\"B\n" +
+	"\x13ListDevicesResponse\x12+\n" +
+	"\adevices\x18\x01 \x03(\v2\x11.pushproto.DeviceR\adevices\"\xf6\x02\n" +
+	"\x06Device\x12\x16\n" +
+	"\x06peerId\x18\x01 \x01(\tR\x06peerId\x12/\n" +
+	"\bplatform\x18\x02 \x01(\x0e2\x13.pushproto.PlatformR\bplatform\x12\x18\n" +
+	"\acreated\x18\x03 \x01(\x03R\acreated\x12\x18\n" +
+	"\aupdated\x18\x04 \x01(\x03R\aupdated\x12\x1e\n" +
+	"\n" +
+	"appVersion\x18\x05 \x01(\tR\n" +
+	"appVersion\x12.\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x16.pushproto.TokenStatusR\x06status\x12\x1c\n" +
+	"\tosVersion\x18\a \x01(\tR\tosVersion\x12;\n" +
+	"\fbuildChannel\x18\b \x01(\x0e2\x17.pushproto.BuildChannelR\fbuildChannel\x12D\n" +
+	"\x0fapnsEnvironment\x18\t \x01(\x0e2\x1a.pushproto.ApnsEnvironmentR\x0fapnsEnvironment\"-\n" +
+	"\x13RevokeDeviceRequest\x12\x16\n" +
+	"\x06peerId\x18\x01 \x01(\tR\x06peerId\"\x16\n" +
+	"\x14DeleteAccountRequest\">\n" +
+	"\x18ExportAccountDataRequest\x12\"\n" +
+	"\frevealTokens\x18\x01 \x01(\bR\frevealTokens\"I\n" +
+	"\x19ExportAccountDataResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\",\n" +
+	"\fBlockRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\".\n" +
+	"\x0eUnblockRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\"\x14\n" +
+	"\x12ListBlockedRequest\"5\n" +
+	"\x13ListBlockedResponse\x12\x1e\n" +
+	"\n" +
+	"accountIds\x18\x01 \x03(\tR\n" +
+	"accountIds\"k\n" +
+	"\x19ReportNotificationRequest\x12\x18\n" +
+	"\agroupId\x18\x01 \x01(\tR\agroupId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x90\x01\n" +
 	"\x12CreateSpaceRequest\x12\x1a\n" +
 	"\bspaceKey\x18\x01 \x01(\fR\bspaceKey\x12*\n" +
-	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\"\\\n" +
+	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\x122\n" +
+	"\tspaceType\x18\x03 \x01(\x0e2\x14.pushproto.SpaceTypeR\tspaceType\"\\\n" +
 	"\x12RemoveSpaceRequest\x12\x1a\n" +
 	"\bspaceKey\x18\x01 \x01(\fR\bspaceKey\x12*\n" +
-	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\"\x16\n" +
-	"\x14SubscriptionsRequest\"B\n" +
+	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\"\xb3\x01\n" +
+	"\x15RotateSpaceKeyRequest\x12\x1a\n" +
+	"\bspaceKey\x18\x01 \x01(\fR\bspaceKey\x12*\n" +
+	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\x12 \n" +
+	"\vnewSpaceKey\x18\x03 \x01(\fR\vnewSpaceKey\x120\n" +
+	"\x13newAccountSignature\x18\x04 \x01(\fR\x13newAccountSignature\"|\n" +
+	"\x14TransferSpaceRequest\x12\x1a\n" +
+	"\bspaceKey\x18\x01 \x01(\fR\bspaceKey\x12*\n" +
+	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\x12\x1c\n" +
+	"\taccountId\x18\x03 \x01(\tR\taccountId\"|\n" +
+	"\x14AddSpaceAdminRequest\x12\x1a\n" +
+	"\bspaceKey\x18\x01 \x01(\fR\bspaceKey\x12*\n" +
+	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\x12\x1c\n" +
+	"\taccountId\x18\x03 \x01(\tR\taccountId\"\x7f\n" +
+	"\x17RemoveSpaceAdminRequest\x12\x1a\n" +
+	"\bspaceKey\x18\x01 \x01(\fR\bspaceKey\x12*\n" +
+	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\x12\x1c\n" +
+	"\taccountId\x18\x03 \x01(\tR\taccountId\"m\n" +
+	"\vSpacePolicy\x12.\n" +
+	"\x04mode\x18\x01 \x01(\x0e2\x1a.pushproto.SpacePolicyModeR\x04mode\x12.\n" +
+	"\x12maxFanOutPerMinute\x18\x02 \x01(\rR\x12maxFanOutPerMinute\"_\n" +
+	"\x15GetSpacePolicyRequest\x12\x1a\n" +
+	"\bspaceKey\x18\x01 \x01(\fR\bspaceKey\x12*\n" +
+	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\"H\n" +
+	"\x16GetSpacePolicyResponse\x12.\n" +
+	"\x06policy\x18\x01 \x01(\v2\x16.pushproto.SpacePolicyR\x06policy\"\x8f\x01\n" +
+	"\x15SetSpacePolicyRequest\x12\x1a\n" +
+	"\bspaceKey\x18\x01 \x01(\fR\bspaceKey\x12*\n" +
+	"\x10accountSignature\x18\x02 \x01(\fR\x10accountSignature\x12.\n" +
+	"\x06policy\x18\x03 \x01(\v2\x16.pushproto.SpacePolicyR\x06policy\"\x16\n" +
+	"\x14SubscriptionsRequest\"\\\n" +
 	"\x15SubscriptionsResponse\x12)\n" +
-	"\x06topics\x18\x01 \x01(\v2\x11.pushproto.TopicsR\x06topics\"=\n" +
+	"\x06topics\x18\x01 \x01(\v2\x11.pushproto.TopicsR\x06topics\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"=\n" +
 	"\x10SubscribeRequest\x12)\n" +
 	"\x06topics\x18\x01 \x01(\v2\x11.pushproto.TopicsR\x06topics\"?\n" +
 	"\x12UnsubscribeRequest\x12)\n" +
-	"\x06topics\x18\x01 \x01(\v2\x11.pushproto.TopicsR\x06topics\"@\n" +
+	"\x06topics\x18\x01 \x01(\v2\x11.pushproto.TopicsR\x06topics\"\x83\x01\n" +
 	"\x13SubscribeAllRequest\x12)\n" +
-	"\x06topics\x18\x01 \x01(\v2\x11.pushproto.TopicsR\x06topics\"\x82\x01\n" +
+	"\x06topics\x18\x01 \x01(\v2\x11.pushproto.TopicsR\x06topics\x12-\n" +
+	"\x0fexpectedVersion\x18\x02 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x12\n" +
+	"\x10_expectedVersion\"4\n" +
+	"\x18SyncSubscriptionsRequest\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\"\x9f\x01\n" +
+	"\x19SyncSubscriptionsResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12\x12\n" +
+	"\x04full\x18\x02 \x01(\bR\x04full\x12'\n" +
+	"\x05added\x18\x03 \x01(\v2\x11.pushproto.TopicsR\x05added\x12+\n" +
+	"\aremoved\x18\x04 \x01(\v2\x11.pushproto.TopicsR\aremoved\"\x82\x01\n" +
 	"\rNotifyRequest\x12)\n" +
 	"\x06topics\x18\x01 \x01(\v2\x11.pushproto.TopicsR\x06topics\x12,\n" +
 	"\amessage\x18\x02 \x01(\v2\x12.pushproto.MessageR\amessage\x12\x18\n" +
-	"\agroupId\x18\x03 \x01(\tR\agroupId\"W\n" +
+	"\agroupId\x18\x03 \x01(\tR\agroupId\"s\n" +
+	"\x11NotifyPeerRequest\x12\x16\n" +
+	"\x06peerId\x18\x01 \x01(\tR\x06peerId\x12,\n" +
+	"\amessage\x18\x02 \x01(\v2\x12.pushproto.MessageR\amessage\x12\x18\n" +
+	"\agroupId\x18\x03 \x01(\tR\agroupId\"\x89\x01\n" +
 	"\aMessage\x12\x14\n" +
 	"\x05keyId\x18\x01 \x01(\tR\x05keyId\x12\x18\n" +
 	"\apayload\x18\x02 \x01(\fR\apayload\x12\x1c\n" +
-	"\tsignature\x18\x03 \x01(\fR\tsignature\"\x04\n" +
-	"\x02Ok*\x81\x01\n" +
+	"\tsignature\x18\x03 \x01(\fR\tsignature\x120\n" +
+	"\x06format\x18\x04 \x01(\x0e2\x18.pushproto.PayloadFormatR\x06format\"_\n" +
+	"\x0fPayloadEnvelope\x12\x18\n" +
+	"\apayload\x18\x01 \x01(\fR\apayload\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05nonce\x18\x03 \x01(\fR\x05nonce\"\x04\n" +
+	"\x02Ok*\xdf\x03\n" +
 	"\bErrCodes\x12\x0e\n" +
 	"\n" +
 	"Unexpected\x10\x00\x12\x14\n" +
 	"\x10InvalidSignature\x10\x01\x12\x19\n" +
 	"\x15InvalidTopicSignature\x10\x02\x12\x0f\n" +
 	"\vSpaceExists\x10\x03\x12\x11\n" +
-	"\rNoValidTopics\x10\x04\x12\x10\n" +
-	"\vErrorOffset\x10\xb0\t* \n" +
+	"\rNoValidTopics\x10\x04\x12\x12\n" +
+	"\x0eDeviceNotFound\x10\x05\x12\x10\n" +
+	"\fInvalidToken\x10\x06\x12\x13\n" +
+	"\x0fVersionConflict\x10\a\x12\x11\n" +
+	"\rTooManyTopics\x10\b\x12\x1a\n" +
+	"\x16TooManyTopicsInRequest\x10\t\x12\x10\n" +
+	"\fTopicTooLong\x10\n" +
+	"\x12\x11\n" +
+	"\rTooManySpaces\x10\v\x12\x0f\n" +
+	"\vRateLimited\x10\f\x12\x11\n" +
+	"\rAccountBanned\x10\r\x12\x13\n" +
+	"\x0fInvalidEnvelope\x10\x0e\x12\x12\n" +
+	"\x0eMessageExpired\x10\x0f\x12\x13\n" +
+	"\x0fMessageReplayed\x10\x10\x12\x11\n" +
+	"\rSpaceNotFound\x10\x11\x12\x11\n" +
+	"\rNotSpaceAdmin\x10\x12\x12\x12\n" +
+	"\x0eNotSpaceAuthor\x10\x12\x12\x12\n" +
+	"\x0eLastSpaceAdmin\x10\x13\x12\x19\n" +
+	"\x15SpacePolicyRestricted\x10\x14\x12\x10\n" +
+	"\vErrorOffset\x10\xb0\t\x1a\x02\x10\x01* \n" +
 	"\bPlatform\x12\a\n" +
 	"\x03IOS\x10\x00\x12\v\n" +
-	"\aAndroid\x10\x012\xd9\x04\n" +
+	"\aAndroid\x10\x01*%\n" +
+	"\vTokenStatus\x12\t\n" +
+	"\x05Valid\x10\x00\x12\v\n" +
+	"\aInvalid\x10\x01*.\n" +
+	"\fBuildChannel\x12\v\n" +
+	"\aRelease\x10\x00\x12\b\n" +
+	"\x04Beta\x10\x01\x12\a\n" +
+	"\x03Dev\x10\x02*.\n" +
+	"\x0fApnsEnvironment\x12\x0e\n" +
+	"\n" +
+	"Production\x10\x00\x12\v\n" +
+	"\aSandbox\x10\x01*&\n" +
+	"\tSpaceType\x12\v\n" +
+	"\aRegular\x10\x00\x12\f\n" +
+	"\bOneToOne\x10\x01*N\n" +
+	"\x0fSpacePolicyMode\x12\x14\n" +
+	"\x10AllNotifications\x10\x00\x12\x10\n" +
+	"\fMentionsOnly\x10\x01\x12\x13\n" +
+	"\x0fNoNotifications\x10\x02*)\n" +
+	"\rPayloadFormat\x12\n" +
+	"\n" +
+	"\x06Legacy\x10\x00\x12\f\n" +
+	"\bEnvelope\x10\x012\xc7\r\n" +
 	"\x04Push\x125\n" +
 	"\bSetToken\x12\x1a.pushproto.SetTokenRequest\x1a\r.pushproto.Ok\x12+\n" +
 	"\vRevokeToken\x12\r.pushproto.Ok\x1a\r.pushproto.Ok\x12;\n" +
 	"\vCreateSpace\x12\x1d.pushproto.CreateSpaceRequest\x1a\r.pushproto.Ok\x12;\n" +
-	"\vRemoveSpace\x12\x1d.pushproto.RemoveSpaceRequest\x1a\r.pushproto.Ok\x12R\n" +
+	"\vRemoveSpace\x12\x1d.pushproto.RemoveSpaceRequest\x1a\r.pushproto.Ok\x12A\n" +
+	"\x0eRotateSpaceKey\x12 .pushproto.RotateSpaceKeyRequest\x1a\r.pushproto.Ok\x12?\n" +
+	"\rTransferSpace\x12\x1f.pushproto.TransferSpaceRequest\x1a\r.pushproto.Ok\x12?\n" +
+	"\rAddSpaceAdmin\x12\x1f.pushproto.AddSpaceAdminRequest\x1a\r.pushproto.Ok\x12E\n" +
+	"\x10RemoveSpaceAdmin\x12\".pushproto.RemoveSpaceAdminRequest\x1a\r.pushproto.Ok\x12U\n" +
+	"\x0eGetSpacePolicy\x12 .pushproto.GetSpacePolicyRequest\x1a!.pushproto.GetSpacePolicyResponse\x12A\n" +
+	"\x0eSetSpacePolicy\x12 .pushproto.SetSpacePolicyRequest\x1a\r.pushproto.Ok\x12R\n" +
 	"\rSubscriptions\x12\x1f.pushproto.SubscriptionsRequest\x1a .pushproto.SubscriptionsResponse\x127\n" +
 	"\tSubscribe\x12\x1b.pushproto.SubscribeRequest\x1a\r.pushproto.Ok\x12;\n" +
 	"\vUnsubscribe\x12\x1d.pushproto.UnsubscribeRequest\x1a\r.pushproto.Ok\x12=\n" +
-	"\fSubscribeAll\x12\x1e.pushproto.SubscribeAllRequest\x1a\r.pushproto.Ok\x121\n" +
+	"\fSubscribeAll\x12\x1e.pushproto.SubscribeAllRequest\x1a\r.pushproto.Ok\x12^\n" +
+	"\x11SyncSubscriptions\x12#.pushproto.SyncSubscriptionsRequest\x1a$.pushproto.SyncSubscriptionsResponse\x121\n" +
 	"\x06Notify\x12\x18.pushproto.NotifyRequest\x1a\r.pushproto.Ok\x127\n" +
-	"\fNotifySilent\x12\x18.pushproto.NotifyRequest\x1a\r.pushproto.OkB\x14Z\x12pushclient/pushapib\x06proto3"
+	"\fNotifySilent\x12\x18.pushproto.NotifyRequest\x1a\r.pushproto.Ok\x129\n" +
+	"\n" +
+	"NotifyPeer\x12\x1c.pushproto.NotifyPeerRequest\x1a\r.pushproto.Ok\x12L\n" +
+	"\vListDevices\x12\x1d.pushproto.ListDevicesRequest\x1a\x1e.pushproto.ListDevicesResponse\x12=\n" +
+	"\fRevokeDevice\x12\x1e.pushproto.RevokeDeviceRequest\x1a\r.pushproto.Ok\x12?\n" +
+	"\rDeleteAccount\x12\x1f.pushproto.DeleteAccountRequest\x1a\r.pushproto.Ok\x12^\n" +
+	"\x11ExportAccountData\x12#.pushproto.ExportAccountDataRequest\x1a$.pushproto.ExportAccountDataResponse\x12/\n" +
+	"\x05Block\x12\x17.pushproto.BlockRequest\x1a\r.pushproto.Ok\x123\n" +
+	"\aUnblock\x12\x19.pushproto.UnblockRequest\x1a\r.pushproto.Ok\x12L\n" +
+	"\vListBlocked\x12\x1d.pushproto.ListBlockedRequest\x1a\x1e.pushproto.ListBlockedResponse\x12I\n" +
+	"\x12ReportNotification\x12$.pushproto.ReportNotificationRequest\x1a\r.pushproto.OkB\x14Z\x12pushclient/pushapib\x06proto3"
 
 var (
 	file_pushclient_pushapi_protos_push_proto_rawDescOnce sync.Once
@@ -829,59 +2694,136 @@ func file_pushclient_pushapi_protos_push_proto_rawDescGZIP() []byte {
 	return file_pushclient_pushapi_protos_push_proto_rawDescData
 }
 
-var file_pushclient_pushapi_protos_push_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_pushclient_pushapi_protos_push_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_pushclient_pushapi_protos_push_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_pushclient_pushapi_protos_push_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
 var file_pushclient_pushapi_protos_push_proto_goTypes = []any{
-	(ErrCodes)(0),                 // 0: pushproto.ErrCodes
-	(Platform)(0),                 // 1: pushproto.Platform
-	(*Topics)(nil),                // 2: pushproto.Topics
-	(*Topic)(nil),                 // 3: pushproto.Topic
-	(*SetTokenRequest)(nil),       // 4: pushproto.SetTokenRequest
-	(*CreateSpaceRequest)(nil),    // 5: pushproto.CreateSpaceRequest
-	(*RemoveSpaceRequest)(nil),    // 6: pushproto.RemoveSpaceRequest
-	(*SubscriptionsRequest)(nil),  // 7: pushproto.SubscriptionsRequest
-	(*SubscriptionsResponse)(nil), // 8: pushproto.SubscriptionsResponse
-	(*SubscribeRequest)(nil),      // 9: pushproto.SubscribeRequest
-	(*UnsubscribeRequest)(nil),    // 10: pushproto.UnsubscribeRequest
-	(*SubscribeAllRequest)(nil),   // 11: pushproto.SubscribeAllRequest
-	(*NotifyRequest)(nil),         // 12: pushproto.NotifyRequest
-	(*Message)(nil),               // 13: pushproto.Message
-	(*Ok)(nil),                    // 14: pushproto.Ok
+	(ErrCodes)(0),                     // 0: pushproto.ErrCodes
+	(Platform)(0),                     // 1: pushproto.Platform
+	(TokenStatus)(0),                  // 2: pushproto.TokenStatus
+	(BuildChannel)(0),                 // 3: pushproto.BuildChannel
+	(ApnsEnvironment)(0),              // 4: pushproto.ApnsEnvironment
+	(SpaceType)(0),                    // 5: pushproto.SpaceType
+	(SpacePolicyMode)(0),              // 6: pushproto.SpacePolicyMode
+	(PayloadFormat)(0),                // 7: pushproto.PayloadFormat
+	(*Topics)(nil),                    // 8: pushproto.Topics
+	(*Topic)(nil),                     // 9: pushproto.Topic
+	(*SetTokenRequest)(nil),           // 10: pushproto.SetTokenRequest
+	(*ListDevicesRequest)(nil),        // 11: pushproto.ListDevicesRequest
+	(*ListDevicesResponse)(nil),       // 12: pushproto.ListDevicesResponse
+	(*Device)(nil),                    // 13: pushproto.Device
+	(*RevokeDeviceRequest)(nil),       // 14: pushproto.RevokeDeviceRequest
+	(*DeleteAccountRequest)(nil),      // 15: pushproto.DeleteAccountRequest
+	(*ExportAccountDataRequest)(nil),  // 16: pushproto.ExportAccountDataRequest
+	(*ExportAccountDataResponse)(nil), // 17: pushproto.ExportAccountDataResponse
+	(*BlockRequest)(nil),              // 18: pushproto.BlockRequest
+	(*UnblockRequest)(nil),            // 19: pushproto.UnblockRequest
+	(*ListBlockedRequest)(nil),        // 20: pushproto.ListBlockedRequest
+	(*ListBlockedResponse)(nil),       // 21: pushproto.ListBlockedResponse
+	(*ReportNotificationRequest)(nil), // 22: pushproto.ReportNotificationRequest
+	(*CreateSpaceRequest)(nil),        // 23: pushproto.CreateSpaceRequest
+	(*RemoveSpaceRequest)(nil),        // 24: pushproto.RemoveSpaceRequest
+	(*RotateSpaceKeyRequest)(nil),     // 25: pushproto.RotateSpaceKeyRequest
+	(*TransferSpaceRequest)(nil),      // 26: pushproto.TransferSpaceRequest
+	(*AddSpaceAdminRequest)(nil),      // 27: pushproto.AddSpaceAdminRequest
+	(*RemoveSpaceAdminRequest)(nil),   // 28: pushproto.RemoveSpaceAdminRequest
+	(*SpacePolicy)(nil),               // 29: pushproto.SpacePolicy
+	(*GetSpacePolicyRequest)(nil),     // 30: pushproto.GetSpacePolicyRequest
+	(*GetSpacePolicyResponse)(nil),    // 31: pushproto.GetSpacePolicyResponse
+	(*SetSpacePolicyRequest)(nil),     // 32: pushproto.SetSpacePolicyRequest
+	(*SubscriptionsRequest)(nil),      // 33: pushproto.SubscriptionsRequest
+	(*SubscriptionsResponse)(nil),     // 34: pushproto.SubscriptionsResponse
+	(*SubscribeRequest)(nil),          // 35: pushproto.SubscribeRequest
+	(*UnsubscribeRequest)(nil),        // 36: pushproto.UnsubscribeRequest
+	(*SubscribeAllRequest)(nil),       // 37: pushproto.SubscribeAllRequest
+	(*SyncSubscriptionsRequest)(nil),  // 38: pushproto.SyncSubscriptionsRequest
+	(*SyncSubscriptionsResponse)(nil), // 39: pushproto.SyncSubscriptionsResponse
+	(*NotifyRequest)(nil),             // 40: pushproto.NotifyRequest
+	(*NotifyPeerRequest)(nil),         // 41: pushproto.NotifyPeerRequest
+	(*Message)(nil),                   // 42: pushproto.Message
+	(*PayloadEnvelope)(nil),           // 43: pushproto.PayloadEnvelope
+	(*Ok)(nil),                        // 44: pushproto.Ok
 }
 var file_pushclient_pushapi_protos_push_proto_depIdxs = []int32{
-	3,  // 0: pushproto.Topics.topics:type_name -> pushproto.Topic
+	9,  // 0: pushproto.Topics.topics:type_name -> pushproto.Topic
 	1,  // 1: pushproto.SetTokenRequest.platform:type_name -> pushproto.Platform
-	2,  // 2: pushproto.SubscriptionsResponse.topics:type_name -> pushproto.Topics
-	2,  // 3: pushproto.SubscribeRequest.topics:type_name -> pushproto.Topics
-	2,  // 4: pushproto.UnsubscribeRequest.topics:type_name -> pushproto.Topics
-	2,  // 5: pushproto.SubscribeAllRequest.topics:type_name -> pushproto.Topics
-	2,  // 6: pushproto.NotifyRequest.topics:type_name -> pushproto.Topics
-	13, // 7: pushproto.NotifyRequest.message:type_name -> pushproto.Message
-	4,  // 8: pushproto.Push.SetToken:input_type -> pushproto.SetTokenRequest
-	14, // 9: pushproto.Push.RevokeToken:input_type -> pushproto.Ok
-	5,  // 10: pushproto.Push.CreateSpace:input_type -> pushproto.CreateSpaceRequest
-	6,  // 11: pushproto.Push.RemoveSpace:input_type -> pushproto.RemoveSpaceRequest
-	7,  // 12: pushproto.Push.Subscriptions:input_type -> pushproto.SubscriptionsRequest
-	9,  // 13: pushproto.Push.Subscribe:input_type -> pushproto.SubscribeRequest
-	10, // 14: pushproto.Push.Unsubscribe:input_type -> pushproto.UnsubscribeRequest
-	11, // 15: pushproto.Push.SubscribeAll:input_type -> pushproto.SubscribeAllRequest
-	12, // 16: pushproto.Push.Notify:input_type -> pushproto.NotifyRequest
-	12, // 17: pushproto.Push.NotifySilent:input_type -> pushproto.NotifyRequest
-	14, // 18: pushproto.Push.SetToken:output_type -> pushproto.Ok
-	14, // 19: pushproto.Push.RevokeToken:output_type -> pushproto.Ok
-	14, // 20: pushproto.Push.CreateSpace:output_type -> pushproto.Ok
-	14, // 21: pushproto.Push.RemoveSpace:output_type -> pushproto.Ok
-	8,  // 22: pushproto.Push.Subscriptions:output_type -> pushproto.SubscriptionsResponse
-	14, // 23: pushproto.Push.Subscribe:output_type -> pushproto.Ok
-	14, // 24: pushproto.Push.Unsubscribe:output_type -> pushproto.Ok
-	14, // 25: pushproto.Push.SubscribeAll:output_type -> pushproto.Ok
-	14, // 26: pushproto.Push.Notify:output_type -> pushproto.Ok
-	14, // 27: pushproto.Push.NotifySilent:output_type -> pushproto.Ok
-	18, // [18:28] is the sub-list for method output_type
-	8,  // [8:18] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	3,  // 2: pushproto.SetTokenRequest.buildChannel:type_name -> pushproto.BuildChannel
+	4,  // 3: pushproto.SetTokenRequest.apnsEnvironment:type_name -> pushproto.ApnsEnvironment
+	13, // 4: pushproto.ListDevicesResponse.devices:type_name -> pushproto.Device
+	1,  // 5: pushproto.Device.platform:type_name -> pushproto.Platform
+	2,  // 6: pushproto.Device.status:type_name -> pushproto.TokenStatus
+	3,  // 7: pushproto.Device.buildChannel:type_name -> pushproto.BuildChannel
+	4,  // 8: pushproto.Device.apnsEnvironment:type_name -> pushproto.ApnsEnvironment
+	5,  // 9: pushproto.CreateSpaceRequest.spaceType:type_name -> pushproto.SpaceType
+	6,  // 10: pushproto.SpacePolicy.mode:type_name -> pushproto.SpacePolicyMode
+	29, // 11: pushproto.GetSpacePolicyResponse.policy:type_name -> pushproto.SpacePolicy
+	29, // 12: pushproto.SetSpacePolicyRequest.policy:type_name -> pushproto.SpacePolicy
+	8,  // 13: pushproto.SubscriptionsResponse.topics:type_name -> pushproto.Topics
+	8,  // 14: pushproto.SubscribeRequest.topics:type_name -> pushproto.Topics
+	8,  // 15: pushproto.UnsubscribeRequest.topics:type_name -> pushproto.Topics
+	8,  // 16: pushproto.SubscribeAllRequest.topics:type_name -> pushproto.Topics
+	8,  // 17: pushproto.SyncSubscriptionsResponse.added:type_name -> pushproto.Topics
+	8,  // 18: pushproto.SyncSubscriptionsResponse.removed:type_name -> pushproto.Topics
+	8,  // 19: pushproto.NotifyRequest.topics:type_name -> pushproto.Topics
+	42, // 20: pushproto.NotifyRequest.message:type_name -> pushproto.Message
+	42, // 21: pushproto.NotifyPeerRequest.message:type_name -> pushproto.Message
+	7,  // 22: pushproto.Message.format:type_name -> pushproto.PayloadFormat
+	10, // 23: pushproto.Push.SetToken:input_type -> pushproto.SetTokenRequest
+	44, // 24: pushproto.Push.RevokeToken:input_type -> pushproto.Ok
+	23, // 25: pushproto.Push.CreateSpace:input_type -> pushproto.CreateSpaceRequest
+	24, // 26: pushproto.Push.RemoveSpace:input_type -> pushproto.RemoveSpaceRequest
+	25, // 27: pushproto.Push.RotateSpaceKey:input_type -> pushproto.RotateSpaceKeyRequest
+	26, // 28: pushproto.Push.TransferSpace:input_type -> pushproto.TransferSpaceRequest
+	27, // 29: pushproto.Push.AddSpaceAdmin:input_type -> pushproto.AddSpaceAdminRequest
+	28, // 30: pushproto.Push.RemoveSpaceAdmin:input_type -> pushproto.RemoveSpaceAdminRequest
+	30, // 31: pushproto.Push.GetSpacePolicy:input_type -> pushproto.GetSpacePolicyRequest
+	32, // 32: pushproto.Push.SetSpacePolicy:input_type -> pushproto.SetSpacePolicyRequest
+	33, // 33: pushproto.Push.Subscriptions:input_type -> pushproto.SubscriptionsRequest
+	35, // 34: pushproto.Push.Subscribe:input_type -> pushproto.SubscribeRequest
+	36, // 35: pushproto.Push.Unsubscribe:input_type -> pushproto.UnsubscribeRequest
+	37, // 36: pushproto.Push.SubscribeAll:input_type -> pushproto.SubscribeAllRequest
+	38, // 37: pushproto.Push.SyncSubscriptions:input_type -> pushproto.SyncSubscriptionsRequest
+	40, // 38: pushproto.Push.Notify:input_type -> pushproto.NotifyRequest
+	40, // 39: pushproto.Push.NotifySilent:input_type -> pushproto.NotifyRequest
+	41, // 40: pushproto.Push.NotifyPeer:input_type -> pushproto.NotifyPeerRequest
+	11, // 41: pushproto.Push.ListDevices:input_type -> pushproto.ListDevicesRequest
+	14, // 42: pushproto.Push.RevokeDevice:input_type -> pushproto.RevokeDeviceRequest
+	15, // 43: pushproto.Push.DeleteAccount:input_type -> pushproto.DeleteAccountRequest
+	16, // 44: pushproto.Push.ExportAccountData:input_type -> pushproto.ExportAccountDataRequest
+	18, // 45: pushproto.Push.Block:input_type -> pushproto.BlockRequest
+	19, // 46: pushproto.Push.Unblock:input_type -> pushproto.UnblockRequest
+	20, // 47: pushproto.Push.ListBlocked:input_type -> pushproto.ListBlockedRequest
+	22, // 48: pushproto.Push.ReportNotification:input_type -> pushproto.ReportNotificationRequest
+	44, // 49: pushproto.Push.SetToken:output_type -> pushproto.Ok
+	44, // 50: pushproto.Push.RevokeToken:output_type -> pushproto.Ok
+	44, // 51: pushproto.Push.CreateSpace:output_type -> pushproto.Ok
+	44, // 52: pushproto.Push.RemoveSpace:output_type -> pushproto.Ok
+	44, // 53: pushproto.Push.RotateSpaceKey:output_type -> pushproto.Ok
+	44, // 54: pushproto.Push.TransferSpace:output_type -> pushproto.Ok
+	44, // 55: pushproto.Push.AddSpaceAdmin:output_type -> pushproto.Ok
+	44, // 56: pushproto.Push.RemoveSpaceAdmin:output_type -> pushproto.Ok
+	31, // 57: pushproto.Push.GetSpacePolicy:output_type -> pushproto.GetSpacePolicyResponse
+	44, // 58: pushproto.Push.SetSpacePolicy:output_type -> pushproto.Ok
+	34, // 59: pushproto.Push.Subscriptions:output_type -> pushproto.SubscriptionsResponse
+	44, // 60: pushproto.Push.Subscribe:output_type -> pushproto.Ok
+	44, // 61: pushproto.Push.Unsubscribe:output_type -> pushproto.Ok
+	44, // 62: pushproto.Push.SubscribeAll:output_type -> pushproto.Ok
+	39, // 63: pushproto.Push.SyncSubscriptions:output_type -> pushproto.SyncSubscriptionsResponse
+	44, // 64: pushproto.Push.Notify:output_type -> pushproto.Ok
+	44, // 65: pushproto.Push.NotifySilent:output_type -> pushproto.Ok
+	44, // 66: pushproto.Push.NotifyPeer:output_type -> pushproto.Ok
+	12, // 67: pushproto.Push.ListDevices:output_type -> pushproto.ListDevicesResponse
+	44, // 68: pushproto.Push.RevokeDevice:output_type -> pushproto.Ok
+	44, // 69: pushproto.Push.DeleteAccount:output_type -> pushproto.Ok
+	17, // 70: pushproto.Push.ExportAccountData:output_type -> pushproto.ExportAccountDataResponse
+	44, // 71: pushproto.Push.Block:output_type -> pushproto.Ok
+	44, // 72: pushproto.Push.Unblock:output_type -> pushproto.Ok
+	21, // 73: pushproto.Push.ListBlocked:output_type -> pushproto.ListBlockedResponse
+	44, // 74: pushproto.Push.ReportNotification:output_type -> pushproto.Ok
+	49, // [49:75] is the sub-list for method output_type
+	23, // [23:49] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_pushclient_pushapi_protos_push_proto_init() }
@@ -889,13 +2831,14 @@ func file_pushclient_pushapi_protos_push_proto_init() {
 	if File_pushclient_pushapi_protos_push_proto != nil {
 		return
 	}
+	file_pushclient_pushapi_protos_push_proto_msgTypes[29].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pushclient_pushapi_protos_push_proto_rawDesc), len(file_pushclient_pushapi_protos_push_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   13,
+			NumEnums:      8,
+			NumMessages:   37,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -37,12 +37,28 @@ type DRPCPushClient interface {
 	RevokeToken(ctx context.Context, in *Ok) (*Ok, error)
 	CreateSpace(ctx context.Context, in *CreateSpaceRequest) (*Ok, error)
 	RemoveSpace(ctx context.Context, in *RemoveSpaceRequest) (*Ok, error)
+	RotateSpaceKey(ctx context.Context, in *RotateSpaceKeyRequest) (*Ok, error)
+	TransferSpace(ctx context.Context, in *TransferSpaceRequest) (*Ok, error)
+	AddSpaceAdmin(ctx context.Context, in *AddSpaceAdminRequest) (*Ok, error)
+	RemoveSpaceAdmin(ctx context.Context, in *RemoveSpaceAdminRequest) (*Ok, error)
+	GetSpacePolicy(ctx context.Context, in *GetSpacePolicyRequest) (*GetSpacePolicyResponse, error)
+	SetSpacePolicy(ctx context.Context, in *SetSpacePolicyRequest) (*Ok, error)
 	Subscriptions(ctx context.Context, in *SubscriptionsRequest) (*SubscriptionsResponse, error)
 	Subscribe(ctx context.Context, in *SubscribeRequest) (*Ok, error)
 	Unsubscribe(ctx context.Context, in *UnsubscribeRequest) (*Ok, error)
 	SubscribeAll(ctx context.Context, in *SubscribeAllRequest) (*Ok, error)
+	SyncSubscriptions(ctx context.Context, in *SyncSubscriptionsRequest) (*SyncSubscriptionsResponse, error)
 	Notify(ctx context.Context, in *NotifyRequest) (*Ok, error)
 	NotifySilent(ctx context.Context, in *NotifyRequest) (*Ok, error)
+	NotifyPeer(ctx context.Context, in *NotifyPeerRequest) (*Ok, error)
+	ListDevices(ctx context.Context, in *ListDevicesRequest) (*ListDevicesResponse, error)
+	RevokeDevice(ctx context.Context, in *RevokeDeviceRequest) (*Ok, error)
+	DeleteAccount(ctx context.Context, in *DeleteAccountRequest) (*Ok, error)
+	ExportAccountData(ctx context.Context, in *ExportAccountDataRequest) (*ExportAccountDataResponse, error)
+	Block(ctx context.Context, in *BlockRequest) (*Ok, error)
+	Unblock(ctx context.Context, in *UnblockRequest) (*Ok, error)
+	ListBlocked(ctx context.Context, in *ListBlockedRequest) (*ListBlockedResponse, error)
+	ReportNotification(ctx context.Context, in *ReportNotificationRequest) (*Ok, error)
 }
 
 type drpcPushClient struct {
@@ -91,6 +107,60 @@ func (c *drpcPushClient) RemoveSpace(ctx context.Context, in *RemoveSpaceRequest
 	return out, nil
 }
 
+func (c *drpcPushClient) RotateSpaceKey(ctx context.Context, in *RotateSpaceKeyRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/RotateSpaceKey", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) TransferSpace(ctx context.Context, in *TransferSpaceRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/TransferSpace", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) AddSpaceAdmin(ctx context.Context, in *AddSpaceAdminRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/AddSpaceAdmin", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) RemoveSpaceAdmin(ctx context.Context, in *RemoveSpaceAdminRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/RemoveSpaceAdmin", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) GetSpacePolicy(ctx context.Context, in *GetSpacePolicyRequest) (*GetSpacePolicyResponse, error) {
+	out := new(GetSpacePolicyResponse)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/GetSpacePolicy", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) SetSpacePolicy(ctx context.Context, in *SetSpacePolicyRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/SetSpacePolicy", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *drpcPushClient) Subscriptions(ctx context.Context, in *SubscriptionsRequest) (*SubscriptionsResponse, error) {
 	out := new(SubscriptionsResponse)
 	err := c.cc.Invoke(ctx, "/pushproto.Push/Subscriptions", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
@@ -127,6 +197,15 @@ func (c *drpcPushClient) SubscribeAll(ctx context.Context, in *SubscribeAllReque
 	return out, nil
 }
 
+func (c *drpcPushClient) SyncSubscriptions(ctx context.Context, in *SyncSubscriptionsRequest) (*SyncSubscriptionsResponse, error) {
+	out := new(SyncSubscriptionsResponse)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/SyncSubscriptions", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *drpcPushClient) Notify(ctx context.Context, in *NotifyRequest) (*Ok, error) {
 	out := new(Ok)
 	err := c.cc.Invoke(ctx, "/pushproto.Push/Notify", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
@@ -145,17 +224,114 @@ func (c *drpcPushClient) NotifySilent(ctx context.Context, in *NotifyRequest) (*
 	return out, nil
 }
 
+func (c *drpcPushClient) NotifyPeer(ctx context.Context, in *NotifyPeerRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/NotifyPeer", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) ListDevices(ctx context.Context, in *ListDevicesRequest) (*ListDevicesResponse, error) {
+	out := new(ListDevicesResponse)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/ListDevices", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) RevokeDevice(ctx context.Context, in *RevokeDeviceRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/RevokeDevice", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) DeleteAccount(ctx context.Context, in *DeleteAccountRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/DeleteAccount", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) ExportAccountData(ctx context.Context, in *ExportAccountDataRequest) (*ExportAccountDataResponse, error) {
+	out := new(ExportAccountDataResponse)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/ExportAccountData", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) Block(ctx context.Context, in *BlockRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/Block", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) Unblock(ctx context.Context, in *UnblockRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/Unblock", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) ListBlocked(ctx context.Context, in *ListBlockedRequest) (*ListBlockedResponse, error) {
+	out := new(ListBlockedResponse)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/ListBlocked", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcPushClient) ReportNotification(ctx context.Context, in *ReportNotificationRequest) (*Ok, error) {
+	out := new(Ok)
+	err := c.cc.Invoke(ctx, "/pushproto.Push/ReportNotification", drpcEncoding_File_pushclient_pushapi_protos_push_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type DRPCPushServer interface {
 	SetToken(context.Context, *SetTokenRequest) (*Ok, error)
 	RevokeToken(context.Context, *Ok) (*Ok, error)
 	CreateSpace(context.Context, *CreateSpaceRequest) (*Ok, error)
 	RemoveSpace(context.Context, *RemoveSpaceRequest) (*Ok, error)
+	RotateSpaceKey(context.Context, *RotateSpaceKeyRequest) (*Ok, error)
+	TransferSpace(context.Context, *TransferSpaceRequest) (*Ok, error)
+	AddSpaceAdmin(context.Context, *AddSpaceAdminRequest) (*Ok, error)
+	RemoveSpaceAdmin(context.Context, *RemoveSpaceAdminRequest) (*Ok, error)
+	GetSpacePolicy(context.Context, *GetSpacePolicyRequest) (*GetSpacePolicyResponse, error)
+	SetSpacePolicy(context.Context, *SetSpacePolicyRequest) (*Ok, error)
 	Subscriptions(context.Context, *SubscriptionsRequest) (*SubscriptionsResponse, error)
 	Subscribe(context.Context, *SubscribeRequest) (*Ok, error)
 	Unsubscribe(context.Context, *UnsubscribeRequest) (*Ok, error)
 	SubscribeAll(context.Context, *SubscribeAllRequest) (*Ok, error)
+	SyncSubscriptions(context.Context, *SyncSubscriptionsRequest) (*SyncSubscriptionsResponse, error)
 	Notify(context.Context, *NotifyRequest) (*Ok, error)
 	NotifySilent(context.Context, *NotifyRequest) (*Ok, error)
+	NotifyPeer(context.Context, *NotifyPeerRequest) (*Ok, error)
+	ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error)
+	RevokeDevice(context.Context, *RevokeDeviceRequest) (*Ok, error)
+	DeleteAccount(context.Context, *DeleteAccountRequest) (*Ok, error)
+	ExportAccountData(context.Context, *ExportAccountDataRequest) (*ExportAccountDataResponse, error)
+	Block(context.Context, *BlockRequest) (*Ok, error)
+	Unblock(context.Context, *UnblockRequest) (*Ok, error)
+	ListBlocked(context.Context, *ListBlockedRequest) (*ListBlockedResponse, error)
+	ReportNotification(context.Context, *ReportNotificationRequest) (*Ok, error)
 }
 
 type DRPCPushUnimplementedServer struct{}
@@ -176,6 +352,30 @@ func (s *DRPCPushUnimplementedServer) RemoveSpace(context.Context, *RemoveSpaceR
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCPushUnimplementedServer) RotateSpaceKey(context.Context, *RotateSpaceKeyRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) TransferSpace(context.Context, *TransferSpaceRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) AddSpaceAdmin(context.Context, *AddSpaceAdminRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) RemoveSpaceAdmin(context.Context, *RemoveSpaceAdminRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) GetSpacePolicy(context.Context, *GetSpacePolicyRequest) (*GetSpacePolicyResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) SetSpacePolicy(context.Context, *SetSpacePolicyRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 func (s *DRPCPushUnimplementedServer) Subscriptions(context.Context, *SubscriptionsRequest) (*SubscriptionsResponse, error) {
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
@@ -192,6 +392,10 @@ func (s *DRPCPushUnimplementedServer) SubscribeAll(context.Context, *SubscribeAl
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCPushUnimplementedServer) SyncSubscriptions(context.Context, *SyncSubscriptionsRequest) (*SyncSubscriptionsResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 func (s *DRPCPushUnimplementedServer) Notify(context.Context, *NotifyRequest) (*Ok, error) {
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
@@ -200,9 +404,45 @@ func (s *DRPCPushUnimplementedServer) NotifySilent(context.Context, *NotifyReque
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCPushUnimplementedServer) NotifyPeer(context.Context, *NotifyPeerRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) RevokeDevice(context.Context, *RevokeDeviceRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) DeleteAccount(context.Context, *DeleteAccountRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) ExportAccountData(context.Context, *ExportAccountDataRequest) (*ExportAccountDataResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) Block(context.Context, *BlockRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) Unblock(context.Context, *UnblockRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) ListBlocked(context.Context, *ListBlockedRequest) (*ListBlockedResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCPushUnimplementedServer) ReportNotification(context.Context, *ReportNotificationRequest) (*Ok, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 type DRPCPushDescription struct{}
 
-func (DRPCPushDescription) NumMethods() int { return 10 }
+func (DRPCPushDescription) NumMethods() int { return 26 }
 
 func (DRPCPushDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -243,6 +483,60 @@ func (DRPCPushDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 					)
 			}, DRPCPushServer.RemoveSpace, true
 	case 4:
+		return "/pushproto.Push/RotateSpaceKey", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					RotateSpaceKey(
+						ctx,
+						in1.(*RotateSpaceKeyRequest),
+					)
+			}, DRPCPushServer.RotateSpaceKey, true
+	case 5:
+		return "/pushproto.Push/TransferSpace", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					TransferSpace(
+						ctx,
+						in1.(*TransferSpaceRequest),
+					)
+			}, DRPCPushServer.TransferSpace, true
+	case 6:
+		return "/pushproto.Push/AddSpaceAdmin", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					AddSpaceAdmin(
+						ctx,
+						in1.(*AddSpaceAdminRequest),
+					)
+			}, DRPCPushServer.AddSpaceAdmin, true
+	case 7:
+		return "/pushproto.Push/RemoveSpaceAdmin", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					RemoveSpaceAdmin(
+						ctx,
+						in1.(*RemoveSpaceAdminRequest),
+					)
+			}, DRPCPushServer.RemoveSpaceAdmin, true
+	case 8:
+		return "/pushproto.Push/GetSpacePolicy", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					GetSpacePolicy(
+						ctx,
+						in1.(*GetSpacePolicyRequest),
+					)
+			}, DRPCPushServer.GetSpacePolicy, true
+	case 9:
+		return "/pushproto.Push/SetSpacePolicy", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					SetSpacePolicy(
+						ctx,
+						in1.(*SetSpacePolicyRequest),
+					)
+			}, DRPCPushServer.SetSpacePolicy, true
+	case 10:
 		return "/pushproto.Push/Subscriptions", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPushServer).
@@ -251,7 +545,7 @@ func (DRPCPushDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 						in1.(*SubscriptionsRequest),
 					)
 			}, DRPCPushServer.Subscriptions, true
-	case 5:
+	case 11:
 		return "/pushproto.Push/Subscribe", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPushServer).
@@ -260,7 +554,7 @@ func (DRPCPushDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 						in1.(*SubscribeRequest),
 					)
 			}, DRPCPushServer.Subscribe, true
-	case 6:
+	case 12:
 		return "/pushproto.Push/Unsubscribe", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPushServer).
@@ -269,7 +563,7 @@ func (DRPCPushDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 						in1.(*UnsubscribeRequest),
 					)
 			}, DRPCPushServer.Unsubscribe, true
-	case 7:
+	case 13:
 		return "/pushproto.Push/SubscribeAll", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPushServer).
@@ -278,7 +572,16 @@ func (DRPCPushDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 						in1.(*SubscribeAllRequest),
 					)
 			}, DRPCPushServer.SubscribeAll, true
-	case 8:
+	case 14:
+		return "/pushproto.Push/SyncSubscriptions", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					SyncSubscriptions(
+						ctx,
+						in1.(*SyncSubscriptionsRequest),
+					)
+			}, DRPCPushServer.SyncSubscriptions, true
+	case 15:
 		return "/pushproto.Push/Notify", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPushServer).
@@ -287,7 +590,7 @@ func (DRPCPushDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 						in1.(*NotifyRequest),
 					)
 			}, DRPCPushServer.Notify, true
-	case 9:
+	case 16:
 		return "/pushproto.Push/NotifySilent", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCPushServer).
@@ -296,6 +599,87 @@ func (DRPCPushDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver,
 						in1.(*NotifyRequest),
 					)
 			}, DRPCPushServer.NotifySilent, true
+	case 17:
+		return "/pushproto.Push/NotifyPeer", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					NotifyPeer(
+						ctx,
+						in1.(*NotifyPeerRequest),
+					)
+			}, DRPCPushServer.NotifyPeer, true
+	case 18:
+		return "/pushproto.Push/ListDevices", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					ListDevices(
+						ctx,
+						in1.(*ListDevicesRequest),
+					)
+			}, DRPCPushServer.ListDevices, true
+	case 19:
+		return "/pushproto.Push/RevokeDevice", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					RevokeDevice(
+						ctx,
+						in1.(*RevokeDeviceRequest),
+					)
+			}, DRPCPushServer.RevokeDevice, true
+	case 20:
+		return "/pushproto.Push/DeleteAccount", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					DeleteAccount(
+						ctx,
+						in1.(*DeleteAccountRequest),
+					)
+			}, DRPCPushServer.DeleteAccount, true
+	case 21:
+		return "/pushproto.Push/ExportAccountData", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					ExportAccountData(
+						ctx,
+						in1.(*ExportAccountDataRequest),
+					)
+			}, DRPCPushServer.ExportAccountData, true
+	case 22:
+		return "/pushproto.Push/Block", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					Block(
+						ctx,
+						in1.(*BlockRequest),
+					)
+			}, DRPCPushServer.Block, true
+	case 23:
+		return "/pushproto.Push/Unblock", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					Unblock(
+						ctx,
+						in1.(*UnblockRequest),
+					)
+			}, DRPCPushServer.Unblock, true
+	case 24:
+		return "/pushproto.Push/ListBlocked", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					ListBlocked(
+						ctx,
+						in1.(*ListBlockedRequest),
+					)
+			}, DRPCPushServer.ListBlocked, true
+	case 25:
+		return "/pushproto.Push/ReportNotification", drpcEncoding_File_pushclient_pushapi_protos_push_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCPushServer).
+					ReportNotification(
+						ctx,
+						in1.(*ReportNotificationRequest),
+					)
+			}, DRPCPushServer.ReportNotification, true
 	default:
 		return "", nil, nil, nil, false
 	}
@@ -369,6 +753,102 @@ func (x *drpcPush_RemoveSpaceStream) SendAndClose(m *Ok) error {
 	return x.CloseSend()
 }
 
+type DRPCPush_RotateSpaceKeyStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_RotateSpaceKeyStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_RotateSpaceKeyStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_TransferSpaceStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_TransferSpaceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_TransferSpaceStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_AddSpaceAdminStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_AddSpaceAdminStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_AddSpaceAdminStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_RemoveSpaceAdminStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_RemoveSpaceAdminStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_RemoveSpaceAdminStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_GetSpacePolicyStream interface {
+	drpc.Stream
+	SendAndClose(*GetSpacePolicyResponse) error
+}
+
+type drpcPush_GetSpacePolicyStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_GetSpacePolicyStream) SendAndClose(m *GetSpacePolicyResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_SetSpacePolicyStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_SetSpacePolicyStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_SetSpacePolicyStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
 type DRPCPush_SubscriptionsStream interface {
 	drpc.Stream
 	SendAndClose(*SubscriptionsResponse) error
@@ -433,6 +913,22 @@ func (x *drpcPush_SubscribeAllStream) SendAndClose(m *Ok) error {
 	return x.CloseSend()
 }
 
+type DRPCPush_SyncSubscriptionsStream interface {
+	drpc.Stream
+	SendAndClose(*SyncSubscriptionsResponse) error
+}
+
+type drpcPush_SyncSubscriptionsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_SyncSubscriptionsStream) SendAndClose(m *SyncSubscriptionsResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
 type DRPCPush_NotifyStream interface {
 	drpc.Stream
 	SendAndClose(*Ok) error
@@ -464,3 +960,147 @@ func (x *drpcPush_NotifySilentStream) SendAndClose(m *Ok) error {
 	}
 	return x.CloseSend()
 }
+
+type DRPCPush_NotifyPeerStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_NotifyPeerStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_NotifyPeerStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_ListDevicesStream interface {
+	drpc.Stream
+	SendAndClose(*ListDevicesResponse) error
+}
+
+type drpcPush_ListDevicesStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_ListDevicesStream) SendAndClose(m *ListDevicesResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_RevokeDeviceStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_RevokeDeviceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_RevokeDeviceStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_DeleteAccountStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_DeleteAccountStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_DeleteAccountStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_ExportAccountDataStream interface {
+	drpc.Stream
+	SendAndClose(*ExportAccountDataResponse) error
+}
+
+type drpcPush_ExportAccountDataStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_ExportAccountDataStream) SendAndClose(m *ExportAccountDataResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_BlockStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_BlockStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_BlockStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_UnblockStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_UnblockStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_UnblockStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_ListBlockedStream interface {
+	drpc.Stream
+	SendAndClose(*ListBlockedResponse) error
+}
+
+type drpcPush_ListBlockedStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_ListBlockedStream) SendAndClose(m *ListBlockedResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCPush_ReportNotificationStream interface {
+	drpc.Stream
+	SendAndClose(*Ok) error
+}
+
+type drpcPush_ReportNotificationStream struct {
+	drpc.Stream
+}
+
+func (x *drpcPush_ReportNotificationStream) SendAndClose(m *Ok) error {
+	if err := x.MsgSend(m, drpcEncoding_File_pushclient_pushapi_protos_push_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
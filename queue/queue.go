@@ -27,14 +27,21 @@ func New() Queue {
 }
 
 type Message struct {
-	IgnoreAccountId string         `json:"ignoreAccountId"`
-	KeyId           string         `json:"keyId"`
-	Payload         []byte         `json:"payload"`
-	Signature       []byte         `json:"signature"`
-	Topics          []domain.Topic `json:"topics"`
-	Created         time.Time      `json:"created"`
-	GroupId         string         `json:"groupId"`
-	Silent          bool           `json:"silent"`
+	IgnoreAccountId string `json:"ignoreAccountId"`
+	KeyId           string `json:"keyId"`
+	Payload         []byte `json:"payload"`
+	Signature       []byte `json:"signature"`
+	// Envelope means the payload is a marshaled pushapi.PayloadEnvelope
+	Envelope bool           `json:"envelope"`
+	Topics   []domain.Topic `json:"topics"`
+	Created  time.Time      `json:"created"`
+	GroupId  string         `json:"groupId"`
+	Silent   bool           `json:"silent"`
+	// SenderAccountId is the account that sent the message, recipients who blocked it are skipped
+	SenderAccountId string `json:"senderAccountId"`
+	// AccountId and PeerId target a single device instead of topic subscribers
+	AccountId string `json:"accountId"`
+	PeerId    string `json:"peerId"`
 }
 
 type Queue interface {
@@ -0,0 +1,23 @@
+package ratelimit
+
+type configSource interface {
+	GetRateLimit() Config
+}
+
+type Config struct {
+	Enabled bool  `yaml:"enabled"`
+	Account Limit `yaml:"account"`
+	Space   Limit `yaml:"space"`
+	Topic   Limit `yaml:"topic"`
+}
+
+// Limit allows Limit calls per WindowSec, zero Limit disables the check
+type Limit struct {
+	Limit     int `yaml:"limit"`
+	WindowSec int `yaml:"windowSec"`
+}
+
+// PerMinute allows n units per minute
+func PerMinute(n int) Limit {
+	return Limit{Limit: n, WindowSec: 60}
+}
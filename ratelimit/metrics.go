@@ -0,0 +1,13 @@
+package ratelimit
+
+import "github.com/prometheus/client_golang/prometheus"
+
+func registerMetrics(reg *prometheus.Registry, r *rateLimit) {
+	r.metrics.requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "push",
+		Subsystem: "ratelimit",
+		Name:      "requests_total",
+		Help:      "count of rate limit checks by scope and result",
+	}, []string{"scope", "result"})
+	reg.MustRegister(r.metrics.requests)
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/anyproto/anytype-push-server/ratelimit (interfaces: RateLimit)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock_ratelimit/mock_ratelimit.go github.com/anyproto/anytype-push-server/ratelimit RateLimit
+//
+
+// Package mock_ratelimit is a generated GoMock package.
+package mock_ratelimit
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	app "github.com/anyproto/any-sync/app"
+	ratelimit "github.com/anyproto/anytype-push-server/ratelimit"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRateLimit is a mock of RateLimit interface.
+type MockRateLimit struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitMockRecorder
+}
+
+// MockRateLimitMockRecorder is the mock recorder for MockRateLimit.
+type MockRateLimitMockRecorder struct {
+	mock *MockRateLimit
+}
+
+// NewMockRateLimit creates a new mock instance.
+func NewMockRateLimit(ctrl *gomock.Controller) *MockRateLimit {
+	mock := &MockRateLimit{ctrl: ctrl}
+	mock.recorder = &MockRateLimitMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimit) EXPECT() *MockRateLimitMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockRateLimit) Allow(arg0 context.Context, arg1 ...ratelimit.Key) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Allow", varargs...)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockRateLimitMockRecorder) Allow(arg0 any, arg1 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockRateLimit)(nil).Allow), varargs...)
+}
+
+// AllowN mocks base method.
+func (m *MockRateLimit) AllowN(arg0 context.Context, arg1 ratelimit.Key, arg2 int, arg3 ratelimit.Limit) (int, time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllowN", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(time.Duration)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AllowN indicates an expected call of AllowN.
+func (mr *MockRateLimitMockRecorder) AllowN(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllowN", reflect.TypeOf((*MockRateLimit)(nil).AllowN), arg0, arg1, arg2, arg3)
+}
+
+// Init mocks base method.
+func (m *MockRateLimit) Init(arg0 *app.App) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockRateLimitMockRecorder) Init(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockRateLimit)(nil).Init), arg0)
+}
+
+// Name mocks base method.
+func (m *MockRateLimit) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockRateLimitMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockRateLimit)(nil).Name))
+}
@@ -0,0 +1,213 @@
+//go:generate mockgen -destination mock_ratelimit/mock_ratelimit.go github.com/anyproto/anytype-push-server/ratelimit RateLimit
+
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/metric"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/anyproto/anytype-push-server/redisprovider"
+)
+
+const CName = "push.ratelimit"
+
+const keyPrefix = "push:rl:"
+
+type Scope string
+
+const (
+	ScopeAccount Scope = "account"
+	ScopeSpace   Scope = "space"
+	ScopeTopic   Scope = "topic"
+	// ScopeSpaceFanOut counts notified subscribers of the space, the limit is set by the space policy
+	ScopeSpaceFanOut Scope = "spaceFanOut"
+)
+
+type Key struct {
+	Scope Scope
+	Id    string
+}
+
+func New() RateLimit {
+	return new(rateLimit)
+}
+
+type RateLimit interface {
+	// Allow registers the call for every key, returns positive retryAfter when one of the budgets is exceeded.
+	// Keys are checked in one round trip, the budgets are spent only when the call is allowed.
+	Allow(ctx context.Context, keys ...Key) (retryAfter time.Duration, err error)
+	// AllowN spends up to n units of the key budget within a fixed window and returns the number of allowed units,
+	// retryAfter is positive when the budget is exhausted. n = 0 only checks the budget.
+	// The limit is given by the caller, the global Enabled flag doesn't apply.
+	AllowN(ctx context.Context, key Key, n int, limit Limit) (allowed int, retryAfter time.Duration, err error)
+	app.Component
+}
+
+// slidingWindowScript keeps call timestamps in a sorted set, returns 0 if the call is allowed or milliseconds to wait otherwise
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+if redis.call('ZCARD', key) >= limit then
+	local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+	return tonumber(oldest[2]) + window - now
+end
+redis.call('ZADD', key, now, ARGV[4])
+redis.call('PEXPIRE', key, window)
+return 0
+`)
+
+// fixedWindowScript counts units in a key expiring with the window, spends what is left of the requested units
+// and returns the allowed units with milliseconds to wait when the budget is exhausted
+var fixedWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local n = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local current = tonumber(redis.call('GET', key) or '0')
+local allowed = math.max(math.min(n, limit - current), 0)
+if allowed > 0 and redis.call('INCRBY', key, allowed) == allowed then
+	redis.call('PEXPIRE', key, window)
+end
+if allowed == n and current < limit then
+	return {allowed, 0}
+end
+local ttl = redis.call('PTTL', key)
+if ttl < 0 then
+	ttl = window
+end
+return {allowed, ttl}
+`)
+
+type rateLimit struct {
+	client  redis.UniversalClient
+	conf    Config
+	metrics struct {
+		requests *prometheus.CounterVec
+	}
+}
+
+func (r *rateLimit) Init(a *app.App) (err error) {
+	r.client = a.MustComponent(redisprovider.CName).(redisprovider.RedisProvider).Redis()
+	r.conf = a.MustComponent("config").(configSource).GetRateLimit()
+	registerMetrics(a.MustComponent(metric.CName).(metric.Metric).Registry(), r)
+	return
+}
+
+func (r *rateLimit) Name() (name string) {
+	return CName
+}
+
+func (r *rateLimit) Allow(ctx context.Context, keys ...Key) (retryAfter time.Duration, err error) {
+	if !r.conf.Enabled {
+		return 0, nil
+	}
+	type check struct {
+		key Key
+		cmd *redis.Cmd
+	}
+	var checks []check
+	now := time.Now().UnixMilli()
+	// the member must be unique to count calls made in the same millisecond
+	var suffix = make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	member := strconv.FormatInt(now, 10) + "-" + hex.EncodeToString(suffix)
+	// a pipeline instead of a single script keeps the keys on their own cluster slots
+	pipe := r.client.Pipeline()
+	for _, key := range keys {
+		limit := r.limit(key.Scope)
+		if limit.Limit <= 0 || limit.WindowSec <= 0 {
+			continue
+		}
+		window := time.Duration(limit.WindowSec) * time.Second
+		checks = append(checks, check{
+			key: key,
+			cmd: slidingWindowScript.Eval(ctx, pipe, []string{redisKey(key)}, now, window.Milliseconds(), limit.Limit, member),
+		})
+	}
+	if len(checks) == 0 {
+		return 0, nil
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	var spent []string
+	for _, c := range checks {
+		waitMs, _ := c.cmd.Int64()
+		if waitMs > 0 {
+			r.metrics.requests.WithLabelValues(string(c.key.Scope), "limited").Inc()
+			retryAfter = max(retryAfter, time.Duration(waitMs)*time.Millisecond)
+		} else {
+			spent = append(spent, redisKey(c.key))
+		}
+	}
+	if retryAfter == 0 {
+		for _, c := range checks {
+			r.metrics.requests.WithLabelValues(string(c.key.Scope), "allowed").Inc()
+		}
+		return 0, nil
+	}
+	// the call is limited, so the budgets spent on the other keys are returned
+	if len(spent) > 0 {
+		pipe = r.client.Pipeline()
+		for _, key := range spent {
+			pipe.ZRem(ctx, key, member)
+		}
+		if _, err = pipe.Exec(ctx); err != nil {
+			return 0, err
+		}
+	}
+	return retryAfter, nil
+}
+
+func (r *rateLimit) AllowN(ctx context.Context, key Key, n int, limit Limit) (allowed int, retryAfter time.Duration, err error) {
+	if limit.Limit <= 0 || limit.WindowSec <= 0 {
+		return n, 0, nil
+	}
+	window := time.Duration(limit.WindowSec) * time.Second
+	res, err := fixedWindowScript.Run(ctx, r.client,
+		[]string{redisKey(key)},
+		n, window.Milliseconds(), limit.Limit,
+	).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(res) != 2 {
+		return 0, 0, fmt.Errorf("ratelimit: unexpected script result: %v", res)
+	}
+	allowed, retryAfter = int(res[0]), time.Duration(res[1])*time.Millisecond
+	if retryAfter > 0 {
+		r.metrics.requests.WithLabelValues(string(key.Scope), "limited").Inc()
+	} else {
+		r.metrics.requests.WithLabelValues(string(key.Scope), "allowed").Inc()
+	}
+	return allowed, retryAfter, nil
+}
+
+func redisKey(key Key) string {
+	return keyPrefix + string(key.Scope) + ":" + key.Id
+}
+
+func (r *rateLimit) limit(scope Scope) Limit {
+	switch scope {
+	case ScopeAccount:
+		return r.conf.Account
+	case ScopeSpace:
+		return r.conf.Space
+	case ScopeTopic:
+		return r.conf.Topic
+	default:
+		return Limit{}
+	}
+}
@@ -0,0 +1,159 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/redisprovider/testredisprovider"
+)
+
+var ctx = context.Background()
+
+func TestRateLimit_Allow(t *testing.T) {
+	t.Run("limit exceeded", func(t *testing.T) {
+		fx := newFixture(t, Config{Enabled: true, Account: Limit{Limit: 2, WindowSec: 60}})
+		for range 2 {
+			retryAfter, err := fx.Allow(ctx, Key{Scope: ScopeAccount, Id: "a"})
+			require.NoError(t, err)
+			assert.Zero(t, retryAfter)
+		}
+		retryAfter, err := fx.Allow(ctx, Key{Scope: ScopeAccount, Id: "a"})
+		require.NoError(t, err)
+		assert.Greater(t, retryAfter, time.Duration(0))
+		assert.LessOrEqual(t, retryAfter, time.Minute)
+
+		// another account has its own budget
+		retryAfter, err = fx.Allow(ctx, Key{Scope: ScopeAccount, Id: "b"})
+		require.NoError(t, err)
+		assert.Zero(t, retryAfter)
+	})
+	t.Run("window slides", func(t *testing.T) {
+		fx := newFixture(t, Config{Enabled: true, Space: Limit{Limit: 1, WindowSec: 1}})
+		key := Key{Scope: ScopeSpace, Id: "s"}
+		retryAfter, err := fx.Allow(ctx, key)
+		require.NoError(t, err)
+		assert.Zero(t, retryAfter)
+		retryAfter, err = fx.Allow(ctx, key)
+		require.NoError(t, err)
+		require.Greater(t, retryAfter, time.Duration(0))
+
+		time.Sleep(retryAfter + 10*time.Millisecond)
+		retryAfter, err = fx.Allow(ctx, key)
+		require.NoError(t, err)
+		assert.Zero(t, retryAfter)
+	})
+	t.Run("budgets are not spent when limited", func(t *testing.T) {
+		fx := newFixture(t, Config{
+			Enabled: true,
+			Space:   Limit{Limit: 1, WindowSec: 60},
+			Topic:   Limit{Limit: 1, WindowSec: 60},
+		})
+		_, err := fx.Allow(ctx, Key{Scope: ScopeTopic, Id: "s/t1"})
+		require.NoError(t, err)
+		retryAfter, err := fx.Allow(ctx, Key{Scope: ScopeSpace, Id: "s"}, Key{Scope: ScopeTopic, Id: "s/t1"})
+		require.NoError(t, err)
+		assert.Greater(t, retryAfter, time.Duration(0))
+		// the space budget is not spent by the limited call
+		retryAfter, err = fx.Allow(ctx, Key{Scope: ScopeSpace, Id: "s"}, Key{Scope: ScopeTopic, Id: "s/t2"})
+		require.NoError(t, err)
+		assert.Zero(t, retryAfter)
+		// both budgets are spent now
+		retryAfter, err = fx.Allow(ctx, Key{Scope: ScopeTopic, Id: "s/t2"})
+		require.NoError(t, err)
+		assert.Greater(t, retryAfter, time.Duration(0))
+	})
+	t.Run("disabled", func(t *testing.T) {
+		fx := newFixture(t, Config{Account: Limit{Limit: 1, WindowSec: 60}})
+		for range 3 {
+			retryAfter, err := fx.Allow(ctx, Key{Scope: ScopeAccount, Id: "a"})
+			require.NoError(t, err)
+			assert.Zero(t, retryAfter)
+		}
+	})
+}
+
+func TestRateLimit_AllowN(t *testing.T) {
+	// the policy limit applies even when the configured limits are disabled
+	fx := newFixture(t, Config{})
+	key := Key{Scope: ScopeSpaceFanOut, Id: "s"}
+	limit := Limit{Limit: 10, WindowSec: 60}
+
+	allowed, retryAfter, err := fx.AllowN(ctx, key, 6, limit)
+	require.NoError(t, err)
+	assert.Equal(t, 6, allowed)
+	assert.Zero(t, retryAfter)
+	allowed, retryAfter, err = fx.AllowN(ctx, key, 0, limit)
+	require.NoError(t, err)
+	assert.Zero(t, allowed)
+	assert.Zero(t, retryAfter)
+
+	// only what is left of the budget is spent
+	allowed, retryAfter, err = fx.AllowN(ctx, key, 5, limit)
+	require.NoError(t, err)
+	assert.Equal(t, 4, allowed)
+	assert.Greater(t, retryAfter, time.Duration(0))
+	assert.LessOrEqual(t, retryAfter, time.Minute)
+
+	allowed, retryAfter, err = fx.AllowN(ctx, key, 0, limit)
+	require.NoError(t, err)
+	assert.Zero(t, allowed)
+	assert.Greater(t, retryAfter, time.Duration(0))
+	allowed, retryAfter, err = fx.AllowN(ctx, key, 3, limit)
+	require.NoError(t, err)
+	assert.Zero(t, allowed)
+	assert.Greater(t, retryAfter, time.Duration(0))
+
+	// the budget may be spent exactly
+	key.Id = "s2"
+	allowed, retryAfter, err = fx.AllowN(ctx, key, 10, limit)
+	require.NoError(t, err)
+	assert.Equal(t, 10, allowed)
+	assert.Zero(t, retryAfter)
+}
+
+type fixture struct {
+	RateLimit
+	a *app.App
+}
+
+func newFixture(t *testing.T, conf Config) *fixture {
+	fx := &fixture{
+		RateLimit: New(),
+		a:         new(app.App),
+	}
+	fx.a.Register(&testConfig{conf: conf}).
+		Register(metric.New()).
+		Register(testredisprovider.NewTestRedisProvider()).
+		Register(fx.RateLimit)
+	require.NoError(t, fx.a.Start(ctx))
+	t.Cleanup(func() {
+		require.NoError(t, fx.a.Close(ctx))
+	})
+	return fx
+}
+
+type testConfig struct {
+	conf Config
+}
+
+func (c *testConfig) Init(a *app.App) (err error) {
+	return
+}
+
+func (c *testConfig) Name() (name string) {
+	return "config"
+}
+
+func (c *testConfig) GetRateLimit() Config {
+	return c.conf
+}
+
+func (c *testConfig) GetMetric() metric.Config {
+	return metric.Config{}
+}
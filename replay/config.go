@@ -0,0 +1,13 @@
+package replay
+
+const defaultMaxClockSkewSec = 300
+
+type configSource interface {
+	GetReplay() Config
+}
+
+type Config struct {
+	// RequireEnvelope rejects messages signed without an envelope, by default they are accepted without replay protection
+	RequireEnvelope bool `yaml:"requireEnvelope"`
+	MaxClockSkewSec int  `yaml:"maxClockSkewSec"`
+}
@@ -0,0 +1,13 @@
+package replay
+
+import "github.com/prometheus/client_golang/prometheus"
+
+func registerMetrics(reg *prometheus.Registry, r *replay) {
+	r.metrics.checks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "push",
+		Subsystem: "replay",
+		Name:      "checks_total",
+		Help:      "count of envelope checks by result",
+	}, []string{"result"})
+	reg.MustRegister(r.metrics.checks)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/anyproto/anytype-push-server/replay (interfaces: Replay)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock_replay/mock_replay.go github.com/anyproto/anytype-push-server/replay Replay
+//
+
+// Package mock_replay is a generated GoMock package.
+package mock_replay
+
+import (
+	context "context"
+	reflect "reflect"
+
+	app "github.com/anyproto/any-sync/app"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReplay is a mock of Replay interface.
+type MockReplay struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplayMockRecorder
+}
+
+// MockReplayMockRecorder is the mock recorder for MockReplay.
+type MockReplayMockRecorder struct {
+	mock *MockReplay
+}
+
+// NewMockReplay creates a new mock instance.
+func NewMockReplay(ctrl *gomock.Controller) *MockReplay {
+	mock := &MockReplay{ctrl: ctrl}
+	mock.recorder = &MockReplayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplay) EXPECT() *MockReplayMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockReplay) Check(arg0 int64, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockReplayMockRecorder) Check(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockReplay)(nil).Check), arg0, arg1)
+}
+
+// Init mocks base method.
+func (m *MockReplay) Init(arg0 *app.App) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockReplayMockRecorder) Init(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockReplay)(nil).Init), arg0)
+}
+
+// Name mocks base method.
+func (m *MockReplay) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockReplayMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockReplay)(nil).Name))
+}
+
+// Remember mocks base method.
+func (m *MockReplay) Remember(arg0 context.Context, arg1 string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remember indicates an expected call of Remember.
+func (mr *MockReplayMockRecorder) Remember(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remember", reflect.TypeOf((*MockReplay)(nil).Remember), arg0, arg1, arg2)
+}
+
+// RequireEnvelope mocks base method.
+func (m *MockReplay) RequireEnvelope() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequireEnvelope")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// RequireEnvelope indicates an expected call of RequireEnvelope.
+func (mr *MockReplayMockRecorder) RequireEnvelope() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequireEnvelope", reflect.TypeOf((*MockReplay)(nil).RequireEnvelope))
+}
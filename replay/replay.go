@@ -0,0 +1,100 @@
+//go:generate mockgen -destination mock_replay/mock_replay.go github.com/anyproto/anytype-push-server/replay Replay
+
+package replay
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/metric"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/anyproto/anytype-push-server/redisprovider"
+)
+
+const CName = "push.replay"
+
+const keyPrefix = "push:nonce:"
+
+const (
+	minNonceLength = 16
+	maxNonceLength = 64
+)
+
+var (
+	ErrInvalidNonce = errors.New("invalid nonce")
+	ErrExpired      = errors.New("timestamp is out of the allowed window")
+	ErrReplayed     = errors.New("nonce was already used")
+)
+
+func New() Replay {
+	return new(replay)
+}
+
+type Replay interface {
+	// Check verifies the nonce and that the timestamp is within the clock skew window
+	Check(timestamp int64, nonce []byte) (err error)
+	// Remember stores the nonce of the account, returns ErrReplayed if the nonce was already seen.
+	// It's called right before the message is queued, so a message rejected by other checks doesn't burn its nonce
+	Remember(ctx context.Context, accountId string, nonce []byte) (err error)
+	// RequireEnvelope reports whether messages without an envelope are rejected
+	RequireEnvelope() bool
+	app.Component
+}
+
+type replay struct {
+	client  redis.UniversalClient
+	conf    Config
+	metrics struct {
+		checks *prometheus.CounterVec
+	}
+}
+
+func (r *replay) Init(a *app.App) (err error) {
+	r.client = a.MustComponent(redisprovider.CName).(redisprovider.RedisProvider).Redis()
+	r.conf = a.MustComponent("config").(configSource).GetReplay()
+	if r.conf.MaxClockSkewSec <= 0 {
+		r.conf.MaxClockSkewSec = defaultMaxClockSkewSec
+	}
+	registerMetrics(a.MustComponent(metric.CName).(metric.Metric).Registry(), r)
+	return
+}
+
+func (r *replay) Name() (name string) {
+	return CName
+}
+
+func (r *replay) RequireEnvelope() bool {
+	return r.conf.RequireEnvelope
+}
+
+func (r *replay) Check(timestamp int64, nonce []byte) (err error) {
+	if len(nonce) < minNonceLength || len(nonce) > maxNonceLength {
+		return ErrInvalidNonce
+	}
+	skew := time.Duration(r.conf.MaxClockSkewSec) * time.Second
+	if diff := time.Since(time.Unix(timestamp, 0)); diff > skew || diff < -skew {
+		r.metrics.checks.WithLabelValues("expired").Inc()
+		return ErrExpired
+	}
+	return nil
+}
+
+func (r *replay) Remember(ctx context.Context, accountId string, nonce []byte) (err error) {
+	skew := time.Duration(r.conf.MaxClockSkewSec) * time.Second
+	// a nonce is kept while its timestamp can pass the window check
+	ok, err := r.client.SetNX(ctx, keyPrefix+accountId+":"+hex.EncodeToString(nonce), 1, skew*2).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		r.metrics.checks.WithLabelValues("replayed").Inc()
+		return ErrReplayed
+	}
+	r.metrics.checks.WithLabelValues("ok").Inc()
+	return nil
+}
@@ -0,0 +1,92 @@
+package replay
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/redisprovider/testredisprovider"
+)
+
+var ctx = context.Background()
+
+func TestReplay_Check(t *testing.T) {
+	fx := newFixture(t, Config{MaxClockSkewSec: 60})
+	now := time.Now().Unix()
+	t.Run("expired", func(t *testing.T) {
+		assert.ErrorIs(t, fx.Check(now-120, newNonce()), ErrExpired)
+		assert.ErrorIs(t, fx.Check(now+120, newNonce()), ErrExpired)
+		require.NoError(t, fx.Check(now-30, newNonce()))
+	})
+	t.Run("invalid nonce", func(t *testing.T) {
+		assert.ErrorIs(t, fx.Check(now, nil), ErrInvalidNonce)
+		assert.ErrorIs(t, fx.Check(now, make([]byte, maxNonceLength+1)), ErrInvalidNonce)
+	})
+	t.Run("nonce is not stored", func(t *testing.T) {
+		nonce := newNonce()
+		require.NoError(t, fx.Check(now, nonce))
+		require.NoError(t, fx.Check(now, nonce))
+	})
+}
+
+func TestReplay_Remember(t *testing.T) {
+	fx := newFixture(t, Config{MaxClockSkewSec: 60})
+	nonce := newNonce()
+	require.NoError(t, fx.Remember(ctx, "a1", nonce))
+	assert.ErrorIs(t, fx.Remember(ctx, "a1", nonce), ErrReplayed)
+	// nonces are per account
+	require.NoError(t, fx.Remember(ctx, "a2", nonce))
+}
+
+func newNonce() []byte {
+	nonce := make([]byte, minNonceLength)
+	_, _ = rand.Read(nonce)
+	return nonce
+}
+
+type fixture struct {
+	Replay
+	a *app.App
+}
+
+func newFixture(t *testing.T, conf Config) *fixture {
+	fx := &fixture{
+		Replay: New(),
+		a:      new(app.App),
+	}
+	fx.a.Register(&testConfig{conf: conf}).
+		Register(metric.New()).
+		Register(testredisprovider.NewTestRedisProvider()).
+		Register(fx.Replay)
+	require.NoError(t, fx.a.Start(ctx))
+	t.Cleanup(func() {
+		require.NoError(t, fx.a.Close(ctx))
+	})
+	return fx
+}
+
+type testConfig struct {
+	conf Config
+}
+
+func (c *testConfig) Init(a *app.App) (err error) {
+	return
+}
+
+func (c *testConfig) Name() (name string) {
+	return "config"
+}
+
+func (c *testConfig) GetReplay() Config {
+	return c.conf
+}
+
+func (c *testConfig) GetMetric() metric.Config {
+	return metric.Config{}
+}
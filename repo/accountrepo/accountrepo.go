@@ -5,9 +5,11 @@ package accountrepo
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/logger"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -20,23 +22,48 @@ const CName = "push.accountrepo"
 
 const collName = "account"
 
+var log = logger.NewNamed(CName)
+
+// changeLogSize is a number of topic changes kept for SyncSubscriptions
+const changeLogSize = 100
+
+var ErrVersionConflict = errors.New("version conflict")
+
 func New() AccountRepo {
 	return new(accountRepo)
 }
 
 type AccountRepo interface {
 	SetAccountTopics(ctx context.Context, accountId string, topics []domain.Topic) error
+	// SetAccountTopicsIfVersion replaces topics only when the account version equals to expectedVersion, returns ErrVersionConflict otherwise
+	SetAccountTopicsIfVersion(ctx context.Context, accountId string, topics []domain.Topic, expectedVersion int64) error
+	// AddAccountTopics adds topics to the account, topics listed in expiring are removed after the expiry time
+	AddAccountTopics(ctx context.Context, accountId string, topics []domain.Topic, expiring []domain.TopicExpiry) error
+	RemoveAccountTopics(ctx context.Context, accountId string, topics []domain.Topic) error
+	// RemoveSpaceTopics removes topics of the space from all accounts
+	RemoveSpaceTopics(ctx context.Context, spaceKey string) error
+	// RenameSpaceTopics moves subscriptions of all accounts from the old space key to the new one
+	RenameSpaceTopics(ctx context.Context, oldSpaceKey, newSpaceKey string) error
 	GetAccountIdsByTopics(ctx context.Context, topics []domain.Topic) ([]string, error)
 	GetTopicsByAccountId(ctx context.Context, accountId string) (topics []domain.Topic, err error)
+	GetAccount(ctx context.Context, accountId string) (account domain.Account, err error)
+	BlockAccount(ctx context.Context, accountId, blockedAccountId string) error
+	UnblockAccount(ctx context.Context, accountId, blockedAccountId string) error
+	// GetAccountIdsBlocking returns ids from the list that have blockedAccountId in their block list
+	GetAccountIdsBlocking(ctx context.Context, accountIds []string, blockedAccountId string) ([]string, error)
+	RemoveAccount(ctx context.Context, accountId string) error
 	app.ComponentRunnable
 }
 
 type accountRepo struct {
-	coll *mongo.Collection
+	coll         *mongo.Collection
+	runCtx       context.Context
+	runCtxCancel context.CancelFunc
 }
 
 func (r *accountRepo) Init(a *app.App) (err error) {
 	r.coll = a.MustComponent(db.CName).(db.Database).Db().Collection(collName)
+	r.runCtx, r.runCtxCancel = context.WithCancel(context.Background())
 	return
 }
 
@@ -45,26 +72,150 @@ func (r *accountRepo) Name() (name string) {
 }
 
 func (r *accountRepo) Run(ctx context.Context) error {
-	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{{"topics", 1}},
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"topics", 1}}},
+		{Keys: bson.D{{"expiring.expires", 1}}},
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	go r.runExpiredTopicsGC()
+	return nil
 }
 
 func (r *accountRepo) SetAccountTopics(ctx context.Context, accountId string, topics []domain.Topic) error {
 	opts := options.Update().SetUpsert(true)
-	_, err := r.coll.UpdateByID(
-		ctx,
-		accountId,
-		bson.D{
-			{"$set", bson.D{{"topics", topics}, {"updated", time.Now().Unix()}}},
-			{"$setOnInsert", bson.D{{"created", time.Now().Unix()}}},
-		},
-		opts,
-	)
+	_, err := r.coll.UpdateByID(ctx, accountId, topicsUpdate(topics, nil, nil, true), opts)
+	return err
+}
+
+func (r *accountRepo) SetAccountTopicsIfVersion(ctx context.Context, accountId string, topics []domain.Topic, expectedVersion int64) error {
+	filter := bson.D{{"_id", accountId}}
+	opts := options.Update()
+	if expectedVersion == 0 {
+		// accounts created before versioning have no version field
+		filter = append(filter, bson.E{"version", bson.D{{"$in", bson.A{0, nil}}}})
+		// zero version also means a new account
+		opts.SetUpsert(true)
+	} else {
+		filter = append(filter, bson.E{"version", expectedVersion})
+	}
+	res, err := r.coll.UpdateOne(ctx, filter, topicsUpdate(topics, nil, nil, true), opts)
+	// the upsert conflicts with the existing document when the version doesn't match
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrVersionConflict
+	}
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 && res.UpsertedCount == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *accountRepo) AddAccountTopics(ctx context.Context, accountId string, topics []domain.Topic, expiring []domain.TopicExpiry) error {
+	opts := options.Update().SetUpsert(true)
+	_, err := r.coll.UpdateByID(ctx, accountId, topicsUpdate(topics, nil, expiring, false), opts)
+	return err
+}
+
+func (r *accountRepo) RemoveAccountTopics(ctx context.Context, accountId string, topics []domain.Topic) error {
+	_, err := r.coll.UpdateByID(ctx, accountId, topicsUpdate(nil, topics, nil, false))
 	return err
 }
 
+// topicsUpdate builds a pipeline update that changes topics and their expiry, increments the version and appends the change to the log.
+// With reset the added topics replace the whole list.
+// Every change increments the version by one, so the version of a log entry is derived from its position.
+func topicsUpdate(added, removed []domain.Topic, expiring []domain.TopicExpiry, reset bool) bson.A {
+	now := time.Now().Unix()
+	added, removed = uniqueTopics(added), uniqueTopics(removed)
+	if expiring == nil {
+		expiring = []domain.TopicExpiry{}
+	}
+	change := domain.TopicsChange{Reset: true}
+	if !reset {
+		change = domain.TopicsChange{Added: added, Removed: removed}
+	}
+	curTopics := bson.D{{"$ifNull", bson.A{"$topics", bson.A{}}}}
+	curExpiring := bson.D{{"$ifNull", bson.A{"$expiring", bson.A{}}}}
+	// keeps expiry records of topics not in the list
+	expiringExcept := func(topics []domain.Topic) bson.D {
+		return bson.D{{"$filter", bson.D{
+			{"input", curExpiring},
+			{"cond", bson.D{{"$not", bson.A{bson.D{{"$in", bson.A{"$$this.topic", bson.D{{"$literal", topics}}}}}}}}},
+		}}}
+	}
+	var set bson.D
+	switch {
+	case reset:
+		set = bson.D{
+			{"topics", bson.D{{"$literal", added}}},
+			{"expiring", bson.D{{"$literal", expiring}}},
+		}
+	case len(removed) > 0:
+		set = bson.D{
+			{"topics", bson.D{{"$filter", bson.D{
+				{"input", curTopics},
+				{"cond", bson.D{{"$not", bson.A{bson.D{{"$in", bson.A{"$$this", bson.D{{"$literal", removed}}}}}}}}},
+			}}}},
+			{"expiring", expiringExcept(removed)},
+		}
+	default:
+		set = bson.D{
+			{"topics", bson.D{{"$concatArrays", bson.A{
+				curTopics,
+				bson.D{{"$filter", bson.D{
+					{"input", bson.D{{"$literal", added}}},
+					{"cond", bson.D{{"$not", bson.A{bson.D{{"$in", bson.A{"$$this", curTopics}}}}}}},
+				}}},
+			}}}},
+			// a new subscription replaces the previous expiry of the topic
+			{"expiring", bson.D{{"$concatArrays", bson.A{
+				expiringExcept(added),
+				bson.D{{"$literal", expiring}},
+			}}}},
+		}
+	}
+	set = append(set,
+		bson.E{"updated", now},
+		bson.E{"created", bson.D{{"$ifNull", bson.A{"$created", now}}}},
+		bson.E{"version", bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$version", 0}}}, 1}}}},
+		bson.E{"changes", changesPush(bson.D{{"$literal", change}})},
+	)
+	return bson.A{bson.D{{"$set", set}}}
+}
+
+// changesPush appends the change expression to the change log keeping only the latest changeLogSize entries
+func changesPush(change any) bson.D {
+	return bson.D{{"$slice", bson.A{
+		bson.D{{"$concatArrays", bson.A{
+			bson.D{{"$ifNull", bson.A{"$changes", bson.A{}}}},
+			bson.A{change},
+		}}},
+		-changeLogSize,
+	}}}
+}
+
+func uniqueTopics(topics []domain.Topic) []domain.Topic {
+	result := make([]domain.Topic, 0, len(topics))
+	for _, topic := range topics {
+		if !slices.Contains(result, topic) {
+			result = append(result, topic)
+		}
+	}
+	return result
+}
+
+func (r *accountRepo) GetAccount(ctx context.Context, accountId string) (account domain.Account, err error) {
+	err = r.coll.FindOne(ctx, bson.M{"_id": accountId}).Decode(&account)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Account{Id: accountId}, nil
+	}
+	return
+}
+
 type docId struct {
 	Id string `bson:"_id"`
 }
@@ -74,7 +225,10 @@ type withTopics struct {
 }
 
 func (r *accountRepo) GetAccountIdsByTopics(ctx context.Context, topics []domain.Topic) ([]string, error) {
-	cur, err := r.coll.Find(ctx, bson.M{"topics": bson.M{"$in": topics}}, options.Find().SetProjection(bson.M{"_id": 1}))
+	cur, err := r.coll.Find(ctx, bson.D{
+		{"topics", bson.D{{"$in", topics}}},
+		{"$expr", hasActiveTopic(topics, time.Now().Unix())},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
 	if err != nil {
 		return nil, err
 	}
@@ -104,6 +258,57 @@ func (r *accountRepo) GetTopicsByAccountId(ctx context.Context, accountId string
 	return topicsRes.Topics, nil
 }
 
+func (r *accountRepo) BlockAccount(ctx context.Context, accountId, blockedAccountId string) error {
+	now := time.Now().Unix()
+	_, err := r.coll.UpdateByID(ctx, accountId, bson.D{
+		{"$addToSet", bson.D{{"blocked", blockedAccountId}}},
+		{"$set", bson.D{{"updated", now}}},
+		{"$setOnInsert", bson.D{{"created", now}}},
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *accountRepo) UnblockAccount(ctx context.Context, accountId, blockedAccountId string) error {
+	_, err := r.coll.UpdateByID(ctx, accountId, bson.D{
+		{"$pull", bson.D{{"blocked", blockedAccountId}}},
+		{"$set", bson.D{{"updated", time.Now().Unix()}}},
+	})
+	return err
+}
+
+func (r *accountRepo) GetAccountIdsBlocking(ctx context.Context, accountIds []string, blockedAccountId string) ([]string, error) {
+	if len(accountIds) == 0 {
+		return nil, nil
+	}
+	cur, err := r.coll.Find(ctx, bson.D{
+		{"_id", bson.D{{"$in", accountIds}}},
+		{"blocked", blockedAccountId},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+	var docs []docId
+	if err = cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(docs))
+	for i, d := range docs {
+		ids[i] = d.Id
+	}
+	return ids, nil
+}
+
+func (r *accountRepo) RemoveAccount(ctx context.Context, accountId string) error {
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": accountId})
+	return err
+}
+
 func (r *accountRepo) Close(ctx context.Context) error {
+	if r.runCtxCancel != nil {
+		r.runCtxCancel()
+	}
 	return nil
 }
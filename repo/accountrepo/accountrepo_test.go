@@ -3,6 +3,7 @@ package accountrepo
 import (
 	"context"
 	"crypto/rand"
+	"sync"
 	"testing"
 
 	"github.com/anyproto/any-sync/app"
@@ -49,6 +50,22 @@ func TestAccountRepo_GetAccountTopics(t *testing.T) {
 	assert.Equal(t, []string{"a"}, result)
 }
 
+func TestAccountRepo_GetAccountIdsByTopics_Wildcard(t *testing.T) {
+	fx := newFixture(t)
+	topic := newTestTopic()
+	require.NoError(t, fx.SetAccountTopics(ctx, "a", []domain.Topic{topic}))
+	require.NoError(t, fx.SetAccountTopics(ctx, "b", []domain.Topic{topic.SpaceWildcard()}))
+	require.NoError(t, fx.SetAccountTopics(ctx, "c", []domain.Topic{newTestTopic().SpaceWildcard()}))
+
+	result, err := fx.GetAccountIdsByTopics(ctx, domain.WithSpaceWildcards([]domain.Topic{topic}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, result)
+
+	result, err = fx.GetAccountIdsByTopics(ctx, []domain.Topic{topic})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, result)
+}
+
 func TestAccountRepo_GetTopicsByAccount(t *testing.T) {
 	fx := newFixture(t)
 	topics := []domain.Topic{newTestTopic(), newTestTopic(), newTestTopic()}
@@ -68,6 +85,184 @@ func TestAccountRepo_GetTopicsByAccount(t *testing.T) {
 
 }
 
+func TestAccountRepo_RemoveAccount(t *testing.T) {
+	fx := newFixture(t)
+	topics := []domain.Topic{newTestTopic(), newTestTopic()}
+	require.NoError(t, fx.SetAccountTopics(ctx, "a", topics[:1]))
+	require.NoError(t, fx.SetAccountTopics(ctx, "b", topics))
+
+	require.NoError(t, fx.RemoveAccount(ctx, "a"))
+	require.NoError(t, fx.RemoveAccount(ctx, "a"))
+
+	result, err := fx.GetTopicsByAccountId(ctx, "a")
+	require.NoError(t, err)
+	assert.Len(t, result, 0)
+
+	accountIds, err := fx.GetAccountIdsByTopics(ctx, topics)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, accountIds)
+}
+
+func TestAccountRepo_AddAccountTopics(t *testing.T) {
+	fx := newFixture(t)
+	topics := []domain.Topic{newTestTopic(), newTestTopic(), newTestTopic()}
+	require.NoError(t, fx.AddAccountTopics(ctx, "a", topics[:2], nil))
+	require.NoError(t, fx.AddAccountTopics(ctx, "a", topics[1:], nil))
+
+	result, err := fx.GetTopicsByAccountId(ctx, "a")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, topics, result)
+}
+
+func TestAccountRepo_RemoveAccountTopics(t *testing.T) {
+	fx := newFixture(t)
+	topics := []domain.Topic{newTestTopic(), newTestTopic(), newTestTopic()}
+	require.NoError(t, fx.SetAccountTopics(ctx, "a", topics))
+	require.NoError(t, fx.RemoveAccountTopics(ctx, "a", topics[:2]))
+	// unknown account
+	require.NoError(t, fx.RemoveAccountTopics(ctx, "b", topics))
+
+	result, err := fx.GetTopicsByAccountId(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, topics[2:], result)
+}
+
+func TestAccountRepo_BlockAccount(t *testing.T) {
+	fx := newFixture(t)
+	topics := []domain.Topic{newTestTopic()}
+	require.NoError(t, fx.SetAccountTopics(ctx, "a", topics))
+	require.NoError(t, fx.BlockAccount(ctx, "a", "c"))
+	require.NoError(t, fx.BlockAccount(ctx, "a", "c"))
+	// an account without topics
+	require.NoError(t, fx.BlockAccount(ctx, "b", "c"))
+
+	account, err := fx.GetAccount(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, account.Blocked)
+	assert.Equal(t, topics, account.Topics)
+
+	blocking, err := fx.GetAccountIdsBlocking(ctx, []string{"a", "b", "d"}, "c")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, blocking)
+
+	require.NoError(t, fx.UnblockAccount(ctx, "a", "c"))
+	blocking, err = fx.GetAccountIdsBlocking(ctx, []string{"a", "b", "d"}, "c")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, blocking)
+}
+
+func TestAccountRepo_Version(t *testing.T) {
+	fx := newFixture(t)
+	topics := []domain.Topic{newTestTopic(), newTestTopic(), newTestTopic()}
+	require.NoError(t, fx.SetAccountTopics(ctx, "a", topics[:1]))
+	require.NoError(t, fx.AddAccountTopics(ctx, "a", topics[1:], nil))
+	require.NoError(t, fx.RemoveAccountTopics(ctx, "a", topics[:1]))
+
+	account, err := fx.GetAccount(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), account.Version)
+	assert.Equal(t, topics[1:], account.Topics)
+	assert.Equal(t, []domain.TopicsChange{
+		{Reset: true},
+		{Added: topics[1:]},
+		{Removed: topics[:1]},
+	}, account.Changes)
+
+	account, err = fx.GetAccount(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), account.Version)
+}
+
+func TestAccountRepo_ChangeLogSize(t *testing.T) {
+	fx := newFixture(t)
+	for range changeLogSize + 10 {
+		require.NoError(t, fx.AddAccountTopics(ctx, "a", []domain.Topic{newTestTopic()}, nil))
+	}
+	account, err := fx.GetAccount(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(changeLogSize+10), account.Version)
+	assert.Len(t, account.Changes, changeLogSize)
+	assert.Equal(t, account.Topics[len(account.Topics)-1:], account.Changes[changeLogSize-1].Added)
+}
+
+func TestAccountRepo_SetAccountTopicsIfVersion(t *testing.T) {
+	fx := newFixture(t)
+	topics := []domain.Topic{newTestTopic(), newTestTopic()}
+	// new account
+	require.NoError(t, fx.SetAccountTopicsIfVersion(ctx, "a", topics[:1], 0))
+	assert.ErrorIs(t, fx.SetAccountTopicsIfVersion(ctx, "a", topics, 0), ErrVersionConflict)
+	require.NoError(t, fx.SetAccountTopicsIfVersion(ctx, "a", topics, 1))
+	assert.ErrorIs(t, fx.SetAccountTopicsIfVersion(ctx, "a", topics[:1], 1), ErrVersionConflict)
+	// unknown account with non-zero version
+	assert.ErrorIs(t, fx.SetAccountTopicsIfVersion(ctx, "c", topics, 3), ErrVersionConflict)
+
+	account, err := fx.GetAccount(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), account.Version)
+	assert.Equal(t, topics, account.Topics)
+
+	t.Run("legacy account", func(t *testing.T) {
+		_, err := fx.AccountRepo.(*accountRepo).coll.InsertOne(ctx, map[string]any{"_id": "b", "topics": topics})
+		require.NoError(t, err)
+		require.NoError(t, fx.SetAccountTopicsIfVersion(ctx, "b", topics[:1], 0))
+		account, err := fx.GetAccount(ctx, "b")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), account.Version)
+	})
+}
+
+func TestAccountRepo_ConcurrentTopicsUpdate(t *testing.T) {
+	const workers = 20
+	t.Run("add", func(t *testing.T) {
+		fx := newFixture(t)
+		topics := make([]domain.Topic, workers)
+		for i := range topics {
+			topics[i] = newTestTopic()
+		}
+		var wg sync.WaitGroup
+		for _, topic := range topics {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, fx.AddAccountTopics(ctx, "a", []domain.Topic{topic}, nil))
+			}()
+		}
+		wg.Wait()
+
+		result, err := fx.GetTopicsByAccountId(ctx, "a")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, topics, result)
+	})
+	t.Run("add and remove", func(t *testing.T) {
+		fx := newFixture(t)
+		toRemove := make([]domain.Topic, workers)
+		toAdd := make([]domain.Topic, workers)
+		for i := range workers {
+			toRemove[i] = newTestTopic()
+			toAdd[i] = newTestTopic()
+		}
+		require.NoError(t, fx.SetAccountTopics(ctx, "a", toRemove))
+
+		var wg sync.WaitGroup
+		for i := range workers {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, fx.AddAccountTopics(ctx, "a", []domain.Topic{toAdd[i]}, nil))
+			}()
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, fx.RemoveAccountTopics(ctx, "a", []domain.Topic{toRemove[i]}))
+			}()
+		}
+		wg.Wait()
+
+		result, err := fx.GetTopicsByAccountId(ctx, "a")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, toAdd, result)
+	})
+}
+
 func newFixture(t testing.TB) *fixture {
 	fx := &fixture{
 		AccountRepo: New(),
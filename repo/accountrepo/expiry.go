@@ -0,0 +1,88 @@
+package accountrepo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+const expiredTopicsGCInterval = 10 * time.Minute
+
+// hasActiveTopic is an expression that checks the account has at least one of the topics not expired at the given time,
+// expired topics stay in the account until the next gc run
+func hasActiveTopic(topics []domain.Topic, now int64) bson.D {
+	expiredTopics := bson.D{{"$map", bson.D{
+		{"input", bson.D{{"$filter", bson.D{
+			{"input", bson.D{{"$ifNull", bson.A{"$expiring", bson.A{}}}}},
+			{"as", "e"},
+			{"cond", bson.D{{"$lt", bson.A{"$$e.expires", now}}}},
+		}}}},
+		{"as", "e"},
+		{"in", "$$e.topic"},
+	}}}
+	return bson.D{{"$anyElementTrue", bson.A{bson.D{{"$map", bson.D{
+		{"input", bson.D{{"$literal", topics}}},
+		{"as", "topic"},
+		{"in", bson.D{{"$and", bson.A{
+			bson.D{{"$in", bson.A{"$$topic", bson.D{{"$ifNull", bson.A{"$topics", bson.A{}}}}}}},
+			bson.D{{"$not", bson.A{bson.D{{"$in", bson.A{"$$topic", expiredTopics}}}}}},
+		}}}},
+	}}}}}}
+}
+
+func (r *accountRepo) runExpiredTopicsGC() {
+	ticker := time.NewTicker(expiredTopicsGCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.runCtx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := r.removeExpiredTopics(r.runCtx, time.Now()); err != nil {
+			log.Warn("remove expired topics error", zap.Error(err))
+		}
+	}
+}
+
+// removeExpiredTopics removes expired subscriptions and records the removal to the change log
+func (r *accountRepo) removeExpiredTopics(ctx context.Context, now time.Time) (err error) {
+	ts := now.Unix()
+	res, err := r.coll.UpdateMany(ctx,
+		bson.D{{"expiring.expires", bson.D{{"$lt", ts}}}},
+		bson.A{
+			bson.D{{"$set", bson.D{{"_expired", bson.D{{"$map", bson.D{
+				{"input", bson.D{{"$filter", bson.D{
+					{"input", "$expiring"},
+					{"cond", bson.D{{"$lt", bson.A{"$$this.expires", ts}}}},
+				}}}},
+				{"in", "$$this.topic"},
+			}}}}}}},
+			bson.D{{"$set", bson.D{
+				{"topics", bson.D{{"$filter", bson.D{
+					{"input", bson.D{{"$ifNull", bson.A{"$topics", bson.A{}}}}},
+					{"cond", bson.D{{"$not", bson.A{bson.D{{"$in", bson.A{"$$this", "$_expired"}}}}}}},
+				}}}},
+				{"expiring", bson.D{{"$filter", bson.D{
+					{"input", "$expiring"},
+					{"cond", bson.D{{"$gte", bson.A{"$$this.expires", ts}}}},
+				}}}},
+				{"updated", ts},
+				{"version", bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$version", 0}}}, 1}}}},
+				{"changes", changesPush(bson.D{{"removed", "$_expired"}})},
+			}}},
+			bson.D{{"$unset", "_expired"}},
+		},
+	)
+	if err != nil {
+		return
+	}
+	if res.ModifiedCount > 0 {
+		log.Info("expired topics removed", zap.Int64("accounts", res.ModifiedCount))
+	}
+	return
+}
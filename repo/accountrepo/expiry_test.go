@@ -0,0 +1,62 @@
+package accountrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+func TestAccountRepo_ExpiringTopics(t *testing.T) {
+	now := time.Now()
+	fx := newFixture(t)
+	topics := []domain.Topic{newTestTopic(), newTestTopic(), newTestTopic()}
+	// permanent topic
+	require.NoError(t, fx.AddAccountTopics(ctx, "a", topics[:1], nil))
+	require.NoError(t, fx.AddAccountTopics(ctx, "a", topics[1:], []domain.TopicExpiry{
+		{Topic: topics[1], Expires: now.Add(-time.Minute).Unix()},
+		{Topic: topics[2], Expires: now.Add(time.Hour).Unix()},
+	}))
+	require.NoError(t, fx.AddAccountTopics(ctx, "b", topics[1:2], nil))
+
+	t.Run("expired topics are not matched", func(t *testing.T) {
+		result, err := fx.GetAccountIdsByTopics(ctx, topics[1:2])
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b"}, result)
+
+		result, err = fx.GetAccountIdsByTopics(ctx, topics)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "b"}, result)
+	})
+	t.Run("resubscribe replaces expiry", func(t *testing.T) {
+		require.NoError(t, fx.AddAccountTopics(ctx, "b", topics[2:], []domain.TopicExpiry{
+			{Topic: topics[2], Expires: now.Add(time.Hour).Unix()},
+		}))
+		require.NoError(t, fx.AddAccountTopics(ctx, "b", topics[2:], nil))
+		account, err := fx.GetAccount(ctx, "b")
+		require.NoError(t, err)
+		assert.Empty(t, account.Expiring)
+	})
+	t.Run("gc", func(t *testing.T) {
+		before, err := fx.GetAccount(ctx, "a")
+		require.NoError(t, err)
+
+		require.NoError(t, fx.AccountRepo.(*accountRepo).removeExpiredTopics(ctx, now))
+
+		account, err := fx.GetAccount(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Topic{topics[0], topics[2]}, account.Topics)
+		assert.Equal(t, []domain.TopicExpiry{{Topic: topics[2], Expires: now.Add(time.Hour).Unix()}}, account.Expiring)
+		assert.Equal(t, before.Version+1, account.Version)
+		assert.Equal(t, domain.TopicsChange{Removed: topics[1:2]}, account.Changes[len(account.Changes)-1])
+
+		// nothing to remove
+		require.NoError(t, fx.AccountRepo.(*accountRepo).removeExpiredTopics(ctx, now))
+		account, err = fx.GetAccount(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, before.Version+1, account.Version)
+	})
+}
@@ -41,6 +41,34 @@ func (m *MockAccountRepo) EXPECT() *MockAccountRepoMockRecorder {
 	return m.recorder
 }
 
+// AddAccountTopics mocks base method.
+func (m *MockAccountRepo) AddAccountTopics(arg0 context.Context, arg1 string, arg2 []domain.Topic, arg3 []domain.TopicExpiry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountTopics", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAccountTopics indicates an expected call of AddAccountTopics.
+func (mr *MockAccountRepoMockRecorder) AddAccountTopics(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountTopics", reflect.TypeOf((*MockAccountRepo)(nil).AddAccountTopics), arg0, arg1, arg2, arg3)
+}
+
+// BlockAccount mocks base method.
+func (m *MockAccountRepo) BlockAccount(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockAccount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockAccount indicates an expected call of BlockAccount.
+func (mr *MockAccountRepoMockRecorder) BlockAccount(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockAccount", reflect.TypeOf((*MockAccountRepo)(nil).BlockAccount), arg0, arg1, arg2)
+}
+
 // Close mocks base method.
 func (m *MockAccountRepo) Close(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -55,6 +83,36 @@ func (mr *MockAccountRepoMockRecorder) Close(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAccountRepo)(nil).Close), arg0)
 }
 
+// GetAccount mocks base method.
+func (m *MockAccountRepo) GetAccount(arg0 context.Context, arg1 string) (domain.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(domain.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockAccountRepoMockRecorder) GetAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockAccountRepo)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountIdsBlocking mocks base method.
+func (m *MockAccountRepo) GetAccountIdsBlocking(arg0 context.Context, arg1 []string, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountIdsBlocking", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountIdsBlocking indicates an expected call of GetAccountIdsBlocking.
+func (mr *MockAccountRepoMockRecorder) GetAccountIdsBlocking(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountIdsBlocking", reflect.TypeOf((*MockAccountRepo)(nil).GetAccountIdsBlocking), arg0, arg1, arg2)
+}
+
 // GetAccountIdsByTopics mocks base method.
 func (m *MockAccountRepo) GetAccountIdsByTopics(arg0 context.Context, arg1 []domain.Topic) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -113,6 +171,62 @@ func (mr *MockAccountRepoMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockAccountRepo)(nil).Name))
 }
 
+// RemoveAccount mocks base method.
+func (m *MockAccountRepo) RemoveAccount(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAccount indicates an expected call of RemoveAccount.
+func (mr *MockAccountRepoMockRecorder) RemoveAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccount", reflect.TypeOf((*MockAccountRepo)(nil).RemoveAccount), arg0, arg1)
+}
+
+// RemoveAccountTopics mocks base method.
+func (m *MockAccountRepo) RemoveAccountTopics(arg0 context.Context, arg1 string, arg2 []domain.Topic) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAccountTopics", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAccountTopics indicates an expected call of RemoveAccountTopics.
+func (mr *MockAccountRepoMockRecorder) RemoveAccountTopics(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccountTopics", reflect.TypeOf((*MockAccountRepo)(nil).RemoveAccountTopics), arg0, arg1, arg2)
+}
+
+// RemoveSpaceTopics mocks base method.
+func (m *MockAccountRepo) RemoveSpaceTopics(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSpaceTopics", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSpaceTopics indicates an expected call of RemoveSpaceTopics.
+func (mr *MockAccountRepoMockRecorder) RemoveSpaceTopics(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSpaceTopics", reflect.TypeOf((*MockAccountRepo)(nil).RemoveSpaceTopics), arg0, arg1)
+}
+
+// RenameSpaceTopics mocks base method.
+func (m *MockAccountRepo) RenameSpaceTopics(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameSpaceTopics", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameSpaceTopics indicates an expected call of RenameSpaceTopics.
+func (mr *MockAccountRepoMockRecorder) RenameSpaceTopics(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameSpaceTopics", reflect.TypeOf((*MockAccountRepo)(nil).RenameSpaceTopics), arg0, arg1, arg2)
+}
+
 // Run mocks base method.
 func (m *MockAccountRepo) Run(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -140,3 +254,31 @@ func (mr *MockAccountRepoMockRecorder) SetAccountTopics(arg0, arg1, arg2 any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountTopics", reflect.TypeOf((*MockAccountRepo)(nil).SetAccountTopics), arg0, arg1, arg2)
 }
+
+// SetAccountTopicsIfVersion mocks base method.
+func (m *MockAccountRepo) SetAccountTopicsIfVersion(arg0 context.Context, arg1 string, arg2 []domain.Topic, arg3 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountTopicsIfVersion", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountTopicsIfVersion indicates an expected call of SetAccountTopicsIfVersion.
+func (mr *MockAccountRepoMockRecorder) SetAccountTopicsIfVersion(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountTopicsIfVersion", reflect.TypeOf((*MockAccountRepo)(nil).SetAccountTopicsIfVersion), arg0, arg1, arg2, arg3)
+}
+
+// UnblockAccount mocks base method.
+func (m *MockAccountRepo) UnblockAccount(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockAccount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockAccount indicates an expected call of UnblockAccount.
+func (mr *MockAccountRepoMockRecorder) UnblockAccount(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockAccount", reflect.TypeOf((*MockAccountRepo)(nil).UnblockAccount), arg0, arg1, arg2)
+}
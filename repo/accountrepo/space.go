@@ -0,0 +1,115 @@
+package accountrepo
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+func (r *accountRepo) RemoveSpaceTopics(ctx context.Context, spaceKey string) (err error) {
+	prefix := spaceKey + "/"
+	now := time.Now().Unix()
+	// the anchored regex uses the topics index
+	res, err := r.coll.UpdateMany(ctx,
+		bson.D{{"topics", bson.D{{"$regex", "^" + regexp.QuoteMeta(prefix)}}}},
+		bson.A{
+			bson.D{{"$set", bson.D{{"_removed", bson.D{{"$filter", bson.D{
+				{"input", "$topics"},
+				{"cond", hasPrefixExpr("$$this", prefix)},
+			}}}}}}},
+			bson.D{{"$set", bson.D{
+				{"topics", bson.D{{"$filter", bson.D{
+					{"input", "$topics"},
+					{"cond", bson.D{{"$not", bson.A{bson.D{{"$in", bson.A{"$$this", "$_removed"}}}}}}},
+				}}}},
+				{"expiring", bson.D{{"$filter", bson.D{
+					{"input", bson.D{{"$ifNull", bson.A{"$expiring", bson.A{}}}}},
+					{"cond", bson.D{{"$not", bson.A{bson.D{{"$in", bson.A{"$$this.topic", "$_removed"}}}}}}},
+				}}}},
+				{"updated", now},
+				{"version", bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$version", 0}}}, 1}}}},
+				{"changes", changesPush(bson.D{{"removed", "$_removed"}})},
+			}}},
+			bson.D{{"$unset", "_removed"}},
+		},
+	)
+	if err != nil {
+		return
+	}
+	log.Info("space topics removed", zap.String("spaceKey", spaceKey), zap.Int64("accounts", res.ModifiedCount))
+	return
+}
+
+func (r *accountRepo) RenameSpaceTopics(ctx context.Context, oldSpaceKey, newSpaceKey string) (err error) {
+	oldPrefix, newPrefix := oldSpaceKey+"/", newSpaceKey+"/"
+	now := time.Now().Unix()
+	renamed := bson.D{{"$map", bson.D{
+		{"input", "$topics"},
+		{"in", renameTopicExpr("$$this", oldPrefix, newPrefix)},
+	}}}
+	res, err := r.coll.UpdateMany(ctx,
+		bson.D{{"topics", bson.D{{"$regex", "^" + regexp.QuoteMeta(oldPrefix)}}}},
+		bson.A{
+			bson.D{{"$set", bson.D{{"_removed", bson.D{{"$filter", bson.D{
+				{"input", "$topics"},
+				{"cond", hasPrefixExpr("$$this", oldPrefix)},
+			}}}}}}},
+			bson.D{{"$set", bson.D{
+				// the account may already be subscribed to some of the new topics
+				{"topics", bson.D{{"$reduce", bson.D{
+					{"input", renamed},
+					{"initialValue", bson.A{}},
+					{"in", bson.D{{"$cond", bson.A{
+						bson.D{{"$in", bson.A{"$$this", "$$value"}}},
+						"$$value",
+						bson.D{{"$concatArrays", bson.A{"$$value", bson.A{"$$this"}}}},
+					}}}},
+				}}}},
+				{"expiring", bson.D{{"$map", bson.D{
+					{"input", bson.D{{"$ifNull", bson.A{"$expiring", bson.A{}}}}},
+					{"in", bson.D{{"$mergeObjects", bson.A{
+						"$$this",
+						bson.D{{"topic", renameTopicExpr("$$this.topic", oldPrefix, newPrefix)}},
+					}}}},
+				}}}},
+				{"updated", now},
+				{"version", bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$version", 0}}}, 1}}}},
+				{"changes", changesPush(bson.D{
+					{"added", bson.D{{"$map", bson.D{
+						{"input", "$_removed"},
+						{"in", renameTopicExpr("$$this", oldPrefix, newPrefix)},
+					}}}},
+					{"removed", "$_removed"},
+				})},
+			}}},
+			bson.D{{"$unset", "_removed"}},
+		},
+	)
+	if err != nil {
+		return
+	}
+	log.Info("space topics renamed",
+		zap.String("oldSpaceKey", oldSpaceKey),
+		zap.String("newSpaceKey", newSpaceKey),
+		zap.Int64("accounts", res.ModifiedCount),
+	)
+	return
+}
+
+func hasPrefixExpr(topic, prefix string) bson.D {
+	return bson.D{{"$eq", bson.A{bson.D{{"$indexOfCP", bson.A{topic, prefix}}}, 0}}}
+}
+
+func renameTopicExpr(topic, oldPrefix, newPrefix string) bson.D {
+	return bson.D{{"$cond", bson.A{
+		hasPrefixExpr(topic, oldPrefix),
+		bson.D{{"$concat", bson.A{
+			newPrefix,
+			bson.D{{"$substrCP", bson.A{topic, len([]rune(oldPrefix)), bson.D{{"$strLenCP", topic}}}}},
+		}}},
+		topic,
+	}}}
+}
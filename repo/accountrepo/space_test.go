@@ -0,0 +1,85 @@
+package accountrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+func TestAccountRepo_RemoveSpaceTopics(t *testing.T) {
+	fx := newFixture(t)
+	removed := newTestTopic()
+	spaceKey := removed.SpaceKeyBase58()
+	removedWildcard := removed.SpaceWildcard()
+	kept := newTestTopic()
+	expires := time.Now().Add(time.Hour).Unix()
+
+	require.NoError(t, fx.AddAccountTopics(ctx, "a", []domain.Topic{removed, kept}, []domain.TopicExpiry{
+		{Topic: removed, Expires: expires},
+		{Topic: kept, Expires: expires},
+	}))
+	require.NoError(t, fx.AddAccountTopics(ctx, "b", []domain.Topic{removedWildcard}, nil))
+	require.NoError(t, fx.AddAccountTopics(ctx, "c", []domain.Topic{kept}, nil))
+
+	require.NoError(t, fx.RemoveSpaceTopics(ctx, spaceKey))
+
+	account, err := fx.GetAccount(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Topic{kept}, account.Topics)
+	assert.Equal(t, []domain.TopicExpiry{{Topic: kept, Expires: expires}}, account.Expiring)
+	assert.Equal(t, int64(2), account.Version)
+	assert.Equal(t, domain.TopicsChange{Removed: []domain.Topic{removed}}, account.Changes[len(account.Changes)-1])
+
+	account, err = fx.GetAccount(ctx, "b")
+	require.NoError(t, err)
+	assert.Empty(t, account.Topics)
+
+	// other accounts are not touched
+	account, err = fx.GetAccount(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), account.Version)
+
+	accountIds, err := fx.GetAccountIdsByTopics(ctx, domain.WithSpaceWildcards([]domain.Topic{removed}))
+	require.NoError(t, err)
+	assert.Empty(t, accountIds)
+}
+
+func TestAccountRepo_RenameSpaceTopics(t *testing.T) {
+	fx := newFixture(t)
+	oldTopic := newTestTopic()
+	oldKey := oldTopic.SpaceKeyBase58()
+	other := newTestTopic()
+	newTopic := domain.Topic(other.SpaceKeyBase58() + "x/" + oldTopic.Topic())
+	newKey := newTopic.SpaceKeyBase58()
+	expires := time.Now().Add(time.Hour).Unix()
+
+	require.NoError(t, fx.AddAccountTopics(ctx, "a", []domain.Topic{oldTopic, other}, []domain.TopicExpiry{
+		{Topic: oldTopic, Expires: expires},
+	}))
+	// already subscribed to the new topic
+	require.NoError(t, fx.AddAccountTopics(ctx, "b", []domain.Topic{oldTopic, newTopic}, nil))
+
+	require.NoError(t, fx.RenameSpaceTopics(ctx, oldKey, newKey))
+
+	account, err := fx.GetAccount(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Topic{newTopic, other}, account.Topics)
+	assert.Equal(t, []domain.TopicExpiry{{Topic: newTopic, Expires: expires}}, account.Expiring)
+	assert.Equal(t, int64(2), account.Version)
+	assert.Equal(t, domain.TopicsChange{
+		Added:   []domain.Topic{newTopic},
+		Removed: []domain.Topic{oldTopic},
+	}, account.Changes[len(account.Changes)-1])
+
+	account, err = fx.GetAccount(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Topic{newTopic}, account.Topics)
+
+	accountIds, err := fx.GetAccountIdsByTopics(ctx, []domain.Topic{oldTopic})
+	require.NoError(t, err)
+	assert.Empty(t, accountIds)
+}
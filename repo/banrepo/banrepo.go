@@ -0,0 +1,99 @@
+//go:generate mockgen -destination mock_banrepo/mock_banrepo.go github.com/anyproto/anytype-push-server/repo/banrepo BanRepo
+
+package banrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/anyproto/anytype-push-server/db"
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+var ErrBanNotFound = errors.New("ban not found")
+
+const CName = "push.banrepo"
+
+const collName = "ban"
+
+func New() BanRepo {
+	return new(banRepo)
+}
+
+type BanRepo interface {
+	// Ban adds the account to the ban list or updates the reason of the existing ban
+	Ban(ctx context.Context, ban domain.Ban) (err error)
+	Unban(ctx context.Context, accountId string) (err error)
+	IsBanned(ctx context.Context, accountId string) (banned bool, err error)
+	List(ctx context.Context) (bans []domain.Ban, err error)
+	app.ComponentRunnable
+}
+
+type banRepo struct {
+	coll *mongo.Collection
+}
+
+func (r *banRepo) Init(a *app.App) (err error) {
+	r.coll = a.MustComponent(db.CName).(db.Database).Db().Collection(collName)
+	return
+}
+
+func (r *banRepo) Name() (name string) {
+	return CName
+}
+
+func (r *banRepo) Run(ctx context.Context) error {
+	return nil
+}
+
+func (r *banRepo) Ban(ctx context.Context, ban domain.Ban) (err error) {
+	_, err = r.coll.UpdateByID(ctx, ban.AccountId, bson.D{
+		{"$set", bson.D{{"reason", ban.Reason}}},
+		{"$setOnInsert", bson.D{{"created", time.Now().Unix()}}},
+	}, options.Update().SetUpsert(true))
+	return
+}
+
+func (r *banRepo) Unban(ctx context.Context, accountId string) (err error) {
+	res, err := r.coll.DeleteOne(ctx, bson.D{{"_id", accountId}})
+	if err != nil {
+		return
+	}
+	if res.DeletedCount == 0 {
+		return ErrBanNotFound
+	}
+	return
+}
+
+func (r *banRepo) IsBanned(ctx context.Context, accountId string) (banned bool, err error) {
+	err = r.coll.FindOne(ctx, bson.D{{"_id", accountId}}, options.FindOne().SetProjection(bson.D{{"_id", 1}})).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return
+	}
+	return true, nil
+}
+
+func (r *banRepo) List(ctx context.Context) (bans []domain.Ban, err error) {
+	cursor, err := r.coll.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"created", 1}}))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+	err = cursor.All(ctx, &bans)
+	return
+}
+
+func (r *banRepo) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,92 @@
+package banrepo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/db"
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+var ctx = context.Background()
+
+func TestBanRepo_Ban(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.Ban(ctx, domain.Ban{AccountId: "a", Reason: "spam"}))
+	require.NoError(t, fx.Ban(ctx, domain.Ban{AccountId: "a", Reason: "abuse"}))
+
+	banned, err := fx.IsBanned(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, banned)
+
+	banned, err = fx.IsBanned(ctx, "b")
+	require.NoError(t, err)
+	assert.False(t, banned)
+
+	bans, err := fx.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	assert.Equal(t, "abuse", bans[0].Reason)
+	assert.NotZero(t, bans[0].Created)
+}
+
+func TestBanRepo_Unban(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.Ban(ctx, domain.Ban{AccountId: "a"}))
+	require.NoError(t, fx.Unban(ctx, "a"))
+	require.ErrorIs(t, fx.Unban(ctx, "a"), ErrBanNotFound)
+
+	banned, err := fx.IsBanned(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, banned)
+}
+
+func newFixture(t testing.TB) *fixture {
+	fx := &fixture{
+		BanRepo: New(),
+		a:       new(app.App),
+	}
+	fx.a.Register(&testConfig{
+		Mongo: db.Mongo{
+			Connect:  "mongodb://localhost:27017",
+			Database: "publish_unittest",
+		},
+	}).
+		Register(db.New()).
+		Register(fx.BanRepo)
+	require.NoError(t, fx.a.Start(ctx))
+	t.Cleanup(func() {
+		fx.finish(t)
+	})
+	return fx
+}
+
+type fixture struct {
+	BanRepo
+	a *app.App
+}
+
+func (fx *fixture) finish(t testing.TB) {
+	_ = fx.BanRepo.(*banRepo).coll.Drop(ctx)
+	require.NoError(t, fx.a.Close(ctx))
+}
+
+type testConfig struct {
+	Mongo db.Mongo
+}
+
+func (t testConfig) Init(a *app.App) (err error) {
+	return
+}
+
+func (t testConfig) Name() (name string) {
+	return "config"
+}
+
+func (t testConfig) GetMongo() db.Mongo {
+	return t.Mongo
+}
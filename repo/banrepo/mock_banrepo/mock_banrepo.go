@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/anyproto/anytype-push-server/repo/banrepo (interfaces: BanRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock_banrepo/mock_banrepo.go github.com/anyproto/anytype-push-server/repo/banrepo BanRepo
+//
+
+// Package mock_banrepo is a generated GoMock package.
+package mock_banrepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	app "github.com/anyproto/any-sync/app"
+	domain "github.com/anyproto/anytype-push-server/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBanRepo is a mock of BanRepo interface.
+type MockBanRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBanRepoMockRecorder
+}
+
+// MockBanRepoMockRecorder is the mock recorder for MockBanRepo.
+type MockBanRepoMockRecorder struct {
+	mock *MockBanRepo
+}
+
+// NewMockBanRepo creates a new mock instance.
+func NewMockBanRepo(ctrl *gomock.Controller) *MockBanRepo {
+	mock := &MockBanRepo{ctrl: ctrl}
+	mock.recorder = &MockBanRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBanRepo) EXPECT() *MockBanRepoMockRecorder {
+	return m.recorder
+}
+
+// Ban mocks base method.
+func (m *MockBanRepo) Ban(arg0 context.Context, arg1 domain.Ban) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ban", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ban indicates an expected call of Ban.
+func (mr *MockBanRepoMockRecorder) Ban(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ban", reflect.TypeOf((*MockBanRepo)(nil).Ban), arg0, arg1)
+}
+
+// Close mocks base method.
+func (m *MockBanRepo) Close(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockBanRepoMockRecorder) Close(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBanRepo)(nil).Close), arg0)
+}
+
+// Init mocks base method.
+func (m *MockBanRepo) Init(arg0 *app.App) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockBanRepoMockRecorder) Init(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockBanRepo)(nil).Init), arg0)
+}
+
+// IsBanned mocks base method.
+func (m *MockBanRepo) IsBanned(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBanned", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBanned indicates an expected call of IsBanned.
+func (mr *MockBanRepoMockRecorder) IsBanned(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBanned", reflect.TypeOf((*MockBanRepo)(nil).IsBanned), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockBanRepo) List(arg0 context.Context) ([]domain.Ban, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]domain.Ban)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBanRepoMockRecorder) List(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBanRepo)(nil).List), arg0)
+}
+
+// Name mocks base method.
+func (m *MockBanRepo) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockBanRepoMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockBanRepo)(nil).Name))
+}
+
+// Run mocks base method.
+func (m *MockBanRepo) Run(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockBanRepoMockRecorder) Run(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockBanRepo)(nil).Run), arg0)
+}
+
+// Unban mocks base method.
+func (m *MockBanRepo) Unban(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unban", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unban indicates an expected call of Unban.
+func (mr *MockBanRepoMockRecorder) Unban(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unban", reflect.TypeOf((*MockBanRepo)(nil).Unban), arg0, arg1)
+}
@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/anyproto/anytype-push-server/repo/reportrepo (interfaces: ReportRepo)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock_reportrepo/mock_reportrepo.go github.com/anyproto/anytype-push-server/repo/reportrepo ReportRepo
+//
+
+// Package mock_reportrepo is a generated GoMock package.
+package mock_reportrepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	app "github.com/anyproto/any-sync/app"
+	domain "github.com/anyproto/anytype-push-server/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReportRepo is a mock of ReportRepo interface.
+type MockReportRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRepoMockRecorder
+}
+
+// MockReportRepoMockRecorder is the mock recorder for MockReportRepo.
+type MockReportRepoMockRecorder struct {
+	mock *MockReportRepo
+}
+
+// NewMockReportRepo creates a new mock instance.
+func NewMockReportRepo(ctrl *gomock.Controller) *MockReportRepo {
+	mock := &MockReportRepo{ctrl: ctrl}
+	mock.recorder = &MockReportRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportRepo) EXPECT() *MockReportRepoMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockReportRepo) Add(arg0 context.Context, arg1 domain.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockReportRepoMockRecorder) Add(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockReportRepo)(nil).Add), arg0, arg1)
+}
+
+// Close mocks base method.
+func (m *MockReportRepo) Close(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockReportRepoMockRecorder) Close(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockReportRepo)(nil).Close), arg0)
+}
+
+// GetByGroupId mocks base method.
+func (m *MockReportRepo) GetByGroupId(arg0 context.Context, arg1 string) ([]domain.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByGroupId", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByGroupId indicates an expected call of GetByGroupId.
+func (mr *MockReportRepoMockRecorder) GetByGroupId(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByGroupId", reflect.TypeOf((*MockReportRepo)(nil).GetByGroupId), arg0, arg1)
+}
+
+// GetByReporterId mocks base method.
+func (m *MockReportRepo) GetByReporterId(arg0 context.Context, arg1 string) ([]domain.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByReporterId", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByReporterId indicates an expected call of GetByReporterId.
+func (mr *MockReportRepoMockRecorder) GetByReporterId(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByReporterId", reflect.TypeOf((*MockReportRepo)(nil).GetByReporterId), arg0, arg1)
+}
+
+// GetBySenderId mocks base method.
+func (m *MockReportRepo) GetBySenderId(arg0 context.Context, arg1 string) ([]domain.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySenderId", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySenderId indicates an expected call of GetBySenderId.
+func (mr *MockReportRepoMockRecorder) GetBySenderId(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySenderId", reflect.TypeOf((*MockReportRepo)(nil).GetBySenderId), arg0, arg1)
+}
+
+// Init mocks base method.
+func (m *MockReportRepo) Init(arg0 *app.App) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockReportRepoMockRecorder) Init(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockReportRepo)(nil).Init), arg0)
+}
+
+// Name mocks base method.
+func (m *MockReportRepo) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockReportRepoMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockReportRepo)(nil).Name))
+}
+
+// RemoveByReporterId mocks base method.
+func (m *MockReportRepo) RemoveByReporterId(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveByReporterId", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveByReporterId indicates an expected call of RemoveByReporterId.
+func (mr *MockReportRepoMockRecorder) RemoveByReporterId(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveByReporterId", reflect.TypeOf((*MockReportRepo)(nil).RemoveByReporterId), arg0, arg1)
+}
+
+// Run mocks base method.
+func (m *MockReportRepo) Run(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockReportRepoMockRecorder) Run(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockReportRepo)(nil).Run), arg0)
+}
@@ -0,0 +1,102 @@
+//go:generate mockgen -destination mock_reportrepo/mock_reportrepo.go github.com/anyproto/anytype-push-server/repo/reportrepo ReportRepo
+
+package reportrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/anyproto/anytype-push-server/db"
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+const CName = "push.reportrepo"
+
+const collName = "report"
+
+func New() ReportRepo {
+	return new(reportRepo)
+}
+
+type ReportRepo interface {
+	// Add stores the report, a repeated report of the same notification by the same account is ignored
+	Add(ctx context.Context, report domain.Report) (err error)
+	GetBySenderId(ctx context.Context, senderId string) (reports []domain.Report, err error)
+	GetByGroupId(ctx context.Context, groupId string) (reports []domain.Report, err error)
+	GetByReporterId(ctx context.Context, reporterId string) (reports []domain.Report, err error)
+	// RemoveByReporterId removes all reports made by the account
+	RemoveByReporterId(ctx context.Context, reporterId string) (err error)
+	app.ComponentRunnable
+}
+
+type reportRepo struct {
+	coll *mongo.Collection
+}
+
+func (r *reportRepo) Init(a *app.App) (err error) {
+	r.coll = a.MustComponent(db.CName).(db.Database).Db().Collection(collName)
+	return
+}
+
+func (r *reportRepo) Name() (name string) {
+	return CName
+}
+
+func (r *reportRepo) Run(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"reporterId", 1}, {"groupId", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{"groupId", 1}}},
+		{Keys: bson.D{{"senderId", 1}}},
+	})
+	return err
+}
+
+func (r *reportRepo) Add(ctx context.Context, report domain.Report) (err error) {
+	report.Created = time.Now().Unix()
+	_, err = r.coll.InsertOne(ctx, report)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return
+}
+
+func (r *reportRepo) GetBySenderId(ctx context.Context, senderId string) (reports []domain.Report, err error) {
+	return r.find(ctx, bson.D{{"senderId", senderId}})
+}
+
+func (r *reportRepo) GetByGroupId(ctx context.Context, groupId string) (reports []domain.Report, err error) {
+	return r.find(ctx, bson.D{{"groupId", groupId}})
+}
+
+func (r *reportRepo) GetByReporterId(ctx context.Context, reporterId string) (reports []domain.Report, err error) {
+	return r.find(ctx, bson.D{{"reporterId", reporterId}})
+}
+
+func (r *reportRepo) RemoveByReporterId(ctx context.Context, reporterId string) (err error) {
+	_, err = r.coll.DeleteMany(ctx, bson.D{{"reporterId", reporterId}})
+	return
+}
+
+func (r *reportRepo) find(ctx context.Context, filter bson.D) (reports []domain.Report, err error) {
+	cursor, err := r.coll.Find(ctx, filter, options.Find().SetSort(bson.D{{"created", 1}}))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+	err = cursor.All(ctx, &reports)
+	return
+}
+
+func (r *reportRepo) Close(ctx context.Context) error {
+	return nil
+}
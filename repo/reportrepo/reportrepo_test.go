@@ -0,0 +1,100 @@
+package reportrepo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/db"
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+var ctx = context.Background()
+
+func TestReportRepo_Add(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.Add(ctx, domain.Report{ReporterId: "r1", GroupId: "g1", SenderId: "s", Reason: "spam"}))
+	// repeated report
+	require.NoError(t, fx.Add(ctx, domain.Report{ReporterId: "r1", GroupId: "g1", SenderId: "s", Reason: "spam"}))
+	require.NoError(t, fx.Add(ctx, domain.Report{ReporterId: "r2", GroupId: "g1"}))
+	require.NoError(t, fx.Add(ctx, domain.Report{ReporterId: "r1", GroupId: "g2", SenderId: "s"}))
+
+	reports, err := fx.GetByGroupId(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, reports, 2)
+
+	reports, err = fx.GetBySenderId(ctx, "s")
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "r1", reports[0].ReporterId)
+	assert.NotZero(t, reports[0].Created)
+}
+
+func TestReportRepo_RemoveByReporterId(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.Add(ctx, domain.Report{ReporterId: "r1", GroupId: "g1"}))
+	require.NoError(t, fx.Add(ctx, domain.Report{ReporterId: "r1", GroupId: "g2"}))
+	require.NoError(t, fx.Add(ctx, domain.Report{ReporterId: "r2", GroupId: "g1"}))
+
+	reports, err := fx.GetByReporterId(ctx, "r1")
+	require.NoError(t, err)
+	assert.Len(t, reports, 2)
+
+	require.NoError(t, fx.RemoveByReporterId(ctx, "r1"))
+	reports, err = fx.GetByReporterId(ctx, "r1")
+	require.NoError(t, err)
+	assert.Len(t, reports, 0)
+	reports, err = fx.GetByGroupId(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "r2", reports[0].ReporterId)
+}
+
+func newFixture(t testing.TB) *fixture {
+	fx := &fixture{
+		ReportRepo: New(),
+		a:          new(app.App),
+	}
+	fx.a.Register(&testConfig{
+		Mongo: db.Mongo{
+			Connect:  "mongodb://localhost:27017",
+			Database: "publish_unittest",
+		},
+	}).
+		Register(db.New()).
+		Register(fx.ReportRepo)
+	require.NoError(t, fx.a.Start(ctx))
+	t.Cleanup(func() {
+		fx.finish(t)
+	})
+	return fx
+}
+
+type fixture struct {
+	ReportRepo
+	a *app.App
+}
+
+func (fx *fixture) finish(t testing.TB) {
+	_ = fx.ReportRepo.(*reportRepo).coll.Drop(ctx)
+	require.NoError(t, fx.a.Close(ctx))
+}
+
+type testConfig struct {
+	Mongo db.Mongo
+}
+
+func (t testConfig) Init(a *app.App) (err error) {
+	return
+}
+
+func (t testConfig) Name() (name string) {
+	return "config"
+}
+
+func (t testConfig) GetMongo() db.Mongo {
+	return t.Mongo
+}
@@ -12,6 +12,7 @@ package mock_spacerepo
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	app "github.com/anyproto/any-sync/app"
 	domain "github.com/anyproto/anytype-push-server/domain"
@@ -41,6 +42,34 @@ func (m *MockSpaceRepo) EXPECT() *MockSpaceRepoMockRecorder {
 	return m.recorder
 }
 
+// AddAdmin mocks base method.
+func (m *MockSpaceRepo) AddAdmin(arg0 context.Context, arg1 domain.Space, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAdmin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAdmin indicates an expected call of AddAdmin.
+func (mr *MockSpaceRepoMockRecorder) AddAdmin(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdmin", reflect.TypeOf((*MockSpaceRepo)(nil).AddAdmin), arg0, arg1, arg2)
+}
+
+// AddOneToOneMember mocks base method.
+func (m *MockSpaceRepo) AddOneToOneMember(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOneToOneMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOneToOneMember indicates an expected call of AddOneToOneMember.
+func (mr *MockSpaceRepoMockRecorder) AddOneToOneMember(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOneToOneMember", reflect.TypeOf((*MockSpaceRepo)(nil).AddOneToOneMember), arg0, arg1, arg2)
+}
+
 // Close mocks base method.
 func (m *MockSpaceRepo) Close(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -84,6 +113,51 @@ func (mr *MockSpaceRepoMockRecorder) ExistedSpaces(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistedSpaces", reflect.TypeOf((*MockSpaceRepo)(nil).ExistedSpaces), arg0, arg1)
 }
 
+// Get mocks base method.
+func (m *MockSpaceRepo) Get(arg0 context.Context, arg1 string) (domain.Space, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(domain.Space)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSpaceRepoMockRecorder) Get(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSpaceRepo)(nil).Get), arg0, arg1)
+}
+
+// GetByAccount mocks base method.
+func (m *MockSpaceRepo) GetByAccount(arg0 context.Context, arg1 string) ([]domain.Space, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAccount", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Space)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAccount indicates an expected call of GetByAccount.
+func (mr *MockSpaceRepoMockRecorder) GetByAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAccount", reflect.TypeOf((*MockSpaceRepo)(nil).GetByAccount), arg0, arg1)
+}
+
+// GetPolicies mocks base method.
+func (m *MockSpaceRepo) GetPolicies(arg0 context.Context, arg1 []string) (map[string]domain.SpacePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicies", arg0, arg1)
+	ret0, _ := ret[0].(map[string]domain.SpacePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicies indicates an expected call of GetPolicies.
+func (mr *MockSpaceRepoMockRecorder) GetPolicies(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicies", reflect.TypeOf((*MockSpaceRepo)(nil).GetPolicies), arg0, arg1)
+}
+
 // Init mocks base method.
 func (m *MockSpaceRepo) Init(arg0 *app.App) error {
 	m.ctrl.T.Helper()
@@ -112,6 +186,20 @@ func (mr *MockSpaceRepoMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockSpaceRepo)(nil).Name))
 }
 
+// Rekey mocks base method.
+func (m *MockSpaceRepo) Rekey(arg0 context.Context, arg1 domain.Space, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rekey", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rekey indicates an expected call of Rekey.
+func (mr *MockSpaceRepoMockRecorder) Rekey(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rekey", reflect.TypeOf((*MockSpaceRepo)(nil).Rekey), arg0, arg1, arg2)
+}
+
 // Remove mocks base method.
 func (m *MockSpaceRepo) Remove(arg0 context.Context, arg1 domain.Space) error {
 	m.ctrl.T.Helper()
@@ -126,6 +214,50 @@ func (mr *MockSpaceRepoMockRecorder) Remove(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockSpaceRepo)(nil).Remove), arg0, arg1)
 }
 
+// RemoveAccount mocks base method.
+func (m *MockSpaceRepo) RemoveAccount(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAccount", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveAccount indicates an expected call of RemoveAccount.
+func (mr *MockSpaceRepoMockRecorder) RemoveAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccount", reflect.TypeOf((*MockSpaceRepo)(nil).RemoveAccount), arg0, arg1)
+}
+
+// RemoveAdmin mocks base method.
+func (m *MockSpaceRepo) RemoveAdmin(arg0 context.Context, arg1 domain.Space, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAdmin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAdmin indicates an expected call of RemoveAdmin.
+func (mr *MockSpaceRepoMockRecorder) RemoveAdmin(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAdmin", reflect.TypeOf((*MockSpaceRepo)(nil).RemoveAdmin), arg0, arg1, arg2)
+}
+
+// RemovedSince mocks base method.
+func (m *MockSpaceRepo) RemovedSince(arg0 context.Context, arg1 []string, arg2 time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovedSince", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemovedSince indicates an expected call of RemovedSince.
+func (mr *MockSpaceRepoMockRecorder) RemovedSince(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovedSince", reflect.TypeOf((*MockSpaceRepo)(nil).RemovedSince), arg0, arg1, arg2)
+}
+
 // Run mocks base method.
 func (m *MockSpaceRepo) Run(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -139,3 +271,31 @@ func (mr *MockSpaceRepoMockRecorder) Run(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockSpaceRepo)(nil).Run), arg0)
 }
+
+// SetPolicy mocks base method.
+func (m *MockSpaceRepo) SetPolicy(arg0 context.Context, arg1 domain.Space, arg2 domain.SpacePolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPolicy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPolicy indicates an expected call of SetPolicy.
+func (mr *MockSpaceRepoMockRecorder) SetPolicy(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPolicy", reflect.TypeOf((*MockSpaceRepo)(nil).SetPolicy), arg0, arg1, arg2)
+}
+
+// Transfer mocks base method.
+func (m *MockSpaceRepo) Transfer(arg0 context.Context, arg1 domain.Space, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockSpaceRepoMockRecorder) Transfer(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockSpaceRepo)(nil).Transfer), arg0, arg1, arg2)
+}
@@ -5,6 +5,8 @@ package spacerepo
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/anyproto/any-sync/app"
@@ -17,13 +19,21 @@ import (
 )
 
 var (
-	ErrSpaceExists   = errors.New("space already exists")
-	ErrSpaceNotFound = errors.New("space not found")
+	ErrSpaceExists    = errors.New("space already exists")
+	ErrSpaceNotFound  = errors.New("space not found")
+	ErrNotSpaceAdmin  = errors.New("not a space admin")
+	ErrLastSpaceAdmin = errors.New("last space admin")
 )
 
 const CName = "push.spacerepo"
 
-const collName = "space"
+const (
+	collName        = "space"
+	removedCollName = "removedSpace"
+)
+
+// removedTTL is how long removals are remembered to drop messages queued before the removal
+const removedTTL = 24 * time.Hour
 
 func New() SpaceRepo {
 	return new(spaceRepo)
@@ -31,17 +41,40 @@ func New() SpaceRepo {
 
 type SpaceRepo interface {
 	Create(ctx context.Context, space domain.Space) (err error)
+	// AddOneToOneMember registers the account in a 1-1 space creating it if needed, a space has at most two members
+	AddOneToOneMember(ctx context.Context, spaceId, accountId string) (err error)
 	Remove(ctx context.Context, space domain.Space) (err error)
+	// Rekey replaces the id of the space, only an admin may do this
+	Rekey(ctx context.Context, space domain.Space, newId string) (err error)
+	// Transfer makes the account the author and an admin of the space, space.Author must be an admin
+	Transfer(ctx context.Context, space domain.Space, accountId string) (err error)
+	AddAdmin(ctx context.Context, space domain.Space, accountId string) (err error)
+	RemoveAdmin(ctx context.Context, space domain.Space, accountId string) (err error)
+	Get(ctx context.Context, spaceId string) (space domain.Space, err error)
+	// SetPolicy replaces the policy of the space, space.Author must be an admin
+	SetPolicy(ctx context.Context, space domain.Space, policy domain.SpacePolicy) (err error)
+	// GetPolicies returns policies of the registered spaces from the list
+	GetPolicies(ctx context.Context, spaceIds []string) (policies map[string]domain.SpacePolicy, err error)
+	// RemoveAccount drops the account from admins and members of its spaces, the authorship passes to a remaining admin or member.
+	// Spaces left without admins or members are removed, their ids are returned.
+	RemoveAccount(ctx context.Context, accountId string) (removedIds []string, err error)
+	// GetByAccount returns spaces the account is the author, an admin or a member of
+	GetByAccount(ctx context.Context, accountId string) (spaces []domain.Space, err error)
 	ExistedSpaces(ctx context.Context, spaceIds []string) (existedIds []string, err error)
+	// RemovedSince returns spaces from the list removed after the given time
+	RemovedSince(ctx context.Context, spaceIds []string, since time.Time) (removedIds []string, err error)
 	app.ComponentRunnable
 }
 
 type spaceRepo struct {
-	coll *mongo.Collection
+	coll        *mongo.Collection
+	removedColl *mongo.Collection
 }
 
 func (r *spaceRepo) Init(a *app.App) (err error) {
-	r.coll = a.MustComponent(db.CName).(db.Database).Db().Collection(collName)
+	database := a.MustComponent(db.CName).(db.Database).Db()
+	r.coll = database.Collection(collName)
+	r.removedColl = database.Collection(removedCollName)
 	return
 }
 
@@ -50,11 +83,16 @@ func (r *spaceRepo) Name() (name string) {
 }
 
 func (r *spaceRepo) Run(ctx context.Context) error {
-	return nil
+	_, err := r.removedColl.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"removed", 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(removedTTL.Seconds())),
+	})
+	return err
 }
 
 func (r *spaceRepo) Create(ctx context.Context, space domain.Space) (err error) {
 	space.Created = time.Now().Unix()
+	space.Admins = []string{space.Author}
 	_, err = r.coll.InsertOne(ctx, space)
 	if mongo.IsDuplicateKeyError(err) {
 		err = ErrSpaceExists
@@ -62,25 +100,231 @@ func (r *spaceRepo) Create(ctx context.Context, space domain.Space) (err error)
 	return
 }
 
+const maxOneToOneMembers = 2
+
+func (r *spaceRepo) AddOneToOneMember(ctx context.Context, spaceId, accountId string) (err error) {
+	filter := bson.D{
+		{"_id", spaceId},
+		{"type", domain.SpaceTypeOneToOne},
+		{"$or", bson.A{
+			bson.D{{"members", accountId}},
+			bson.D{{fmt.Sprintf("members.%d", maxOneToOneMembers-1), bson.D{{"$exists", false}}}},
+		}},
+	}
+	update := bson.D{
+		{"$addToSet", bson.D{{"members", accountId}}},
+		{"$setOnInsert", bson.D{
+			{"author", accountId},
+			{"created", time.Now().Unix()},
+		}},
+	}
+	// the upsert conflicts with a regular space or a full 1-1 space
+	_, err = r.coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		err = ErrSpaceExists
+	}
+	return
+}
+
 func (r *spaceRepo) Remove(ctx context.Context, space domain.Space) (err error) {
-	res, err := r.coll.DeleteOne(ctx, bson.D{{"_id", space.Id}, {"author", space.Author}})
+	// any member may remove a 1-1 space
+	res, err := r.coll.DeleteOne(ctx, bson.D{
+		{"_id", space.Id},
+		{"$or", append(adminFilter(space.Author), bson.D{{"members", space.Author}})},
+	})
 	if err != nil {
 		return
 	}
 	if res.DeletedCount == 0 {
-		return ErrSpaceNotFound
+		return r.notAdminErr(ctx, space.Id)
+	}
+	return r.markRemoved(ctx, space.Id)
+}
+
+func (r *spaceRepo) markRemoved(ctx context.Context, spaceId string) (err error) {
+	_, err = r.removedColl.UpdateByID(ctx, spaceId,
+		bson.D{{"$set", bson.D{{"removed", time.Now()}}}},
+		options.Update().SetUpsert(true),
+	)
+	return
+}
+
+// adminFilter matches spaces managed by the account, spaces without admins are managed by the author
+func adminFilter(accountId string) bson.A {
+	return bson.A{
+		bson.D{{"admins", accountId}},
+		bson.D{{"admins", bson.D{{"$exists", false}}}, {"author", accountId}},
+	}
+}
+
+// adminsExpr is the aggregation expression of the space admins
+var adminsExpr = bson.D{{"$ifNull", bson.A{"$admins", bson.A{"$author"}}}}
+
+func addAdminExpr(accountId string) bson.D {
+	return bson.D{{"$cond", bson.A{
+		bson.D{{"$in", bson.A{accountId, adminsExpr}}},
+		adminsExpr,
+		bson.D{{"$concatArrays", bson.A{adminsExpr, bson.A{accountId}}}},
+	}}}
+}
+
+// notAdminErr tells apart a missing space from a space managed by other accounts
+func (r *spaceRepo) notAdminErr(ctx context.Context, spaceId string) error {
+	if _, err := r.Get(ctx, spaceId); err != nil {
+		return err
+	}
+	return ErrNotSpaceAdmin
+}
+
+func (r *spaceRepo) Get(ctx context.Context, spaceId string) (space domain.Space, err error) {
+	err = r.coll.FindOne(ctx, bson.D{{"_id", spaceId}}).Decode(&space)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = ErrSpaceNotFound
+	}
+	return
+}
+
+func (r *spaceRepo) Rekey(ctx context.Context, space domain.Space, newId string) (err error) {
+	existing, err := r.Get(ctx, space.Id)
+	if err != nil {
+		return
+	}
+	if !existing.IsAdmin(space.Author) {
+		return ErrNotSpaceAdmin
+	}
+	// _id is immutable, so the space is moved to a new document
+	existing.Id = newId
+	if _, err = r.coll.InsertOne(ctx, existing); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			err = ErrSpaceExists
+		}
+		return
+	}
+	_, err = r.coll.DeleteOne(ctx, bson.D{{"_id", space.Id}})
+	return
+}
+
+func (r *spaceRepo) Transfer(ctx context.Context, space domain.Space, accountId string) (err error) {
+	return r.updateAsAdmin(ctx, space, bson.D{
+		{"author", accountId},
+		{"admins", addAdminExpr(accountId)},
+	})
+}
+
+func (r *spaceRepo) AddAdmin(ctx context.Context, space domain.Space, accountId string) (err error) {
+	return r.updateAsAdmin(ctx, space, bson.D{{"admins", addAdminExpr(accountId)}})
+}
+
+func (r *spaceRepo) RemoveAdmin(ctx context.Context, space domain.Space, accountId string) (err error) {
+	res, err := r.coll.UpdateOne(ctx,
+		bson.D{
+			{"_id", space.Id},
+			{"$or", adminFilter(space.Author)},
+			// the space keeps at least one admin
+			{"$expr", bson.D{{"$gt", bson.A{
+				bson.D{{"$size", bson.D{{"$setDifference", bson.A{adminsExpr, bson.A{accountId}}}}}},
+				0,
+			}}}},
+		},
+		bson.A{bson.D{{"$set", bson.D{{"admins", bson.D{{"$filter", bson.D{
+			{"input", adminsExpr},
+			{"cond", bson.D{{"$ne", bson.A{"$$this", accountId}}}},
+		}}}}}}}},
+	)
+	if err != nil {
+		return
+	}
+	if res.MatchedCount == 0 {
+		existing, err := r.Get(ctx, space.Id)
+		if err != nil {
+			return err
+		}
+		if !existing.IsAdmin(space.Author) {
+			return ErrNotSpaceAdmin
+		}
+		return ErrLastSpaceAdmin
 	}
 	return
 }
 
+func (r *spaceRepo) SetPolicy(ctx context.Context, space domain.Space, policy domain.SpacePolicy) (err error) {
+	return r.updateAsAdmin(ctx, space, bson.D{{"policy", bson.D{{"$literal", policy}}}})
+}
+
+func (r *spaceRepo) updateAsAdmin(ctx context.Context, space domain.Space, set bson.D) (err error) {
+	res, err := r.coll.UpdateOne(ctx,
+		bson.D{{"_id", space.Id}, {"$or", adminFilter(space.Author)}},
+		bson.A{bson.D{{"$set", set}}},
+	)
+	if err != nil {
+		return
+	}
+	if res.MatchedCount == 0 {
+		return r.notAdminErr(ctx, space.Id)
+	}
+	return
+}
+
+func (r *spaceRepo) RemoveAccount(ctx context.Context, accountId string) (removedIds []string, err error) {
+	spaces, err := r.GetByAccount(ctx, accountId)
+	if err != nil {
+		return
+	}
+	for _, space := range spaces {
+		var set bson.D
+		var remaining []string
+		if space.Type == domain.SpaceTypeOneToOne {
+			remaining = slices.DeleteFunc(space.Members, func(id string) bool { return id == accountId })
+			set = bson.D{{"members", remaining}}
+		} else {
+			admins := space.Admins
+			if admins == nil {
+				admins = []string{space.Author}
+			}
+			remaining = slices.DeleteFunc(admins, func(id string) bool { return id == accountId })
+			set = bson.D{{"admins", remaining}}
+		}
+		if len(remaining) == 0 {
+			if _, err = r.coll.DeleteOne(ctx, bson.D{{"_id", space.Id}}); err != nil {
+				return
+			}
+			if err = r.markRemoved(ctx, space.Id); err != nil {
+				return
+			}
+			removedIds = append(removedIds, space.Id)
+			continue
+		}
+		if space.Author == accountId {
+			set = append(set, bson.E{Key: "author", Value: remaining[0]})
+		}
+		if _, err = r.coll.UpdateByID(ctx, space.Id, bson.D{{"$set", set}}); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (r *spaceRepo) GetByAccount(ctx context.Context, accountId string) (spaces []domain.Space, err error) {
+	cursor, err := r.coll.Find(ctx, bson.D{{"$or", bson.A{
+		bson.D{{"author", accountId}},
+		bson.D{{"admins", accountId}},
+		bson.D{{"members", accountId}},
+	}}})
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+	err = cursor.All(ctx, &spaces)
+	return
+}
+
 type doc struct {
 	Id string `bson:"_id"`
 }
 
 func (r *spaceRepo) ExistedSpaces(ctx context.Context, spaceIds []string) (existedIds []string, err error) {
-	// TODO: we're temporary skip this check because 1-1 spaces not able to register spaces yet
-	return spaceIds, nil
-
 	cursor, err := r.coll.Find(
 		ctx,
 		bson.D{{"_id", bson.D{{"$in", spaceIds}}}},
@@ -102,6 +346,55 @@ func (r *spaceRepo) ExistedSpaces(ctx context.Context, spaceIds []string) (exist
 	return
 }
 
+func (r *spaceRepo) GetPolicies(ctx context.Context, spaceIds []string) (policies map[string]domain.SpacePolicy, err error) {
+	cursor, err := r.coll.Find(
+		ctx,
+		bson.D{{"_id", bson.D{{"$in", spaceIds}}}},
+		options.Find().SetProjection(bson.D{{"policy", 1}}),
+	)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+	policies = make(map[string]domain.SpacePolicy)
+	var space domain.Space
+	for cursor.Next(ctx) {
+		space = domain.Space{}
+		if err = cursor.Decode(&space); err != nil {
+			return
+		}
+		policies[space.Id] = space.Policy
+	}
+	return
+}
+
+func (r *spaceRepo) RemovedSince(ctx context.Context, spaceIds []string, since time.Time) (removedIds []string, err error) {
+	cursor, err := r.removedColl.Find(
+		ctx,
+		bson.D{
+			{"_id", bson.D{{"$in", spaceIds}}},
+			{"removed", bson.D{{"$gt", since}}},
+		},
+		options.Find().SetProjection(bson.D{{"_id", 1}}),
+	)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+	var d doc
+	for cursor.Next(ctx) {
+		if err = cursor.Decode(&d); err != nil {
+			return
+		}
+		removedIds = append(removedIds, d.Id)
+	}
+	return
+}
+
 func (r *spaceRepo) Close(ctx context.Context) error {
 	return nil
 }
@@ -3,6 +3,7 @@ package spacerepo
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/anyproto/any-sync/app"
 	"github.com/stretchr/testify/require"
@@ -26,7 +27,6 @@ func TestSpaceRepo_Create(t *testing.T) {
 }
 
 func TestSpaceRepo_ExistedSpaces(t *testing.T) {
-	t.Skip()
 	fx := newFixture(t)
 	require.NoError(t, fx.Create(ctx, domain.Space{
 		Id:     "1",
@@ -41,12 +41,41 @@ func TestSpaceRepo_ExistedSpaces(t *testing.T) {
 	require.Equal(t, []string{"1", "2"}, result)
 }
 
+func TestSpaceRepo_AddOneToOneMember(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.AddOneToOneMember(ctx, "1", "a"))
+	require.NoError(t, fx.AddOneToOneMember(ctx, "1", "b"))
+	// repeated registration
+	require.NoError(t, fx.AddOneToOneMember(ctx, "1", "a"))
+	// the space is full
+	require.ErrorIs(t, fx.AddOneToOneMember(ctx, "1", "c"), ErrSpaceExists)
+
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "2", Author: "a"}))
+	require.ErrorIs(t, fx.AddOneToOneMember(ctx, "2", "b"), ErrSpaceExists)
+
+	result, err := fx.ExistedSpaces(ctx, []string{"1", "2", "3"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"1", "2"}, result)
+
+	spaces, err := fx.GetByAccount(ctx, "a")
+	require.NoError(t, err)
+	require.Len(t, spaces, 2)
+
+	// any member removes the 1-1 space
+	require.NoError(t, fx.Remove(ctx, domain.Space{Id: "1", Author: "b"}))
+	require.ErrorIs(t, fx.Remove(ctx, domain.Space{Id: "2", Author: "b"}), ErrNotSpaceAdmin)
+}
+
 func TestSpaceRepo_Remove(t *testing.T) {
 	fx := newFixture(t)
 	require.NoError(t, fx.Create(ctx, domain.Space{
 		Id:     "1",
 		Author: "a",
 	}))
+	require.ErrorIs(t, fx.Remove(ctx, domain.Space{
+		Id:     "1",
+		Author: "b",
+	}), ErrNotSpaceAdmin)
 	require.NoError(t, fx.Remove(ctx, domain.Space{
 		Id:     "1",
 		Author: "a",
@@ -57,6 +86,144 @@ func TestSpaceRepo_Remove(t *testing.T) {
 	}), ErrSpaceNotFound)
 }
 
+func TestSpaceRepo_Admins(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "1", Author: "a"}))
+
+	require.ErrorIs(t, fx.AddAdmin(ctx, domain.Space{Id: "1", Author: "b"}, "c"), ErrNotSpaceAdmin)
+	require.ErrorIs(t, fx.AddAdmin(ctx, domain.Space{Id: "2", Author: "a"}, "c"), ErrSpaceNotFound)
+	require.NoError(t, fx.AddAdmin(ctx, domain.Space{Id: "1", Author: "a"}, "b"))
+	// repeated add
+	require.NoError(t, fx.AddAdmin(ctx, domain.Space{Id: "1", Author: "b"}, "a"))
+
+	require.NoError(t, fx.Transfer(ctx, domain.Space{Id: "1", Author: "b"}, "c"))
+	spaces, err := fx.GetByAccount(ctx, "c")
+	require.NoError(t, err)
+	require.Len(t, spaces, 1)
+	require.Equal(t, []string{"a", "b", "c"}, spaces[0].Admins)
+
+	require.NoError(t, fx.RemoveAdmin(ctx, domain.Space{Id: "1", Author: "c"}, "a"))
+	require.NoError(t, fx.RemoveAdmin(ctx, domain.Space{Id: "1", Author: "b"}, "b"))
+	require.ErrorIs(t, fx.RemoveAdmin(ctx, domain.Space{Id: "1", Author: "b"}, "c"), ErrNotSpaceAdmin)
+	require.ErrorIs(t, fx.RemoveAdmin(ctx, domain.Space{Id: "1", Author: "c"}, "c"), ErrLastSpaceAdmin)
+
+	// admins instead of the author manage the space
+	require.ErrorIs(t, fx.Remove(ctx, domain.Space{Id: "1", Author: "a"}), ErrNotSpaceAdmin)
+	require.NoError(t, fx.Remove(ctx, domain.Space{Id: "1", Author: "c"}))
+}
+
+func TestSpaceRepo_Policy(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "1", Author: "a"}))
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "2", Author: "a"}))
+	policy := domain.SpacePolicy{Mode: domain.SpacePolicyMentionsOnly, MaxFanOutPerMinute: 10}
+
+	require.ErrorIs(t, fx.SetPolicy(ctx, domain.Space{Id: "1", Author: "b"}, policy), ErrNotSpaceAdmin)
+	require.NoError(t, fx.SetPolicy(ctx, domain.Space{Id: "1", Author: "a"}, policy))
+
+	space, err := fx.Get(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, policy, space.Policy)
+	_, err = fx.Get(ctx, "3")
+	require.ErrorIs(t, err, ErrSpaceNotFound)
+
+	policies, err := fx.GetPolicies(ctx, []string{"1", "2", "3"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]domain.SpacePolicy{"1": policy, "2": {}}, policies)
+}
+
+func TestSpaceRepo_RemovedSince(t *testing.T) {
+	fx := newFixture(t)
+	before := time.Now().Add(-time.Minute)
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "1", Author: "a"}))
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "2", Author: "a"}))
+	require.NoError(t, fx.Remove(ctx, domain.Space{Id: "1", Author: "a"}))
+
+	removed, err := fx.RemovedSince(ctx, []string{"1", "2"}, before)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, removed)
+
+	removed, err = fx.RemovedSince(ctx, []string{"1", "2"}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, removed)
+}
+
+func TestSpaceRepo_Rekey(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "1", Author: "a"}))
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "2", Author: "a"}))
+
+	require.ErrorIs(t, fx.Rekey(ctx, domain.Space{Id: "0", Author: "a"}, "3"), ErrSpaceNotFound)
+	require.ErrorIs(t, fx.Rekey(ctx, domain.Space{Id: "1", Author: "b"}, "3"), ErrNotSpaceAdmin)
+	require.ErrorIs(t, fx.Rekey(ctx, domain.Space{Id: "1", Author: "a"}, "2"), ErrSpaceExists)
+	require.NoError(t, fx.Rekey(ctx, domain.Space{Id: "1", Author: "a"}, "3"))
+
+	existed, err := fx.ExistedSpaces(ctx, []string{"1", "3"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"3"}, existed)
+}
+
+func TestSpaceRepo_RemoveAccount(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "1", Author: "a"}))
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "2", Author: "a"}))
+	require.NoError(t, fx.AddAdmin(ctx, domain.Space{Id: "2", Author: "a"}, "b"))
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "3", Author: "b"}))
+	require.NoError(t, fx.AddAdmin(ctx, domain.Space{Id: "3", Author: "b"}, "a"))
+	require.NoError(t, fx.AddOneToOneMember(ctx, "4", "a"))
+	require.NoError(t, fx.AddOneToOneMember(ctx, "4", "b"))
+	require.NoError(t, fx.AddOneToOneMember(ctx, "5", "a"))
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "6", Author: "b"}))
+
+	removedIds, err := fx.RemoveAccount(ctx, "a")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"1", "5"}, removedIds)
+	removedIds, err = fx.RemoveAccount(ctx, "a")
+	require.NoError(t, err)
+	require.Empty(t, removedIds)
+
+	existed, err := fx.ExistedSpaces(ctx, []string{"1", "2", "3", "4", "5", "6"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"2", "3", "4", "6"}, existed)
+	removed, err := fx.RemovedSince(ctx, []string{"1", "2", "5"}, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"1", "5"}, removed)
+
+	// the remaining admin or member becomes the author
+	for _, id := range []string{"2", "3", "4"} {
+		space, err := fx.Get(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, "b", space.Author)
+		require.False(t, space.IsAdmin("a"))
+		require.NotContains(t, space.Members, "a")
+	}
+	spaces, err := fx.GetByAccount(ctx, "a")
+	require.NoError(t, err)
+	require.Empty(t, spaces)
+}
+
+func TestSpaceRepo_GetByAccount(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "1", Author: "a"}))
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "2", Author: "b"}))
+	require.NoError(t, fx.AddAdmin(ctx, domain.Space{Id: "2", Author: "b"}, "a"))
+	require.NoError(t, fx.AddOneToOneMember(ctx, "3", "b"))
+	require.NoError(t, fx.AddOneToOneMember(ctx, "3", "a"))
+	require.NoError(t, fx.Create(ctx, domain.Space{Id: "4", Author: "b"}))
+
+	spaces, err := fx.GetByAccount(ctx, "a")
+	require.NoError(t, err)
+	var ids []string
+	for _, space := range spaces {
+		ids = append(ids, space.Id)
+	}
+	require.ElementsMatch(t, []string{"1", "2", "3"}, ids)
+
+	spaces, err = fx.GetByAccount(ctx, "c")
+	require.NoError(t, err)
+	require.Len(t, spaces, 0)
+}
+
 func newFixture(t testing.TB) *fixture {
 	fx := &fixture{
 		SpaceRepo: New(),
@@ -84,6 +251,7 @@ type fixture struct {
 
 func (fx *fixture) finish(t testing.TB) {
 	_ = fx.SpaceRepo.(*spaceRepo).coll.Drop(ctx)
+	_ = fx.SpaceRepo.(*spaceRepo).removedColl.Drop(ctx)
 	require.NoError(t, fx.a.Close(ctx))
 }
 
@@ -0,0 +1,46 @@
+package tokenrepo
+
+const (
+	defaultJanitorIntervalMin = 60
+	defaultStaleDays          = 60
+	defaultGraceDays          = 30
+	defaultMaxFailures        = 3
+	defaultQuarantineDays     = 7
+	defaultStatsIntervalMin   = 10
+)
+
+type configSource interface {
+	GetTokenRepo() Config
+}
+
+type Config struct {
+	Janitor    JanitorConfig    `yaml:"janitor"`
+	Quarantine QuarantineConfig `yaml:"quarantine"`
+	Stats      StatsConfig      `yaml:"stats"`
+}
+
+type StatsConfig struct {
+	// Enabled turns on periodic counting of valid tokens by client version
+	Enabled bool `yaml:"enabled"`
+	// IntervalMin is a period between counts
+	IntervalMin int `yaml:"intervalMin"`
+}
+
+type QuarantineConfig struct {
+	// MaxFailures is a number of invalid token reports after which the token is removed
+	MaxFailures int `yaml:"maxFailures"`
+	// Days is a period after which a quarantined token is removed on the next invalid report
+	Days int `yaml:"days"`
+}
+
+type JanitorConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// DryRun only counts and logs tokens the janitor would change
+	DryRun bool `yaml:"dryRun"`
+	// IntervalMin is a period between janitor runs
+	IntervalMin int `yaml:"intervalMin"`
+	// StaleDays is a number of days without SetToken after which a token becomes invalid
+	StaleDays int `yaml:"staleDays"`
+	// GraceDays is a number of days an invalid token is kept before removal
+	GraceDays int `yaml:"graceDays"`
+}
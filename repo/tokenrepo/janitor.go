@@ -0,0 +1,94 @@
+package tokenrepo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+func (t *tokenRepo) runJanitor() {
+	interval := time.Duration(t.conf.Janitor.IntervalMin) * time.Minute
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := t.cleanup(t.runCtx, time.Now()); err != nil {
+			log.Warn("janitor error", zap.Error(err))
+		}
+		select {
+		case <-t.runCtx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *tokenRepo) cleanup(ctx context.Context, now time.Time) (err error) {
+	st := time.Now()
+	staleBefore := now.AddDate(0, 0, -t.conf.Janitor.StaleDays).Unix()
+	removeBefore := now.AddDate(0, 0, -t.conf.Janitor.GraceDays).Unix()
+	dryRun := t.conf.Janitor.DryRun
+
+	// staleness is tracked per account binding, the same token may still be refreshed by another account
+	stale, err := t.staleBindings(ctx, staleBefore)
+	if err != nil {
+		return
+	}
+	if !dryRun {
+		if err = t.UpdateTokensStatus(ctx, stale, domain.TokenStatusInvalid); err != nil {
+			return
+		}
+	}
+	t.metrics.janitorInvalidated.Add(uint64(len(stale)))
+
+	removeFilter := bson.D{
+		{"status", domain.TokenStatusInvalid},
+		{"updated", bson.D{{"$lt", removeBefore}}},
+	}
+	var removed int64
+	if dryRun {
+		removed, err = t.coll.CountDocuments(ctx, removeFilter)
+	} else {
+		res, dErr := t.coll.DeleteMany(ctx, removeFilter)
+		if dErr != nil {
+			return dErr
+		}
+		removed = res.DeletedCount
+	}
+	if err != nil {
+		return
+	}
+	t.metrics.janitorRemoved.Add(uint64(removed))
+	t.metrics.janitorRuns.Add(1)
+
+	log.Info("janitor finished",
+		zap.Bool("dryRun", dryRun),
+		zap.Int("invalidated", len(stale)),
+		zap.Int64("removed", removed),
+		zap.Duration("dur", time.Since(st)),
+	)
+	return
+}
+
+func (t *tokenRepo) staleBindings(ctx context.Context, staleBefore int64) (tokens []domain.Token, err error) {
+	cur, err := t.coll.Find(ctx, bson.D{
+		{"status", domain.TokenStatusValid},
+		{"$or", bson.A{
+			bson.D{{"refreshed", bson.D{{"$lt", staleBefore}}}},
+			// tokens registered before refresh tracking was introduced
+			bson.D{{"refreshed", bson.D{{"$exists", false}}}, {"updated", bson.D{{"$lt", staleBefore}}}},
+		}},
+	}, options.Find().SetProjection(bson.D{{"token", 1}, {"accountId", 1}, {"peerId", 1}}))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+	err = cur.All(ctx, &tokens)
+	return
+}
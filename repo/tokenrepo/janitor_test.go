@@ -0,0 +1,76 @@
+package tokenrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+func TestTokenRepo_Cleanup(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(days int) int64 {
+		return now.AddDate(0, 0, -days).Unix()
+	}
+	prepare := func(t *testing.T, dryRun bool) *fixture {
+		fx := newFixture(t)
+		tr := fx.TokenRepo.(*tokenRepo)
+		tr.conf.Janitor.DryRun = dryRun
+		tr.conf.Janitor.StaleDays = 60
+		tr.conf.Janitor.GraceDays = 30
+		_, err := tr.coll.InsertMany(ctx, []any{
+			// fresh
+			domain.Token{Id: "1", AccountId: "a", PeerId: "p1", Status: domain.TokenStatusValid, Updated: daysAgo(1), Refreshed: daysAgo(1)},
+			// stale
+			domain.Token{Id: "2", AccountId: "a", PeerId: "p2", Status: domain.TokenStatusValid, Updated: daysAgo(61), Refreshed: daysAgo(61)},
+			// invalid within grace period
+			domain.Token{Id: "3", AccountId: "a", PeerId: "p3", Status: domain.TokenStatusInvalid, Updated: daysAgo(10), Refreshed: daysAgo(90)},
+			// invalid after grace period
+			domain.Token{Id: "4", AccountId: "a", PeerId: "p4", Status: domain.TokenStatusInvalid, Updated: daysAgo(31), Refreshed: daysAgo(91)},
+		})
+		require.NoError(t, err)
+		// legacy stale token without refreshed field
+		_, err = tr.coll.InsertOne(ctx, map[string]any{"_id": "5", "token": "5", "accountId": "a", "peerId": "p5", "status": domain.TokenStatusValid, "updated": daysAgo(70)})
+		require.NoError(t, err)
+		require.NoError(t, tr.cleanup(ctx, now))
+		return fx
+	}
+	statuses := func(t *testing.T, fx *fixture) map[string]domain.TokenStatus {
+		tokens, err := fx.GetTokensByAccountId(ctx, "a")
+		require.NoError(t, err)
+		res := map[string]domain.TokenStatus{}
+		for _, token := range tokens {
+			res[token.Id] = token.Status
+		}
+		return res
+	}
+
+	t.Run("cleanup", func(t *testing.T) {
+		fx := prepare(t, false)
+		assert.Equal(t, map[string]domain.TokenStatus{
+			"1": domain.TokenStatusValid,
+			"2": domain.TokenStatusInvalid,
+			"3": domain.TokenStatusInvalid,
+			"5": domain.TokenStatusInvalid,
+		}, statuses(t, fx))
+		tr := fx.TokenRepo.(*tokenRepo)
+		assert.Equal(t, uint64(2), tr.metrics.janitorInvalidated.Load())
+		assert.Equal(t, uint64(1), tr.metrics.janitorRemoved.Load())
+	})
+	t.Run("dry run", func(t *testing.T) {
+		fx := prepare(t, true)
+		assert.Equal(t, map[string]domain.TokenStatus{
+			"1": domain.TokenStatusValid,
+			"2": domain.TokenStatusValid,
+			"3": domain.TokenStatusInvalid,
+			"4": domain.TokenStatusInvalid,
+			"5": domain.TokenStatusValid,
+		}, statuses(t, fx))
+		tr := fx.TokenRepo.(*tokenRepo)
+		assert.Equal(t, uint64(2), tr.metrics.janitorInvalidated.Load())
+		assert.Equal(t, uint64(1), tr.metrics.janitorRemoved.Load())
+	})
+}
@@ -0,0 +1,37 @@
+package tokenrepo
+
+import "github.com/prometheus/client_golang/prometheus"
+
+func registerMetrics(reg *prometheus.Registry, t *tokenRepo) {
+	reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "push",
+		Subsystem: "tokenrepo",
+		Name:      "janitor_invalidated_tokens",
+		Help:      "total count of tokens marked as invalid by janitor",
+	}, func() float64 {
+		return float64(t.metrics.janitorInvalidated.Load())
+	}))
+	reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "push",
+		Subsystem: "tokenrepo",
+		Name:      "janitor_removed_tokens",
+		Help:      "total count of invalid tokens removed by janitor",
+	}, func() float64 {
+		return float64(t.metrics.janitorRemoved.Load())
+	}))
+	reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "push",
+		Subsystem: "tokenrepo",
+		Name:      "janitor_runs",
+		Help:      "total count of janitor runs",
+	}, func() float64 {
+		return float64(t.metrics.janitorRuns.Load())
+	}))
+	t.metrics.tokensByVersion = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "push",
+		Subsystem: "tokenrepo",
+		Name:      "valid_tokens",
+		Help:      "count of valid tokens by platform and client version",
+	}, []string{"platform", "appVersion", "buildChannel"})
+	reg.MustRegister(t.metrics.tokensByVersion)
+}
@@ -84,6 +84,36 @@ func (mr *MockTokenRepoMockRecorder) GetActiveTokensByAccountIds(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveTokensByAccountIds", reflect.TypeOf((*MockTokenRepo)(nil).GetActiveTokensByAccountIds), arg0, arg1)
 }
 
+// GetActiveTokensByPeerId mocks base method.
+func (m *MockTokenRepo) GetActiveTokensByPeerId(arg0 context.Context, arg1, arg2 string) ([]domain.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveTokensByPeerId", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveTokensByPeerId indicates an expected call of GetActiveTokensByPeerId.
+func (mr *MockTokenRepoMockRecorder) GetActiveTokensByPeerId(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveTokensByPeerId", reflect.TypeOf((*MockTokenRepo)(nil).GetActiveTokensByPeerId), arg0, arg1, arg2)
+}
+
+// GetTokensByAccountId mocks base method.
+func (m *MockTokenRepo) GetTokensByAccountId(arg0 context.Context, arg1 string) ([]domain.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokensByAccountId", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokensByAccountId indicates an expected call of GetTokensByAccountId.
+func (mr *MockTokenRepoMockRecorder) GetTokensByAccountId(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokensByAccountId", reflect.TypeOf((*MockTokenRepo)(nil).GetTokensByAccountId), arg0, arg1)
+}
+
 // Init mocks base method.
 func (m *MockTokenRepo) Init(arg0 *app.App) error {
 	m.ctrl.T.Helper()
@@ -112,6 +142,49 @@ func (mr *MockTokenRepoMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockTokenRepo)(nil).Name))
 }
 
+// QuarantineTokens mocks base method.
+func (m *MockTokenRepo) QuarantineTokens(arg0 context.Context, arg1 []string, arg2 domain.TokenInvalidReason) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuarantineTokens", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuarantineTokens indicates an expected call of QuarantineTokens.
+func (mr *MockTokenRepoMockRecorder) QuarantineTokens(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuarantineTokens", reflect.TypeOf((*MockTokenRepo)(nil).QuarantineTokens), arg0, arg1, arg2)
+}
+
+// RemoveAccountTokens mocks base method.
+func (m *MockTokenRepo) RemoveAccountTokens(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAccountTokens", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAccountTokens indicates an expected call of RemoveAccountTokens.
+func (mr *MockTokenRepoMockRecorder) RemoveAccountTokens(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAccountTokens", reflect.TypeOf((*MockTokenRepo)(nil).RemoveAccountTokens), arg0, arg1)
+}
+
+// RemoveDeviceTokens mocks base method.
+func (m *MockTokenRepo) RemoveDeviceTokens(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDeviceTokens", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDeviceTokens indicates an expected call of RemoveDeviceTokens.
+func (mr *MockTokenRepoMockRecorder) RemoveDeviceTokens(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDeviceTokens", reflect.TypeOf((*MockTokenRepo)(nil).RemoveDeviceTokens), arg0, arg1, arg2)
+}
+
 // RemoveTokens mocks base method.
 func (m *MockTokenRepo) RemoveTokens(arg0 context.Context, arg1 []string) error {
 	m.ctrl.T.Helper()
@@ -126,6 +199,20 @@ func (mr *MockTokenRepoMockRecorder) RemoveTokens(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTokens", reflect.TypeOf((*MockTokenRepo)(nil).RemoveTokens), arg0, arg1)
 }
 
+// RestoreTokens mocks base method.
+func (m *MockTokenRepo) RestoreTokens(arg0 context.Context, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTokens", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTokens indicates an expected call of RestoreTokens.
+func (mr *MockTokenRepoMockRecorder) RestoreTokens(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTokens", reflect.TypeOf((*MockTokenRepo)(nil).RestoreTokens), arg0, arg1)
+}
+
 // RevokeToken mocks base method.
 func (m *MockTokenRepo) RevokeToken(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -167,3 +254,17 @@ func (mr *MockTokenRepoMockRecorder) UpdateTokenStatus(arg0, arg1, arg2 any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTokenStatus", reflect.TypeOf((*MockTokenRepo)(nil).UpdateTokenStatus), arg0, arg1, arg2)
 }
+
+// UpdateTokensStatus mocks base method.
+func (m *MockTokenRepo) UpdateTokensStatus(arg0 context.Context, arg1 []domain.Token, arg2 domain.TokenStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTokensStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTokensStatus indicates an expected call of UpdateTokensStatus.
+func (mr *MockTokenRepoMockRecorder) UpdateTokensStatus(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTokensStatus", reflect.TypeOf((*MockTokenRepo)(nil).UpdateTokensStatus), arg0, arg1, arg2)
+}
@@ -0,0 +1,160 @@
+package tokenrepo
+
+import (
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+type versionStat struct {
+	Platform     domain.Platform
+	AppVersion   string
+	BuildChannel domain.BuildChannel
+	Count        int64
+}
+
+func (t *tokenRepo) runStats() {
+	interval := time.Duration(t.conf.Stats.IntervalMin) * time.Minute
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := t.updateVersionStats(t.runCtx); err != nil {
+			log.Warn("token stats error", zap.Error(err))
+		}
+		select {
+		case <-t.runCtx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+const (
+	// maxVersionLabels bounds the number of app versions exposed as metric labels, less popular versions are reported as other
+	maxVersionLabels    = 50
+	unknownVersionLabel = "unknown"
+	otherVersionLabel   = "other"
+)
+
+var versionRe = regexp.MustCompile(`^\d{1,4}(\.\d{1,4}){0,3}(-[a-z0-9]{1,16})?$`)
+
+// normalizeVersionStats maps client reported versions to a bounded set of labels
+func normalizeVersionStats(stats []versionStat) []versionStat {
+	var totals = make(map[string]int64)
+	for _, st := range stats {
+		if versionRe.MatchString(st.AppVersion) {
+			totals[st.AppVersion] += st.Count
+		}
+	}
+	versions := make([]string, 0, len(totals))
+	for version := range totals {
+		versions = append(versions, version)
+	}
+	slices.SortFunc(versions, func(a, b string) int {
+		if c := cmp.Compare(totals[b], totals[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	if len(versions) > maxVersionLabels {
+		versions = versions[:maxVersionLabels]
+	}
+
+	type statKey struct {
+		platform     domain.Platform
+		appVersion   string
+		buildChannel domain.BuildChannel
+	}
+	var byKey = make(map[statKey]int64)
+	var keys []statKey
+	for _, st := range stats {
+		key := statKey{platform: st.Platform, appVersion: st.AppVersion, buildChannel: st.BuildChannel}
+		switch {
+		case !versionRe.MatchString(st.AppVersion):
+			key.appVersion = unknownVersionLabel
+		case !slices.Contains(versions, st.AppVersion):
+			key.appVersion = otherVersionLabel
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] += st.Count
+	}
+	result := make([]versionStat, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, versionStat{
+			Platform:     key.platform,
+			AppVersion:   key.appVersion,
+			BuildChannel: key.buildChannel,
+			Count:        byKey[key],
+		})
+	}
+	return result
+}
+
+func (t *tokenRepo) updateVersionStats(ctx context.Context) (err error) {
+	stats, err := t.countByVersion(ctx)
+	if err != nil {
+		return
+	}
+	stats = normalizeVersionStats(stats)
+	// reset to drop versions without tokens
+	t.metrics.tokensByVersion.Reset()
+	for _, st := range stats {
+		t.metrics.tokensByVersion.WithLabelValues(
+			st.Platform.String(),
+			st.AppVersion,
+			st.BuildChannel.String(),
+		).Set(float64(st.Count))
+	}
+	return
+}
+
+func (t *tokenRepo) countByVersion(ctx context.Context) (stats []versionStat, err error) {
+	cur, err := t.coll.Aggregate(ctx, mongo.Pipeline{
+		{{"$match", bson.D{{"status", domain.TokenStatusValid}}}},
+		{{"$group", bson.D{
+			{"_id", bson.D{
+				{"platform", "$platform"},
+				{"appVersion", "$appVersion"},
+				{"buildChannel", "$buildChannel"},
+			}},
+			{"count", bson.D{{"$sum", 1}}},
+		}}},
+	})
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+	for cur.Next(ctx) {
+		var doc struct {
+			Id struct {
+				Platform     domain.Platform     `bson:"platform"`
+				AppVersion   string              `bson:"appVersion"`
+				BuildChannel domain.BuildChannel `bson:"buildChannel"`
+			} `bson:"_id"`
+			Count int64 `bson:"count"`
+		}
+		if err = cur.Decode(&doc); err != nil {
+			return
+		}
+		stats = append(stats, versionStat{
+			Platform:     doc.Id.Platform,
+			AppVersion:   doc.Id.AppVersion,
+			BuildChannel: doc.Id.BuildChannel,
+			Count:        doc.Count,
+		})
+	}
+	err = cur.Err()
+	return
+}
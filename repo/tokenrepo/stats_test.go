@@ -0,0 +1,60 @@
+package tokenrepo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anyproto/anytype-push-server/domain"
+)
+
+func TestTokenRepo_CountByVersion(t *testing.T) {
+	fx := newFixture(t)
+	tokens := []domain.Token{
+		{Id: "1", AccountId: "a", PeerId: "p1", Platform: domain.PlatformIOS, AppVersion: "1.0.0"},
+		{Id: "2", AccountId: "a", PeerId: "p2", Platform: domain.PlatformIOS, AppVersion: "1.0.0"},
+		{Id: "3", AccountId: "a", PeerId: "p3", Platform: domain.PlatformIOS, AppVersion: "1.1.0", BuildChannel: domain.BuildChannelBeta},
+		{Id: "4", AccountId: "a", PeerId: "p4", Platform: domain.PlatformAndroid, AppVersion: "1.0.0"},
+		{Id: "5", AccountId: "a", PeerId: "p5", Platform: domain.PlatformAndroid, AppVersion: "0.9.0"},
+	}
+	for _, token := range tokens {
+		require.NoError(t, fx.AddToken(ctx, token))
+	}
+	require.NoError(t, fx.UpdateTokenStatus(ctx, "5", domain.TokenStatusInvalid))
+
+	stats, err := fx.TokenRepo.(*tokenRepo).countByVersion(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []versionStat{
+		{Platform: domain.PlatformIOS, AppVersion: "1.0.0", BuildChannel: domain.BuildChannelRelease, Count: 2},
+		{Platform: domain.PlatformIOS, AppVersion: "1.1.0", BuildChannel: domain.BuildChannelBeta, Count: 1},
+		{Platform: domain.PlatformAndroid, AppVersion: "1.0.0", BuildChannel: domain.BuildChannelRelease, Count: 1},
+	}, stats)
+}
+
+func TestNormalizeVersionStats(t *testing.T) {
+	t.Run("unknown", func(t *testing.T) {
+		stats := normalizeVersionStats([]versionStat{
+			{Platform: domain.PlatformIOS, AppVersion: "1.0.0", Count: 2},
+			{Platform: domain.PlatformIOS, AppVersion: "", Count: 1},
+			{Platform: domain.PlatformIOS, AppVersion: "<script>", Count: 1},
+			{Platform: domain.PlatformIOS, AppVersion: "1.0.0-beta1", Count: 1},
+		})
+		assert.ElementsMatch(t, []versionStat{
+			{Platform: domain.PlatformIOS, AppVersion: "1.0.0", Count: 2},
+			{Platform: domain.PlatformIOS, AppVersion: unknownVersionLabel, Count: 2},
+			{Platform: domain.PlatformIOS, AppVersion: "1.0.0-beta1", Count: 1},
+		}, stats)
+	})
+	t.Run("other", func(t *testing.T) {
+		var stats []versionStat
+		for i := range maxVersionLabels + 10 {
+			stats = append(stats, versionStat{Platform: domain.PlatformAndroid, AppVersion: fmt.Sprintf("1.%d", i), Count: int64(100 - i)})
+		}
+		stats = normalizeVersionStats(stats)
+		require.Len(t, stats, maxVersionLabels+1)
+		assert.Contains(t, stats, versionStat{Platform: domain.PlatformAndroid, AppVersion: otherVersionLabel, Count: 10*(100-maxVersionLabels) - 45})
+		assert.Contains(t, stats, versionStat{Platform: domain.PlatformAndroid, AppVersion: "1.0", Count: 100})
+	})
+}
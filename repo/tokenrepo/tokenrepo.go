@@ -5,12 +5,17 @@ package tokenrepo
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/app/logger"
+	"github.com/anyproto/any-sync/metric"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
 
 	"github.com/anyproto/anytype-push-server/db"
 	"github.com/anyproto/anytype-push-server/domain"
@@ -20,9 +25,9 @@ const CName = "push.toknenrepo"
 
 const collName = "token"
 
-var (
-	ErrTokenExists = errors.New("token exists")
-)
+var log = logger.NewNamed(CName)
+
+var ErrTokenNotFound = errors.New("token not found")
 
 func New() TokenRepo {
 	return new(tokenRepo)
@@ -31,26 +36,94 @@ func New() TokenRepo {
 type TokenRepo interface {
 	AddToken(ctx context.Context, token domain.Token) (err error)
 	RevokeToken(ctx context.Context, accountId string, peerId string) error
+	RemoveDeviceTokens(ctx context.Context, accountId string, peerId string) error
 	UpdateTokenStatus(ctx context.Context, tokenId string, status domain.TokenStatus) (err error)
+	// UpdateTokensStatus sets the status of the given bindings, other accounts bound to the same token are not affected
+	UpdateTokensStatus(ctx context.Context, tokens []domain.Token, status domain.TokenStatus) (err error)
 	RemoveTokens(ctx context.Context, tokens []string) error
+	// QuarantineTokens marks tokens as invalid and removes the ones reported more than the configured number of times
+	// or quarantined longer than the configured period
+	QuarantineTokens(ctx context.Context, tokens []string, reason domain.TokenInvalidReason) (removed int64, err error)
+	// RestoreTokens brings quarantined tokens back after a successful delivery
+	RestoreTokens(ctx context.Context, tokens []string) (err error)
+	RemoveAccountTokens(ctx context.Context, accountId string) error
+	// GetActiveTokensByAccountIds returns valid and quarantined tokens, quarantined ones are probed by delivery
 	GetActiveTokensByAccountIds(ctx context.Context, accountIds []string) (token []domain.Token, err error)
+	GetActiveTokensByPeerId(ctx context.Context, accountId string, peerId string) (tokens []domain.Token, err error)
+	GetTokensByAccountId(ctx context.Context, accountId string) (tokens []domain.Token, err error)
 	app.ComponentRunnable
 }
 
 type tokenRepo struct {
-	coll *mongo.Collection
+	coll         *mongo.Collection
+	conf         Config
+	runCtx       context.Context
+	runCtxCancel context.CancelFunc
+	metrics      struct {
+		janitorInvalidated atomic.Uint64
+		janitorRemoved     atomic.Uint64
+		janitorRuns        atomic.Uint64
+		tokensByVersion    *prometheus.GaugeVec
+	}
 }
 
 func (t *tokenRepo) Init(a *app.App) (err error) {
 	t.coll = a.MustComponent(db.CName).(db.Database).Db().Collection(collName)
+	t.conf = a.MustComponent("config").(configSource).GetTokenRepo()
+	if t.conf.Janitor.IntervalMin <= 0 {
+		t.conf.Janitor.IntervalMin = defaultJanitorIntervalMin
+	}
+	if t.conf.Janitor.StaleDays <= 0 {
+		t.conf.Janitor.StaleDays = defaultStaleDays
+	}
+	if t.conf.Janitor.GraceDays <= 0 {
+		t.conf.Janitor.GraceDays = defaultGraceDays
+	}
+	if t.conf.Quarantine.MaxFailures <= 0 {
+		t.conf.Quarantine.MaxFailures = defaultMaxFailures
+	}
+	if t.conf.Quarantine.Days <= 0 {
+		t.conf.Quarantine.Days = defaultQuarantineDays
+	}
+	if t.conf.Stats.IntervalMin <= 0 {
+		t.conf.Stats.IntervalMin = defaultStatsIntervalMin
+	}
+	if m, ok := a.Component(metric.CName).(metric.Metric); ok {
+		registerMetrics(m.Registry(), t)
+	} else {
+		// stats are exported only as metrics
+		t.conf.Stats.Enabled = false
+	}
+	t.runCtx, t.runCtxCancel = context.WithCancel(context.Background())
 	return
 }
 
 func (t *tokenRepo) Run(ctx context.Context) error {
-	_, err := t.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{{"accountId", 1}, {"status", 1}},
+	if err := t.migrateTokenField(ctx); err != nil {
+		return err
+	}
+	if err := t.removeDuplicatedPeerTokens(ctx); err != nil {
+		return err
+	}
+	_, err := t.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"token", 1}}},
+		{Keys: bson.D{{"accountId", 1}, {"status", 1}}},
+		{Keys: bson.D{{"status", 1}, {"refreshed", 1}}},
+		{
+			Keys:    bson.D{{"accountId", 1}, {"peerId", 1}},
+			Options: options.Index().SetUnique(true),
+		},
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if t.conf.Janitor.Enabled {
+		go t.runJanitor()
+	}
+	if t.conf.Stats.Enabled {
+		go t.runStats()
+	}
+	return nil
 }
 
 func (t *tokenRepo) Name() (name string) {
@@ -58,28 +131,114 @@ func (t *tokenRepo) Name() (name string) {
 }
 
 func (t *tokenRepo) AddToken(ctx context.Context, token domain.Token) (err error) {
+	// the same account can't be bound to the token via another peer
+	if _, err = t.coll.DeleteMany(ctx, bson.D{
+		{"token", token.Id},
+		{"accountId", token.AccountId},
+		{"peerId", bson.D{{"$ne", token.PeerId}}},
+	}); err != nil {
+		return
+	}
+	// concurrent upserts of the same device may both try to insert, the loser is retried as an update
+	if err = t.upsertToken(ctx, token); mongo.IsDuplicateKeyError(err) {
+		err = t.upsertToken(ctx, token)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		log.Info("token replaced by a concurrent update", zap.String("accountId", token.AccountId), zap.String("peerId", token.PeerId))
+		return nil
+	}
+	return
+}
+
+// upsertToken keeps one active token per account and device, so the binding is updated in place after rotation
+func (t *tokenRepo) upsertToken(ctx context.Context, token domain.Token) (err error) {
 	now := time.Now().Unix()
-	token.Created = now
-	token.Updated = now
-	opts := options.Update().SetUpsert(true)
-	_, err = t.coll.UpdateByID(
+	_, err = t.coll.UpdateOne(
 		ctx,
-		token.Id,
 		bson.D{
-			{"$set", bson.D{
+			{"accountId", token.AccountId},
+			{"peerId", token.PeerId},
+		},
+		bson.A{
+			bson.D{{"$set", bson.D{
+				// failures of the previous token don't apply to the new one
+				{"failCount", bson.D{{"$cond", bson.A{
+					bson.D{{"$eq", bson.A{"$token", token.Id}}},
+					bson.D{{"$ifNull", bson.A{"$failCount", 0}}},
+					0,
+				}}}},
+				{"token", token.Id},
 				{"platform", token.Platform},
-				{"updated", time.Now().Unix()},
-				{"peerId", token.PeerId},
-				{"accountId", token.AccountId},
+				{"updated", now},
 				{"status", token.Status},
-			}},
-			{"$setOnInsert", bson.D{{"created", time.Now().Unix()}}},
+				{"appVersion", token.AppVersion},
+				{"osVersion", token.OsVersion},
+				{"buildChannel", token.BuildChannel},
+				{"apnsEnvironment", token.ApnsEnvironment},
+				{"refreshed", now},
+				{"created", bson.D{{"$ifNull", bson.A{"$created", now}}}},
+			}}},
+			bson.D{{"$unset", bson.A{"invalidReason", "invalidSince"}}},
 		},
-		opts,
+		options.Update().SetUpsert(true),
 	)
-	if mongo.IsDuplicateKeyError(err) {
-		return ErrTokenExists
+	return
+}
+
+// migrateTokenField copies the token value from _id to the token field for documents
+// written before one token could be bound to several accounts
+func (t *tokenRepo) migrateTokenField(ctx context.Context) (err error) {
+	res, err := t.coll.UpdateMany(ctx,
+		bson.D{{"token", bson.D{{"$exists", false}}}},
+		bson.A{bson.D{{"$set", bson.D{{"token", "$_id"}}}}},
+	)
+	if err != nil {
+		return
+	}
+	if res.ModifiedCount > 0 {
+		log.Info("migrated token field", zap.Int64("count", res.ModifiedCount))
+	}
+	return
+}
+
+// removeDuplicatedPeerTokens keeps only the latest token for every account and peer pair,
+// it's required to build the unique index over the data written before the index was introduced
+func (t *tokenRepo) removeDuplicatedPeerTokens(ctx context.Context) (err error) {
+	cur, err := t.coll.Aggregate(ctx, mongo.Pipeline{
+		{{"$sort", bson.D{{"updated", -1}}}},
+		{{"$group", bson.D{
+			{"_id", bson.D{{"accountId", "$accountId"}, {"peerId", "$peerId"}}},
+			{"ids", bson.D{{"$push", "$_id"}}},
+			{"count", bson.D{{"$sum", 1}}},
+		}}},
+		{{"$match", bson.D{{"count", bson.D{{"$gt", 1}}}}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+	var toRemove bson.A
+	for cur.Next(ctx) {
+		var group struct {
+			Ids bson.A `bson:"ids"`
+		}
+		if err = cur.Decode(&group); err != nil {
+			return
+		}
+		toRemove = append(toRemove, group.Ids[1:]...)
+	}
+	if err = cur.Err(); err != nil {
+		return
+	}
+	if len(toRemove) == 0 {
+		return
+	}
+	if _, err = t.coll.DeleteMany(ctx, bson.D{{"_id", bson.D{{"$in", toRemove}}}}); err != nil {
+		return
 	}
+	log.Info("removed duplicated peer tokens", zap.Int("count", len(toRemove)))
 	return
 }
 
@@ -94,15 +253,91 @@ func (t *tokenRepo) RevokeToken(ctx context.Context, accountId string, peerId st
 	return
 }
 
+func (t *tokenRepo) RemoveDeviceTokens(ctx context.Context, accountId string, peerId string) (err error) {
+	res, err := t.coll.DeleteMany(ctx, bson.D{
+		{"accountId", accountId},
+		{"peerId", peerId},
+	})
+	if err != nil {
+		return
+	}
+	if res.DeletedCount == 0 {
+		return ErrTokenNotFound
+	}
+	return
+}
+
 func (t *tokenRepo) RemoveTokens(ctx context.Context, tokens []string) (err error) {
-	_, err = t.coll.DeleteMany(ctx, bson.D{{"_id", bson.D{{"$in", tokens}}}})
+	_, err = t.coll.DeleteMany(ctx, bson.D{{"token", bson.D{{"$in", tokens}}}})
+	return
+}
+
+func (t *tokenRepo) QuarantineTokens(ctx context.Context, tokens []string, reason domain.TokenInvalidReason) (removed int64, err error) {
+	now := time.Now().Unix()
+	filter := bson.D{{"token", bson.D{{"$in", tokens}}}}
+	// pipeline update to keep the original invalidSince for already quarantined tokens
+	_, err = t.coll.UpdateMany(ctx, filter, bson.A{
+		bson.D{{"$set", bson.D{
+			{"invalidSince", bson.D{{"$cond", bson.A{
+				bson.D{{"$eq", bson.A{"$status", domain.TokenStatusValid}}},
+				now,
+				"$invalidSince",
+			}}}},
+			{"status", domain.TokenStatusInvalid},
+			{"invalidReason", reason},
+			{"failCount", bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$failCount", 0}}}, 1}}}},
+			{"updated", now},
+		}}},
+	})
+	if err != nil {
+		return
+	}
+	quarantinedBefore := time.Now().AddDate(0, 0, -t.conf.Quarantine.Days).Unix()
+	res, err := t.coll.DeleteMany(ctx, append(filter, bson.E{"$or", bson.A{
+		bson.D{{"failCount", bson.D{{"$gte", t.conf.Quarantine.MaxFailures}}}},
+		bson.D{{"invalidSince", bson.D{{"$lt", quarantinedBefore}}}},
+	}}))
+	if err != nil {
+		return
+	}
+	return res.DeletedCount, nil
+}
+
+func (t *tokenRepo) RestoreTokens(ctx context.Context, tokens []string) (err error) {
+	_, err = t.coll.UpdateMany(ctx,
+		append(bson.D{{"token", bson.D{{"$in", tokens}}}}, quarantinedFilter...),
+		bson.D{
+			{"$set", bson.D{
+				{"status", domain.TokenStatusValid},
+				{"failCount", 0},
+				{"updated", time.Now().Unix()},
+			}},
+			{"$unset", bson.D{{"invalidReason", ""}, {"invalidSince", ""}}},
+		},
+	)
+	return
+}
+
+// quarantinedFilter matches tokens reported by a provider, unlike stale tokens they are still used for delivery
+var quarantinedFilter = bson.D{
+	{"status", domain.TokenStatusInvalid},
+	{"invalidReason", bson.D{{"$exists", true}}},
+}
+
+var activeFilter = bson.E{"$or", bson.A{
+	bson.D{{"status", domain.TokenStatusValid}},
+	quarantinedFilter,
+}}
+
+func (t *tokenRepo) RemoveAccountTokens(ctx context.Context, accountId string) (err error) {
+	_, err = t.coll.DeleteMany(ctx, bson.D{{"accountId", accountId}})
 	return
 }
 
 func (t *tokenRepo) UpdateTokenStatus(ctx context.Context, tokenId string, status domain.TokenStatus) (err error) {
-	_, err = t.coll.UpdateOne(
+	_, err = t.coll.UpdateMany(
 		ctx,
-		bson.D{{"_id", tokenId}},
+		bson.D{{"token", tokenId}},
 		bson.D{{"$set", bson.D{
 			{"status", status},
 			{"updated", time.Now().Unix()},
@@ -110,10 +345,49 @@ func (t *tokenRepo) UpdateTokenStatus(ctx context.Context, tokenId string, statu
 	return
 }
 
+func (t *tokenRepo) UpdateTokensStatus(ctx context.Context, tokens []domain.Token, status domain.TokenStatus) (err error) {
+	if len(tokens) == 0 {
+		return nil
+	}
+	now := time.Now().Unix()
+	models := make([]mongo.WriteModel, 0, len(tokens))
+	for _, token := range tokens {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{
+				{"token", token.Id},
+				{"accountId", token.AccountId},
+				{"peerId", token.PeerId},
+			}).
+			SetUpdate(bson.D{{"$set", bson.D{
+				{"status", status},
+				{"updated", now},
+			}}}),
+		)
+	}
+	_, err = t.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return
+}
+
 func (t *tokenRepo) GetActiveTokensByAccountIds(ctx context.Context, accountIds []string) (tokens []domain.Token, err error) {
 	cur, err := t.coll.Find(ctx, bson.D{
 		{"accountId", bson.D{{"$in", accountIds}}},
-		{"status", domain.TokenStatusValid},
+		activeFilter,
+	})
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+	err = cur.All(ctx, &tokens)
+	return
+}
+
+func (t *tokenRepo) GetActiveTokensByPeerId(ctx context.Context, accountId string, peerId string) (tokens []domain.Token, err error) {
+	cur, err := t.coll.Find(ctx, bson.D{
+		{"accountId", accountId},
+		{"peerId", peerId},
+		activeFilter,
 	})
 	if err != nil {
 		return
@@ -125,6 +399,21 @@ func (t *tokenRepo) GetActiveTokensByAccountIds(ctx context.Context, accountIds
 	return
 }
 
+func (t *tokenRepo) GetTokensByAccountId(ctx context.Context, accountId string) (tokens []domain.Token, err error) {
+	cur, err := t.coll.Find(ctx, bson.D{{"accountId", accountId}})
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+	err = cur.All(ctx, &tokens)
+	return
+}
+
 func (t *tokenRepo) Close(ctx context.Context) (err error) {
+	if t.runCtxCancel != nil {
+		t.runCtxCancel()
+	}
 	return nil
 }
@@ -2,11 +2,16 @@ package tokenrepo
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/anyproto/any-sync/app"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/anyproto/anytype-push-server/db"
 	"github.com/anyproto/anytype-push-server/domain"
@@ -28,10 +33,112 @@ func TestTokenRepo_AddToken(t *testing.T) {
 		PeerId:    "122",
 		Platform:  domain.PlatformAndroid,
 	}))
-	tokens, err := fx.GetActiveTokensByAccountIds(ctx, []string{"b"})
+	// the device is shared, so the token stays bound to both accounts
+	for _, accountId := range []string{"a", "b"} {
+		tokens, err := fx.GetActiveTokensByAccountIds(ctx, []string{accountId})
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.Equal(t, "1", tokens[0].Id)
+		assert.Equal(t, accountId, tokens[0].AccountId)
+	}
+}
+
+func TestTokenRepo_MigrateTokenField(t *testing.T) {
+	fx := newFixture(t)
+	tr := fx.TokenRepo.(*tokenRepo)
+	_, err := tr.coll.InsertOne(ctx, map[string]any{"_id": "1", "accountId": "a", "peerId": "p1", "status": domain.TokenStatusValid})
+	require.NoError(t, err)
+
+	require.NoError(t, tr.Run(ctx))
+
+	tokens, err := fx.GetTokensByAccountId(ctx, "a")
 	require.NoError(t, err)
 	require.Len(t, tokens, 1)
-	require.Equal(t, "1", tokens[0].Id)
+	assert.Equal(t, "1", tokens[0].Id)
+
+	// the legacy document is updated in place on the next registration
+	require.NoError(t, fx.AddToken(ctx, domain.Token{Id: "1", AccountId: "a", PeerId: "p1"}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{Id: "1", AccountId: "b", PeerId: "p1"}))
+	tokens, err = fx.GetActiveTokensByAccountIds(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Len(t, tokens, 2)
+}
+
+func TestTokenRepo_AddToken_ReplacesPeerToken(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "1",
+		AccountId: "a",
+		PeerId:    "p1",
+	}))
+	// token rotation on the same device
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "2",
+		AccountId: "a",
+		PeerId:    "p1",
+	}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "3",
+		AccountId: "a",
+		PeerId:    "p2",
+	}))
+
+	tokens, err := fx.GetActiveTokensByPeerId(ctx, "a", "p1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "2", tokens[0].Id)
+
+	tokens, err = fx.GetTokensByAccountId(ctx, "a")
+	require.NoError(t, err)
+	assert.Len(t, tokens, 2)
+
+	// the unique index doesn't allow a second token for the same device
+	_, err = fx.TokenRepo.(*tokenRepo).coll.InsertOne(ctx, domain.Token{Id: "4", AccountId: "a", PeerId: "p1"})
+	assert.True(t, mongo.IsDuplicateKeyError(err))
+}
+
+func TestTokenRepo_AddToken_Concurrent(t *testing.T) {
+	fx := newFixture(t)
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fx.AddToken(ctx, domain.Token{Id: fmt.Sprint(i), AccountId: "a", PeerId: "p"})
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	tokens, err := fx.GetTokensByAccountId(ctx, "a")
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+}
+
+func TestTokenRepo_RemoveDuplicatedPeerTokens(t *testing.T) {
+	fx := newFixture(t)
+	tr := fx.TokenRepo.(*tokenRepo)
+	_, err := tr.coll.Indexes().DropAll(ctx)
+	require.NoError(t, err)
+	_, err = tr.coll.InsertMany(ctx, []any{
+		domain.Token{Id: "1", AccountId: "a", PeerId: "p1", Updated: 1},
+		domain.Token{Id: "2", AccountId: "a", PeerId: "p1", Updated: 3},
+		domain.Token{Id: "3", AccountId: "a", PeerId: "p1", Updated: 2},
+		domain.Token{Id: "4", AccountId: "a", PeerId: "p2", Updated: 1},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, tr.Run(ctx))
+
+	tokens, err := fx.GetTokensByAccountId(ctx, "a")
+	require.NoError(t, err)
+	var ids []string
+	for _, token := range tokens {
+		ids = append(ids, token.Id)
+	}
+	assert.ElementsMatch(t, []string{"2", "4"}, ids)
 }
 
 func TestTokenRepo_RemoveToken(t *testing.T) {
@@ -48,6 +155,19 @@ func TestTokenRepo_RemoveToken(t *testing.T) {
 	require.Len(t, tokens, 0)
 }
 
+func TestTokenRepo_RevokeToken_SharedToken(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.AddToken(ctx, domain.Token{Id: "1", AccountId: "a1", PeerId: "p1"}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{Id: "1", AccountId: "a2", PeerId: "p1"}))
+
+	require.NoError(t, fx.RevokeToken(ctx, "a1", "p1"))
+
+	tokens, err := fx.GetActiveTokensByAccountIds(ctx, []string{"a1", "a2"})
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "a2", tokens[0].AccountId)
+}
+
 func TestTokenRepo_RemoveTokens(t *testing.T) {
 	fx := newFixture(t)
 	require.NoError(t, fx.AddToken(ctx, domain.Token{
@@ -68,6 +188,35 @@ func TestTokenRepo_RemoveTokens(t *testing.T) {
 	assert.Len(t, res, 0)
 }
 
+func TestTokenRepo_RemoveAccountTokens(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "1",
+		AccountId: "a1",
+		PeerId:    "p1",
+	}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "2",
+		AccountId: "a1",
+		PeerId:    "p2",
+	}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "3",
+		AccountId: "a2",
+		PeerId:    "p3",
+	}))
+
+	require.NoError(t, fx.RemoveAccountTokens(ctx, "a1"))
+	require.NoError(t, fx.RemoveAccountTokens(ctx, "a1"))
+
+	res, err := fx.GetTokensByAccountId(ctx, "a1")
+	require.NoError(t, err)
+	assert.Len(t, res, 0)
+	res, err = fx.GetTokensByAccountId(ctx, "a2")
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+}
+
 func TestTokenRepo_UpdateTokenStatus(t *testing.T) {
 	fx := newFixture(t)
 	require.NoError(t, fx.AddToken(ctx, domain.Token{
@@ -82,6 +231,204 @@ func TestTokenRepo_UpdateTokenStatus(t *testing.T) {
 	require.Len(t, tokens, 0)
 }
 
+func TestTokenRepo_UpdateTokensStatus(t *testing.T) {
+	fx := newFixture(t)
+	// the same token is bound to two accounts
+	for _, accountId := range []string{"a1", "a2"} {
+		require.NoError(t, fx.AddToken(ctx, domain.Token{Id: "1", AccountId: accountId, PeerId: "p1"}))
+	}
+	require.NoError(t, fx.UpdateTokensStatus(ctx, []domain.Token{{Id: "1", AccountId: "a1", PeerId: "p1"}}, domain.TokenStatusInvalid))
+	require.NoError(t, fx.UpdateTokensStatus(ctx, nil, domain.TokenStatusInvalid))
+
+	for accountId, status := range map[string]domain.TokenStatus{"a1": domain.TokenStatusInvalid, "a2": domain.TokenStatusValid} {
+		tokens, err := fx.GetTokensByAccountId(ctx, accountId)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.Equal(t, status, tokens[0].Status, accountId)
+	}
+}
+
+func TestTokenRepo_GetActiveTokensByPeerId(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "1",
+		AccountId: "a1",
+		PeerId:    "p1",
+	}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "2",
+		AccountId: "a1",
+		PeerId:    "p2",
+	}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "3",
+		AccountId: "a2",
+		PeerId:    "p1",
+	}))
+
+	tokens, err := fx.GetActiveTokensByPeerId(ctx, "a1", "p1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "1", tokens[0].Id)
+
+	tokens, err = fx.GetActiveTokensByPeerId(ctx, "a2", "p2")
+	require.NoError(t, err)
+	assert.Len(t, tokens, 0)
+}
+
+func TestTokenRepo_GetTokensByAccountId(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:              "1",
+		AccountId:       "a1",
+		PeerId:          "p1",
+		AppVersion:      "1.0.0",
+		OsVersion:       "14",
+		BuildChannel:    domain.BuildChannelDev,
+		ApnsEnvironment: domain.ApnsEnvironmentSandbox,
+	}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "2",
+		AccountId: "a1",
+		PeerId:    "p2",
+	}))
+	require.NoError(t, fx.UpdateTokenStatus(ctx, "2", domain.TokenStatusInvalid))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "3",
+		AccountId: "a2",
+		PeerId:    "p3",
+	}))
+
+	tokens, err := fx.GetTokensByAccountId(ctx, "a1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	for _, token := range tokens {
+		if token.Id == "1" {
+			assert.Equal(t, "1.0.0", token.AppVersion)
+			assert.Equal(t, "14", token.OsVersion)
+			assert.Equal(t, domain.BuildChannelDev, token.BuildChannel)
+			assert.Equal(t, domain.ApnsEnvironmentSandbox, token.ApnsEnvironment)
+		} else {
+			assert.Equal(t, domain.TokenStatusInvalid, token.Status)
+		}
+	}
+}
+
+func TestTokenRepo_RemoveDeviceTokens(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "1",
+		AccountId: "a1",
+		PeerId:    "p1",
+	}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "2",
+		AccountId: "a1",
+		PeerId:    "p2",
+	}))
+
+	require.ErrorIs(t, fx.RemoveDeviceTokens(ctx, "a2", "p1"), ErrTokenNotFound)
+	require.NoError(t, fx.RemoveDeviceTokens(ctx, "a1", "p1"))
+	require.ErrorIs(t, fx.RemoveDeviceTokens(ctx, "a1", "p1"), ErrTokenNotFound)
+
+	tokens, err := fx.GetTokensByAccountId(ctx, "a1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "p2", tokens[0].PeerId)
+}
+
+func TestTokenRepo_QuarantineTokens(t *testing.T) {
+	fx := newFixture(t)
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "1",
+		AccountId: "a1",
+		PeerId:    "p1",
+		Status:    domain.TokenStatusValid,
+	}))
+	require.NoError(t, fx.AddToken(ctx, domain.Token{
+		Id:        "2",
+		AccountId: "a1",
+		PeerId:    "p2",
+		Status:    domain.TokenStatusValid,
+	}))
+
+	removed, err := fx.QuarantineTokens(ctx, []string{"1"}, domain.TokenInvalidReasonInvalidArgument)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), removed)
+
+	// quarantined tokens are still used for delivery
+	tokens, err := fx.GetActiveTokensByAccountIds(ctx, []string{"a1"})
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+
+	tokens, err = fx.GetTokensByAccountId(ctx, "a1")
+	require.NoError(t, err)
+	var quarantined domain.Token
+	for _, token := range tokens {
+		if token.Id == "1" {
+			quarantined = token
+		}
+	}
+	assert.Equal(t, domain.TokenStatusInvalid, quarantined.Status)
+	assert.Equal(t, domain.TokenInvalidReasonInvalidArgument, quarantined.InvalidReason)
+	assert.Equal(t, 1, quarantined.FailCount)
+	assert.NotZero(t, quarantined.InvalidSince)
+
+	t.Run("revive on set token", func(t *testing.T) {
+		require.NoError(t, fx.AddToken(ctx, domain.Token{
+			Id:        "1",
+			AccountId: "a1",
+			PeerId:    "p1",
+			Status:    domain.TokenStatusValid,
+		}))
+		tokens, err := fx.GetActiveTokensByAccountIds(ctx, []string{"a1"})
+		require.NoError(t, err)
+		require.Len(t, tokens, 2)
+		for _, token := range tokens {
+			assert.Empty(t, token.InvalidReason)
+			assert.Zero(t, token.InvalidSince)
+		}
+	})
+	t.Run("remove after max failures", func(t *testing.T) {
+		for range defaultMaxFailures - 2 {
+			removed, err = fx.QuarantineTokens(ctx, []string{"1"}, domain.TokenInvalidReasonUnregistered)
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), removed)
+		}
+		removed, err = fx.QuarantineTokens(ctx, []string{"1"}, domain.TokenInvalidReasonUnregistered)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+
+		tokens, err = fx.GetTokensByAccountId(ctx, "a1")
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.Equal(t, "2", tokens[0].Id)
+	})
+	t.Run("restore after delivery", func(t *testing.T) {
+		_, err = fx.QuarantineTokens(ctx, []string{"2"}, domain.TokenInvalidReasonInvalidArgument)
+		require.NoError(t, err)
+		require.NoError(t, fx.RestoreTokens(ctx, []string{"2"}))
+		tokens, err := fx.GetTokensByAccountId(ctx, "a1")
+		require.NoError(t, err)
+		for _, token := range tokens {
+			assert.Equal(t, domain.TokenStatusValid, token.Status)
+			assert.Zero(t, token.FailCount)
+			assert.Empty(t, token.InvalidReason)
+		}
+	})
+	t.Run("remove after quarantine period", func(t *testing.T) {
+		_, err = fx.QuarantineTokens(ctx, []string{"2"}, domain.TokenInvalidReasonUnregistered)
+		require.NoError(t, err)
+		_, err = fx.TokenRepo.(*tokenRepo).coll.UpdateMany(ctx, bson.D{{"token", "2"}}, bson.D{{"$set", bson.D{
+			{"invalidSince", time.Now().AddDate(0, 0, -defaultQuarantineDays-1).Unix()},
+		}}})
+		require.NoError(t, err)
+		removed, err = fx.QuarantineTokens(ctx, []string{"2"}, domain.TokenInvalidReasonUnregistered)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+	})
+}
+
 func newFixture(t testing.TB) *fixture {
 	fx := &fixture{
 		TokenRepo: New(),
@@ -127,3 +474,7 @@ func (t testConfig) Name() (name string) {
 func (t testConfig) GetMongo() db.Mongo {
 	return t.Mongo
 }
+
+func (t testConfig) GetTokenRepo() Config {
+	return Config{}
+}
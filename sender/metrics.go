@@ -40,4 +40,11 @@ func registerMetrics(reg *prometheus.Registry, s *sender) {
 		},
 	}, []string{"platform"})
 	reg.MustRegister(s.metrics.sendDuration)
+	s.metrics.invalidTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "push",
+		Subsystem: "sender",
+		Name:      "invalid_tokens_total",
+		Help:      "count of tokens reported as invalid by providers",
+	}, []string{"reason"})
+	reg.MustRegister(s.metrics.invalidTokens)
 }
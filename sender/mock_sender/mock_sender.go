@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/anyproto/anytype-push-server/sender (interfaces: Sender)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock_sender/mock_sender.go github.com/anyproto/anytype-push-server/sender Sender
+//
+
+// Package mock_sender is a generated GoMock package.
+package mock_sender
+
+import (
+	context "context"
+	reflect "reflect"
+
+	app "github.com/anyproto/any-sync/app"
+	domain "github.com/anyproto/anytype-push-server/domain"
+	sender "github.com/anyproto/anytype-push-server/sender"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSender is a mock of Sender interface.
+type MockSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSenderMockRecorder
+}
+
+// MockSenderMockRecorder is the mock recorder for MockSender.
+type MockSenderMockRecorder struct {
+	mock *MockSender
+}
+
+// NewMockSender creates a new mock instance.
+func NewMockSender(ctrl *gomock.Controller) *MockSender {
+	mock := &MockSender{ctrl: ctrl}
+	mock.recorder = &MockSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSender) EXPECT() *MockSenderMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockSender) Close(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockSenderMockRecorder) Close(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSender)(nil).Close), arg0)
+}
+
+// Init mocks base method.
+func (m *MockSender) Init(arg0 *app.App) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockSenderMockRecorder) Init(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockSender)(nil).Init), arg0)
+}
+
+// Name mocks base method.
+func (m *MockSender) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockSenderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockSender)(nil).Name))
+}
+
+// RegisterProvider mocks base method.
+func (m *MockSender) RegisterProvider(arg0 domain.Platform, arg1 sender.Provider) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterProvider", arg0, arg1)
+}
+
+// RegisterProvider indicates an expected call of RegisterProvider.
+func (mr *MockSenderMockRecorder) RegisterProvider(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterProvider", reflect.TypeOf((*MockSender)(nil).RegisterProvider), arg0, arg1)
+}
+
+// RegisterSandboxProvider mocks base method.
+func (m *MockSender) RegisterSandboxProvider(arg0 domain.Platform, arg1 sender.Provider) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterSandboxProvider", arg0, arg1)
+}
+
+// RegisterSandboxProvider indicates an expected call of RegisterSandboxProvider.
+func (mr *MockSenderMockRecorder) RegisterSandboxProvider(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterSandboxProvider", reflect.TypeOf((*MockSender)(nil).RegisterSandboxProvider), arg0, arg1)
+}
+
+// Run mocks base method.
+func (m *MockSender) Run(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockSenderMockRecorder) Run(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockSender)(nil).Run), arg0)
+}
+
+// ValidateToken mocks base method.
+func (m *MockSender) ValidateToken(arg0 context.Context, arg1 domain.Platform, arg2 domain.ApnsEnvironment, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateToken", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateToken indicates an expected call of ValidateToken.
+func (mr *MockSenderMockRecorder) ValidateToken(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockSender)(nil).ValidateToken), arg0, arg1, arg2, arg3)
+}
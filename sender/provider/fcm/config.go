@@ -8,6 +8,8 @@ type Config struct {
 	CredentialsFile struct {
 		IOS     string `yaml:"ios"`
 		Android string `yaml:"android"`
+		// IOSSandbox is optional, used for iOS tokens of the APNs sandbox environment
+		IOSSandbox string `yaml:"iosSandbox"`
 	} `yaml:"credentialsFile"`
 	DefaultMessage struct {
 		Title    string `yaml:"title"`
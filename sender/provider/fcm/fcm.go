@@ -2,6 +2,7 @@ package fcm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 
@@ -46,6 +47,14 @@ func (f *fcm) Init(a *app.App) (err error) {
 		return err
 	}
 	s.RegisterProvider(domain.PlatformIOS, ios)
+
+	if conf.CredentialsFile.IOSSandbox != "" {
+		iosSandbox, err := newSender(conf, domain.PlatformIOS, conf.CredentialsFile.IOSSandbox)
+		if err != nil {
+			return err
+		}
+		s.RegisterSandboxProvider(domain.PlatformIOS, iosSandbox)
+	}
 	return
 }
 
@@ -74,60 +83,96 @@ type fcmSender struct {
 
 const batchSize = 500
 
-func (f *fcmSender) SendMessage(ctx context.Context, message domain.Message, onInvalid func(token string)) (err error) {
-	nextBatch := message.Tokens
-	for len(nextBatch) > 0 {
-		if len(nextBatch) > batchSize {
-			message.Tokens = nextBatch[:batchSize]
-			nextBatch = nextBatch[batchSize:]
-		} else {
-			message.Tokens = nextBatch
-			nextBatch = nil
-		}
-
-		var multicastMessage *messaging.MulticastMessage
-		switch f.platform {
-		case domain.PlatformIOS:
-			if message.Silent {
-				multicastMessage = f.buildFcmIosSilentMessage(message)
-			} else {
-				multicastMessage = f.buildFcmIosMessage(message)
-			}
-		case domain.PlatformAndroid:
-			if message.Silent {
-				multicastMessage = f.buildFcmAndroidSilentMessage(message)
-			} else {
-				multicastMessage = f.buildFcmAndroidMessage(message)
-			}
-		default:
-			return fmt.Errorf("unexpected platform %v", f.platform)
+func (f *fcmSender) SendMessage(ctx context.Context, message domain.Message, onInvalid func(token string, reason domain.TokenInvalidReason)) (err error) {
+	if f.platform != domain.PlatformIOS && f.platform != domain.PlatformAndroid {
+		return fmt.Errorf("unexpected platform %v", f.platform)
+	}
+	var errs []error
+	for start := 0; start < len(message.Tokens); start += batchSize {
+		end := min(start+batchSize, len(message.Tokens))
+		messages := make([]*messaging.Message, 0, end-start)
+		for i := start; i < end; i++ {
+			messages = append(messages, f.buildFcmMessage(message, i))
 		}
 
 		var response *messaging.BatchResponse
-		if response, err = f.client.SendEachForMulticast(ctx, multicastMessage); err != nil {
-			return err
+		if response, err = f.client.SendEach(ctx, messages); err != nil {
+			// the next batches are still sent, their tokens are not affected by this error
+			log.Warn("fcm batch error", zap.Int("tokens", len(messages)), zap.Error(err))
+			errs = append(errs, err)
+			continue
 		}
 		for i, resp := range response.Responses {
 			if resp.Error == nil {
 				continue
 			}
-			log.Warn("fcm resp error", zap.Error(resp.Error))
-			if messaging.IsInvalidArgument(resp.Error) || messaging.IsUnregistered(resp.Error) {
-				onInvalid(message.Tokens[i])
-				log.Info("mark token as invalid", zap.String("token", message.Tokens[i]))
+			token := messages[i].Token
+			if reason, ok := invalidReason(resp.Error); ok {
+				onInvalid(token, reason)
+				log.Info("mark token as invalid", zap.String("token", token), zap.String("reason", string(reason)))
 			} else {
-				log.Warn("fcm returned error", zap.Error(resp.Error), zap.String("token", message.Tokens[i]))
+				log.Warn("fcm returned error", zap.Error(resp.Error), zap.String("token", token))
 			}
 		}
 		log.Info("push sent", zap.Int("success", response.SuccessCount), zap.Int("failure", response.FailureCount))
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// buildFcmMessage builds the message to the i-th token of the message
+func (f *fcmSender) buildFcmMessage(message domain.Message, i int) *messaging.Message {
+	data := message.Data
+	if i < len(message.AccountIds) {
+		data = make(map[string]string, len(message.Data)+1)
+		maps.Copy(data, message.Data)
+		data["x-any-account-id"] = message.AccountIds[i]
+	}
+	token := message.Tokens[i]
+	if f.platform == domain.PlatformIOS {
+		if message.Silent {
+			return f.buildFcmIosSilentMessage(token, data)
+		}
+		return f.buildFcmIosMessage(token, data)
+	}
+	if message.Silent {
+		return f.buildFcmAndroidSilentMessage(token, data)
+	}
+	return f.buildFcmAndroidMessage(token, data)
+}
+
+func (f *fcmSender) ValidateToken(ctx context.Context, token string) (err error) {
+	_, err = f.client.SendDryRun(ctx, &messaging.Message{
+		Token: token,
+		Data:  map[string]string{"x-any-type": "silent"},
+	})
+	if err == nil {
+		return nil
+	}
+	if reason, ok := invalidReason(err); ok {
+		log.Info("token validation failed", zap.String("reason", string(reason)), zap.Error(err))
+		return sender.ErrInvalidToken
+	}
+	return err
+}
+
+func invalidReason(err error) (reason domain.TokenInvalidReason, ok bool) {
+	switch {
+	case messaging.IsUnregistered(err):
+		return domain.TokenInvalidReasonUnregistered, true
+	case messaging.IsInvalidArgument(err):
+		return domain.TokenInvalidReasonInvalidArgument, true
+	case messaging.IsSenderIDMismatch(err):
+		// the token belongs to another firebase project
+		return domain.TokenInvalidReasonSenderMismatch, true
+	default:
+		return "", false
+	}
 }
 
-func (f *fcmSender) buildFcmIosMessage(message domain.Message) *messaging.MulticastMessage {
-	return &messaging.MulticastMessage{
-		Tokens: message.Tokens,
-		Data:   message.Data,
+func (f *fcmSender) buildFcmIosMessage(token string, data map[string]string) *messaging.Message {
+	return &messaging.Message{
+		Token: token,
+		Data:  data,
 		Notification: &messaging.Notification{
 			Title:    f.config.DefaultMessage.Title,
 			Body:     f.config.DefaultMessage.Body,
@@ -143,10 +188,10 @@ func (f *fcmSender) buildFcmIosMessage(message domain.Message) *messaging.Multic
 	}
 }
 
-func (f *fcmSender) buildFcmIosSilentMessage(message domain.Message) *messaging.MulticastMessage {
-	return &messaging.MulticastMessage{
-		Tokens: message.Tokens,
-		Data:   message.Data,
+func (f *fcmSender) buildFcmIosSilentMessage(token string, data map[string]string) *messaging.Message {
+	return &messaging.Message{
+		Token: token,
+		Data:  data,
 		APNS: &messaging.APNSConfig{
 			Payload: &messaging.APNSPayload{
 				Aps: &messaging.Aps{
@@ -157,24 +202,24 @@ func (f *fcmSender) buildFcmIosSilentMessage(message domain.Message) *messaging.
 	}
 }
 
-func (f *fcmSender) buildFcmAndroidMessage(message domain.Message) *messaging.MulticastMessage {
+func (f *fcmSender) buildFcmAndroidMessage(token string, messageData map[string]string) *messaging.Message {
 	var data = make(map[string]string)
-	maps.Copy(data, message.Data)
+	maps.Copy(data, messageData)
 	data["x-any-title"] = f.config.DefaultMessage.Title
 	data["x-any-body"] = f.config.DefaultMessage.Body
 	data["x-any-image-url"] = f.config.DefaultMessage.ImageUrl
-	return &messaging.MulticastMessage{
-		Tokens: message.Tokens,
-		Data:   data,
+	return &messaging.Message{
+		Token: token,
+		Data:  data,
 		Android: &messaging.AndroidConfig{
 			Priority: "high",
 		},
 	}
 }
 
-func (f *fcmSender) buildFcmAndroidSilentMessage(message domain.Message) *messaging.MulticastMessage {
-	return &messaging.MulticastMessage{
-		Tokens: message.Tokens,
-		Data:   message.Data,
+func (f *fcmSender) buildFcmAndroidSilentMessage(token string, data map[string]string) *messaging.Message {
+	return &messaging.Message{
+		Token: token,
+		Data:  data,
 	}
 }
@@ -1,9 +1,13 @@
+//go:generate mockgen -destination mock_sender/mock_sender.go github.com/anyproto/anytype-push-server/sender Sender
+
 package sender
 
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"slices"
 	"sync/atomic"
 	"time"
@@ -17,7 +21,9 @@ import (
 
 	"github.com/anyproto/anytype-push-server/domain"
 	"github.com/anyproto/anytype-push-server/queue"
+	"github.com/anyproto/anytype-push-server/ratelimit"
 	"github.com/anyproto/anytype-push-server/repo/accountrepo"
+	"github.com/anyproto/anytype-push-server/repo/spacerepo"
 	"github.com/anyproto/anytype-push-server/repo/tokenrepo"
 )
 
@@ -25,39 +31,61 @@ const CName = "push.sender"
 
 var log = logger.NewNamed(CName)
 
+var (
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrUnexpectedPlatform = errors.New("unexpected platform")
+)
+
 func New() Sender {
 	return new(sender)
 }
 
 type Sender interface {
 	RegisterProvider(p domain.Platform, provider Provider)
+	// RegisterSandboxProvider registers a provider for tokens of the APNs sandbox environment, such tokens fall back to the default provider if it's not registered
+	RegisterSandboxProvider(p domain.Platform, provider Provider)
+	// ValidateToken checks the token with a dry-run send, returns ErrInvalidToken for rejected tokens
+	ValidateToken(ctx context.Context, platform domain.Platform, env domain.ApnsEnvironment, token string) (err error)
 	app.ComponentRunnable
 }
 
 type Provider interface {
-	SendMessage(ctx context.Context, message domain.Message, onInvalid func(token string)) (err error)
+	SendMessage(ctx context.Context, message domain.Message, onInvalid func(token string, reason domain.TokenInvalidReason)) (err error)
+	ValidateToken(ctx context.Context, token string) (err error)
+}
+
+type invalidToken struct {
+	token  string
+	reason domain.TokenInvalidReason
 }
 
 type sender struct {
-	accountRepo   accountrepo.AccountRepo
-	tokenRepo     tokenrepo.TokenRepo
-	queue         queue.Queue
-	invalidTokens *mb.MB[string]
-	providers     map[domain.Platform]Provider
-	metrics       struct {
-		sendTokens   atomic.Uint64
-		errorTokens  atomic.Uint64
-		sendCount    atomic.Uint64
-		sendDuration *prometheus.SummaryVec
+	accountRepo      accountrepo.AccountRepo
+	spaceRepo        spacerepo.SpaceRepo
+	tokenRepo        tokenrepo.TokenRepo
+	queue            queue.Queue
+	rateLimit        ratelimit.RateLimit
+	invalidTokens    *mb.MB[invalidToken]
+	providers        map[domain.Platform]Provider
+	sandboxProviders map[domain.Platform]Provider
+	metrics          struct {
+		sendTokens    atomic.Uint64
+		errorTokens   atomic.Uint64
+		sendCount     atomic.Uint64
+		sendDuration  *prometheus.SummaryVec
+		invalidTokens *prometheus.CounterVec
 	}
 }
 
 func (s *sender) Init(a *app.App) (err error) {
 	s.accountRepo = a.MustComponent(accountrepo.CName).(accountrepo.AccountRepo)
+	s.spaceRepo = a.MustComponent(spacerepo.CName).(spacerepo.SpaceRepo)
 	s.tokenRepo = a.MustComponent(tokenrepo.CName).(tokenrepo.TokenRepo)
 	s.queue = a.MustComponent(queue.CName).(queue.Queue)
+	s.rateLimit = a.MustComponent(ratelimit.CName).(ratelimit.RateLimit)
 	s.providers = make(map[domain.Platform]Provider)
-	s.invalidTokens = mb.New[string](100)
+	s.sandboxProviders = make(map[domain.Platform]Provider)
+	s.invalidTokens = mb.New[invalidToken](100)
 	registerMetrics(a.MustComponent(metric.CName).(metric.Metric).Registry(), s)
 	return
 }
@@ -67,7 +95,7 @@ func (s *sender) Name() (name string) {
 }
 
 func (s *sender) Run(ctx context.Context) (err error) {
-	go s.removeTokensBatch()
+	go s.quarantineTokensBatch()
 	// TODO: move the num runners to the config
 	for range 10 {
 		if err = s.queue.Consume(ctx, s.SendMessage); err != nil {
@@ -93,16 +121,31 @@ func (s *sender) RegisterProvider(p domain.Platform, provider Provider) {
 	s.providers[p] = provider
 }
 
+func (s *sender) RegisterSandboxProvider(p domain.Platform, provider Provider) {
+	s.sandboxProviders[p] = provider
+}
+
+func (s *sender) provider(platform domain.Platform, env domain.ApnsEnvironment) (provider Provider, ok bool) {
+	if env == domain.ApnsEnvironmentSandbox {
+		if provider, ok = s.sandboxProviders[platform]; ok {
+			return
+		}
+	}
+	provider, ok = s.providers[platform]
+	return
+}
+
+func (s *sender) ValidateToken(ctx context.Context, platform domain.Platform, env domain.ApnsEnvironment, token string) (err error) {
+	provider, ok := s.provider(platform, env)
+	if !ok {
+		return ErrUnexpectedPlatform
+	}
+	return provider.ValidateToken(ctx, token)
+}
+
 func (s *sender) SendMessage(message queue.Message) (err error) {
 	ctx := context.Background()
-	accountIds, err := s.accountRepo.GetAccountIdsByTopics(ctx, message.Topics)
-	if err != nil {
-		return
-	}
-	accountIds = slices.DeleteFunc(accountIds, func(s string) bool {
-		return s == message.IgnoreAccountId
-	})
-	tokens, err := s.tokenRepo.GetActiveTokensByAccountIds(ctx, accountIds)
+	tokens, err := s.resolveTokens(ctx, message)
 	if err != nil {
 		return
 	}
@@ -120,63 +163,246 @@ func (s *sender) SendMessage(message queue.Message) (err error) {
 		data["x-any-payload"] = base64.StdEncoding.EncodeToString(message.Payload)
 		data["x-any-signature"] = base64.StdEncoding.EncodeToString(message.Signature)
 		data["x-any-key-id"] = message.KeyId
+		if message.Envelope {
+			data["x-any-payload-format"] = "envelope"
+		}
 	}
 	data["x-any-group-id"] = message.GroupId
 
-	var byProvider = make(map[domain.Platform]*domain.Message)
+	type providerKey struct {
+		platform domain.Platform
+		env      domain.ApnsEnvironment
+	}
+	var byProvider = make(map[providerKey]*domain.Message)
 
 	for _, token := range tokens {
-		msg := byProvider[token.Platform]
+		key := providerKey{platform: token.Platform, env: token.ApnsEnvironment}
+		msg := byProvider[key]
 		if msg == nil {
 			msg = &domain.Message{
 				Platform: token.Platform,
-				Tokens:   []string{token.Id},
 				Data:     data,
 				Silent:   message.Silent,
 			}
-		} else {
-			msg.Tokens = append(msg.Tokens, token.Id)
+			byProvider[key] = msg
+		}
+		msg.Tokens = append(msg.Tokens, token.Id)
+		msg.AccountIds = append(msg.AccountIds, token.AccountId)
+	}
+
+	var quarantined = make(map[string]bool)
+	for _, token := range tokens {
+		if token.Status == domain.TokenStatusInvalid {
+			quarantined[token.Id] = true
 		}
-		byProvider[token.Platform] = msg
 	}
+	// quarantined tokens the provider accepted are restored, so a spurious report doesn't silence the device
+	var delivered, failed []string
 
-	for prv, msg := range byProvider {
-		provider, ok := s.providers[prv]
+	for key, msg := range byProvider {
+		prv := key.platform
+		provider, ok := s.provider(prv, key.env)
 		if !ok {
 			log.Warn("unexpected provider", zap.String("provider", fmt.Sprint(prv)))
-		} else {
-			if err = provider.SendMessage(ctx, *msg, s.onInvalid); err != nil {
-				return err
+			continue
+		}
+		if sErr := provider.SendMessage(ctx, *msg, func(token string, reason domain.TokenInvalidReason) {
+			failed = append(failed, token)
+			s.onInvalid(token, reason)
+		}); sErr != nil {
+			// the message may be partially delivered, so it isn't retried and the other providers still get it
+			log.Warn("send message error",
+				zap.String("provider", prv.String()),
+				zap.String("groupId", message.GroupId),
+				zap.Int("tokens", len(msg.Tokens)),
+				zap.Error(sErr),
+			)
+			continue
+		}
+		for _, token := range msg.Tokens {
+			if quarantined[token] && !slices.Contains(failed, token) && !slices.Contains(delivered, token) {
+				delivered = append(delivered, token)
 			}
-			s.metrics.sendCount.Add(1)
-			s.metrics.sendTokens.Add(uint64(len(msg.Tokens)))
-			dur := time.Since(message.Created)
-			s.metrics.sendDuration.WithLabelValues(prv.String()).Observe(dur.Seconds())
+		}
+		s.metrics.sendCount.Add(1)
+		s.metrics.sendTokens.Add(uint64(len(msg.Tokens)))
+		dur := time.Since(message.Created)
+		s.metrics.sendDuration.WithLabelValues(prv.String()).Observe(dur.Seconds())
+	}
+	if len(delivered) > 0 {
+		// the message is already sent, so a failed restore doesn't trigger redelivery
+		if rErr := s.tokenRepo.RestoreTokens(ctx, delivered); rErr != nil {
+			log.Warn("restore tokens error", zap.Error(rErr))
 		}
 	}
 	return nil
 }
 
-func (s *sender) onInvalid(token string) {
+func (s *sender) resolveTokens(ctx context.Context, message queue.Message) (tokens []domain.Token, err error) {
+	if message.PeerId != "" {
+		return s.tokenRepo.GetActiveTokensByPeerId(ctx, message.AccountId, message.PeerId)
+	}
+	topics, err := s.dropRemovedSpaces(ctx, message)
+	if err != nil || len(topics) == 0 {
+		return
+	}
+	var accountIds []string
+	// silent messages are addressed to a particular account, space-wide subscribers don't need them
+	if message.Silent {
+		if accountIds, err = s.accountRepo.GetAccountIdsByTopics(ctx, topics); err != nil {
+			return
+		}
+		accountIds, err = s.filterRecipients(ctx, message, accountIds)
+	} else {
+		accountIds, err = s.resolveSubscribers(ctx, message, topics)
+	}
+	if err != nil {
+		return
+	}
+	return s.tokenRepo.GetActiveTokensByAccountIds(ctx, accountIds)
+}
+
+// dropRemovedSpaces filters out topics of spaces removed after the message was queued
+func (s *sender) dropRemovedSpaces(ctx context.Context, message queue.Message) (topics []domain.Topic, err error) {
+	var spaceKeys []string
+	for _, topic := range message.Topics {
+		if spaceKey := topic.SpaceKeyBase58(); !slices.Contains(spaceKeys, spaceKey) {
+			spaceKeys = append(spaceKeys, spaceKey)
+		}
+	}
+	if len(spaceKeys) == 0 {
+		return message.Topics, nil
+	}
+	removed, err := s.spaceRepo.RemovedSince(ctx, spaceKeys, message.Created)
+	if err != nil || len(removed) == 0 {
+		return message.Topics, err
+	}
+	return slices.DeleteFunc(slices.Clone(message.Topics), func(topic domain.Topic) bool {
+		return slices.Contains(removed, topic.SpaceKeyBase58())
+	}), nil
+}
+
+// resolveSubscribers applies the space policies, they may have changed since the message was queued,
+// and returns recipients of the topics including space-wide subscribers.
+// Subscribers of a space with a fan-out limit are capped to what is left of the space budget.
+func (s *sender) resolveSubscribers(ctx context.Context, message queue.Message, topics []domain.Topic) (accountIds []string, err error) {
+	policies, err := s.spaceRepo.GetPolicies(ctx, domain.SpaceKeys(topics))
+	if err != nil {
+		return nil, err
+	}
+	topics = slices.DeleteFunc(slices.Clone(topics), func(topic domain.Topic) bool {
+		return !policies[topic.SpaceKeyBase58()].AllowsTopic(topic)
+	})
+	var unlimited []domain.Topic
+	for _, spaceKey := range domain.SpaceKeys(topics) {
+		spaceTopics := slices.DeleteFunc(slices.Clone(topics), func(topic domain.Topic) bool {
+			return topic.SpaceKeyBase58() != spaceKey
+		})
+		maxFanOut := policies[spaceKey].MaxFanOutPerMinute
+		if maxFanOut <= 0 {
+			unlimited = append(unlimited, spaceTopics...)
+			continue
+		}
+		spaceAccountIds, err := s.accountRepo.GetAccountIdsByTopics(ctx, domain.WithSpaceWildcards(spaceTopics))
+		if err != nil {
+			return nil, err
+		}
+		// only actual recipients spend the budget
+		if spaceAccountIds, err = s.filterRecipients(ctx, message, spaceAccountIds); err != nil {
+			return nil, err
+		}
+		key := ratelimit.Key{Scope: ratelimit.ScopeSpaceFanOut, Id: spaceKey}
+		allowed, _, err := s.rateLimit.AllowN(ctx, key, len(spaceAccountIds), ratelimit.PerMinute(maxFanOut))
+		if err != nil {
+			return nil, err
+		}
+		if allowed < len(spaceAccountIds) {
+			log.Info("space fan-out limit exceeded",
+				zap.String("spaceKey", spaceKey),
+				zap.String("groupId", message.GroupId),
+				zap.Int("fanOut", len(spaceAccountIds)),
+				zap.Int("allowed", allowed),
+			)
+			// a random subset is served, so the same members are not skipped every time
+			rand.Shuffle(len(spaceAccountIds), func(i, j int) {
+				spaceAccountIds[i], spaceAccountIds[j] = spaceAccountIds[j], spaceAccountIds[i]
+			})
+			spaceAccountIds = spaceAccountIds[:allowed]
+		}
+		accountIds = appendUnique(accountIds, spaceAccountIds...)
+	}
+	if len(unlimited) > 0 {
+		unlimitedAccountIds, err := s.accountRepo.GetAccountIdsByTopics(ctx, domain.WithSpaceWildcards(unlimited))
+		if err != nil {
+			return nil, err
+		}
+		if unlimitedAccountIds, err = s.filterRecipients(ctx, message, unlimitedAccountIds); err != nil {
+			return nil, err
+		}
+		accountIds = appendUnique(accountIds, unlimitedAccountIds...)
+	}
+	return accountIds, nil
+}
+
+// filterRecipients drops the ignored account and accounts blocking the sender
+func (s *sender) filterRecipients(ctx context.Context, message queue.Message, accountIds []string) ([]string, error) {
+	accountIds = slices.DeleteFunc(accountIds, func(s string) bool {
+		return s == message.IgnoreAccountId
+	})
+	if message.SenderAccountId == "" || len(accountIds) == 0 {
+		return accountIds, nil
+	}
+	blocking, err := s.accountRepo.GetAccountIdsBlocking(ctx, accountIds, message.SenderAccountId)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(accountIds, func(s string) bool {
+		return slices.Contains(blocking, s)
+	}), nil
+}
+
+func appendUnique(accountIds []string, add ...string) []string {
+	for _, accountId := range add {
+		if !slices.Contains(accountIds, accountId) {
+			accountIds = append(accountIds, accountId)
+		}
+	}
+	return accountIds
+}
+
+func (s *sender) onInvalid(token string, reason domain.TokenInvalidReason) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	_ = s.invalidTokens.Add(ctx, token)
+	_ = s.invalidTokens.Add(ctx, invalidToken{token: token, reason: reason})
 }
 
-func (s *sender) removeTokensBatch() {
+func (s *sender) quarantineTokensBatch() {
 	ctx := mb.CtxWithTimeLimit(context.Background(), time.Second)
 	cond := s.invalidTokens.NewCond().WithMin(10)
 	for {
-		tokens, err := cond.Wait(ctx)
+		invalidTokens, err := cond.Wait(ctx)
 		if err != nil {
 			return
 		}
 		st := time.Now()
-		s.metrics.errorTokens.Add(uint64(len(tokens)))
-		if err = s.tokenRepo.RemoveTokens(ctx, tokens); err != nil {
-			log.Error("remove tokens error", zap.Error(err))
-		} else {
-			log.Info("remove tokens success", zap.Int("count", len(tokens)), zap.Duration("dur", time.Since(st)))
+		s.metrics.errorTokens.Add(uint64(len(invalidTokens)))
+		var byReason = make(map[domain.TokenInvalidReason][]string)
+		for _, it := range invalidTokens {
+			byReason[it.reason] = append(byReason[it.reason], it.token)
+			s.metrics.invalidTokens.WithLabelValues(string(it.reason)).Inc()
+		}
+		for reason, tokens := range byReason {
+			removed, qErr := s.tokenRepo.QuarantineTokens(ctx, tokens, reason)
+			if qErr != nil {
+				log.Error("quarantine tokens error", zap.Error(qErr), zap.String("reason", string(reason)))
+			} else {
+				log.Info("quarantine tokens success",
+					zap.Int("count", len(tokens)),
+					zap.Int64("removed", removed),
+					zap.String("reason", string(reason)),
+					zap.Duration("dur", time.Since(st)),
+				)
+			}
 		}
 	}
 }
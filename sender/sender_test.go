@@ -0,0 +1,342 @@
+package sender
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/app"
+	"github.com/anyproto/any-sync/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/anyproto/anytype-push-server/domain"
+	"github.com/anyproto/anytype-push-server/queue"
+	"github.com/anyproto/anytype-push-server/queue/mock_queue"
+	"github.com/anyproto/anytype-push-server/ratelimit"
+	"github.com/anyproto/anytype-push-server/ratelimit/mock_ratelimit"
+	"github.com/anyproto/anytype-push-server/repo/accountrepo"
+	"github.com/anyproto/anytype-push-server/repo/accountrepo/mock_accountrepo"
+	"github.com/anyproto/anytype-push-server/repo/spacerepo"
+	"github.com/anyproto/anytype-push-server/repo/spacerepo/mock_spacerepo"
+	"github.com/anyproto/anytype-push-server/repo/tokenrepo"
+	"github.com/anyproto/anytype-push-server/repo/tokenrepo/mock_tokenrepo"
+)
+
+var ctx = context.Background()
+
+func TestSender_SendMessage(t *testing.T) {
+	t.Run("peer", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.tokenRepo.EXPECT().GetActiveTokensByPeerId(ctx, "a1", "p1").Return([]domain.Token{
+			{Id: "t1", AccountId: "a1", PeerId: "p1", Platform: domain.PlatformIOS},
+		}, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{GroupId: "g", Silent: true, AccountId: "a1", PeerId: "p1"}))
+		require.Len(t, fx.ios.messages, 1)
+		assert.Equal(t, []string{"t1"}, fx.ios.messages[0].Tokens)
+		assert.Equal(t, "silent", fx.ios.messages[0].Data["x-any-type"])
+		assert.Equal(t, []string{"a1"}, fx.ios.messages[0].AccountIds)
+		assert.Empty(t, fx.android.messages)
+	})
+	t.Run("account per token", func(t *testing.T) {
+		fx := newFixture(t)
+		topic := newTopic("chat")
+		fx.expectSpaces(topic)
+		fx.accountRepo.EXPECT().GetAccountIdsByTopics(ctx, gomock.Any()).Return([]string{"a1", "a2"}, nil)
+		// the same device token is bound to both accounts
+		fx.tokenRepo.EXPECT().GetActiveTokensByAccountIds(ctx, []string{"a1", "a2"}).Return([]domain.Token{
+			{Id: "t1", AccountId: "a1", Platform: domain.PlatformAndroid},
+			{Id: "t2", AccountId: "a1", Platform: domain.PlatformAndroid},
+			{Id: "t1", AccountId: "a2", Platform: domain.PlatformAndroid},
+			{Id: "t3", AccountId: "a2", Platform: domain.PlatformIOS},
+		}, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{GroupId: "g", Topics: []domain.Topic{topic}}))
+		// one provider call for all the recipients
+		require.Len(t, fx.android.messages, 1)
+		assert.Equal(t, "normal", fx.android.messages[0].Data["x-any-type"])
+		assert.Equal(t, []string{"t1", "t2", "t1"}, fx.android.messages[0].Tokens)
+		assert.Equal(t, []string{"a1", "a1", "a2"}, fx.android.messages[0].AccountIds)
+		require.Len(t, fx.ios.messages, 1)
+		assert.Equal(t, []string{"a2"}, fx.ios.messages[0].AccountIds)
+	})
+	t.Run("provider error", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.android.err = errors.New("unavailable")
+		fx.tokenRepo.EXPECT().GetActiveTokensByPeerId(ctx, "a1", "p1").Return([]domain.Token{
+			{Id: "t1", AccountId: "a1", Platform: domain.PlatformAndroid, Status: domain.TokenStatusInvalid},
+			{Id: "t2", AccountId: "a1", Platform: domain.PlatformIOS},
+		}, nil)
+
+		// the message is not failed, so it isn't rejected after a partial delivery, and undelivered tokens are not restored
+		require.NoError(t, fx.SendMessage(queue.Message{Silent: true, AccountId: "a1", PeerId: "p1"}))
+		assert.Len(t, fx.ios.messages, 1)
+	})
+	t.Run("sandbox provider", func(t *testing.T) {
+		fx := newFixture(t)
+		sandbox := &testProvider{}
+		fx.RegisterSandboxProvider(domain.PlatformIOS, sandbox)
+		fx.tokenRepo.EXPECT().GetActiveTokensByPeerId(ctx, "a1", "p1").Return([]domain.Token{
+			{Id: "t1", AccountId: "a1", Platform: domain.PlatformIOS},
+			{Id: "t2", AccountId: "a1", Platform: domain.PlatformIOS, ApnsEnvironment: domain.ApnsEnvironmentSandbox},
+			{Id: "t3", AccountId: "a1", Platform: domain.PlatformAndroid, ApnsEnvironment: domain.ApnsEnvironmentSandbox},
+		}, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{Silent: true, AccountId: "a1", PeerId: "p1"}))
+		require.Len(t, fx.ios.messages, 1)
+		assert.Equal(t, []string{"t1"}, fx.ios.messages[0].Tokens)
+		require.Len(t, sandbox.messages, 1)
+		assert.Equal(t, []string{"t2"}, sandbox.messages[0].Tokens)
+		// no sandbox provider for android, the default one is used
+		require.Len(t, fx.android.messages, 1)
+		assert.Equal(t, []string{"t3"}, fx.android.messages[0].Tokens)
+	})
+	t.Run("wildcard subscribers", func(t *testing.T) {
+		fx := newFixture(t)
+		topic := newTopic("chat")
+		fx.expectSpaces(topic)
+		fx.accountRepo.EXPECT().GetAccountIdsByTopics(ctx, []domain.Topic{topic, topic.SpaceWildcard()}).Return(nil, nil)
+		fx.tokenRepo.EXPECT().GetActiveTokensByAccountIds(ctx, gomock.Any()).Return(nil, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{Topics: []domain.Topic{topic}}))
+	})
+	t.Run("silent message is not expanded", func(t *testing.T) {
+		fx := newFixture(t)
+		topic := newTopic("a1")
+		fx.spaceRepo.EXPECT().RemovedSince(ctx, []string{topic.SpaceKeyBase58()}, gomock.Any()).Return(nil, nil)
+		fx.accountRepo.EXPECT().GetAccountIdsByTopics(ctx, []domain.Topic{topic}).Return([]string{"a1"}, nil)
+		fx.tokenRepo.EXPECT().GetActiveTokensByAccountIds(ctx, []string{"a1"}).Return(nil, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{Topics: []domain.Topic{topic}, Silent: true}))
+	})
+	t.Run("ignored and blocking accounts", func(t *testing.T) {
+		fx := newFixture(t)
+		topic := newTopic("chat")
+		fx.expectSpaces(topic)
+		fx.accountRepo.EXPECT().GetAccountIdsByTopics(ctx, gomock.Any()).Return([]string{"a1", "a2", "sender"}, nil)
+		fx.accountRepo.EXPECT().GetAccountIdsBlocking(ctx, []string{"a1", "a2"}, "sender").Return([]string{"a2"}, nil)
+		fx.tokenRepo.EXPECT().GetActiveTokensByAccountIds(ctx, []string{"a1"}).Return(nil, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{
+			Topics:          []domain.Topic{topic},
+			IgnoreAccountId: "sender",
+			SenderAccountId: "sender",
+		}))
+	})
+	t.Run("fan-out is capped to the space budget", func(t *testing.T) {
+		fx := newFixture(t)
+		limited := newTopic("chat")
+		unlimited := newTopic("chat")
+		topics := []domain.Topic{limited, unlimited}
+		fx.spaceRepo.EXPECT().RemovedSince(ctx, domain.SpaceKeys(topics), gomock.Any()).Return(nil, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(ctx, domain.SpaceKeys(topics)).Return(map[string]domain.SpacePolicy{
+			limited.SpaceKeyBase58(): {MaxFanOutPerMinute: 10},
+		}, nil)
+		fx.accountRepo.EXPECT().GetAccountIdsByTopics(ctx, []domain.Topic{limited, limited.SpaceWildcard()}).
+			Return([]string{"a1", "sender", "a2", "a3", "a5"}, nil)
+		fx.accountRepo.EXPECT().GetAccountIdsBlocking(ctx, []string{"a1", "a2", "a3", "a5"}, "sender").Return([]string{"a3"}, nil)
+		// ignored and blocking accounts don't spend the budget
+		fx.rateLimit.EXPECT().AllowN(ctx,
+			ratelimit.Key{Scope: ratelimit.ScopeSpaceFanOut, Id: limited.SpaceKeyBase58()},
+			3,
+			ratelimit.PerMinute(10),
+		).Return(2, time.Second, nil)
+		fx.accountRepo.EXPECT().GetAccountIdsByTopics(ctx, []domain.Topic{unlimited, unlimited.SpaceWildcard()}).
+			Return([]string{"a4"}, nil)
+		fx.accountRepo.EXPECT().GetAccountIdsBlocking(ctx, []string{"a4"}, "sender").Return(nil, nil)
+		fx.tokenRepo.EXPECT().GetActiveTokensByAccountIds(ctx, gomock.Cond[[]string](func(accountIds []string) bool {
+			return assert.Len(t, accountIds, 3) &&
+				assert.Subset(t, []string{"a1", "a2", "a5", "a4"}, accountIds) &&
+				assert.Contains(t, accountIds, "a4")
+		})).Return(nil, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{Topics: topics, IgnoreAccountId: "sender", SenderAccountId: "sender"}))
+	})
+	t.Run("capped subscribers rotate", func(t *testing.T) {
+		fx := newFixture(t)
+		topic := newTopic("chat")
+		fx.spaceRepo.EXPECT().RemovedSince(ctx, gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		fx.spaceRepo.EXPECT().GetPolicies(ctx, gomock.Any()).Return(map[string]domain.SpacePolicy{
+			topic.SpaceKeyBase58(): {MaxFanOutPerMinute: 10},
+		}, nil).AnyTimes()
+		fx.accountRepo.EXPECT().GetAccountIdsByTopics(ctx, gomock.Any()).DoAndReturn(func(context.Context, []domain.Topic) ([]string, error) {
+			return []string{"a1", "a2", "a3"}, nil
+		}).AnyTimes()
+		fx.rateLimit.EXPECT().AllowN(ctx, gomock.Any(), 3, gomock.Any()).Return(1, time.Second, nil).AnyTimes()
+		var served = make(map[string]bool)
+		fx.tokenRepo.EXPECT().GetActiveTokensByAccountIds(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, accountIds []string) ([]domain.Token, error) {
+			require.Len(t, accountIds, 1)
+			served[accountIds[0]] = true
+			return nil, nil
+		}).AnyTimes()
+
+		for range 50 {
+			require.NoError(t, fx.SendMessage(queue.Message{Topics: []domain.Topic{topic}}))
+		}
+		assert.Len(t, served, 3)
+	})
+	t.Run("fan-out budget is spent", func(t *testing.T) {
+		fx := newFixture(t)
+		topic := newTopic("chat")
+		fx.spaceRepo.EXPECT().RemovedSince(ctx, []string{topic.SpaceKeyBase58()}, gomock.Any()).Return(nil, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(ctx, []string{topic.SpaceKeyBase58()}).Return(map[string]domain.SpacePolicy{
+			topic.SpaceKeyBase58(): {MaxFanOutPerMinute: 10},
+		}, nil)
+		fx.accountRepo.EXPECT().GetAccountIdsByTopics(ctx, gomock.Any()).Return([]string{"a1", "a2"}, nil)
+		fx.rateLimit.EXPECT().AllowN(ctx, gomock.Any(), 2, ratelimit.PerMinute(10)).Return(0, time.Second, nil)
+		fx.tokenRepo.EXPECT().GetActiveTokensByAccountIds(ctx, gomock.Len(0)).Return(nil, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{Topics: []domain.Topic{topic}}))
+		assert.Empty(t, fx.android.messages)
+	})
+	t.Run("removed spaces", func(t *testing.T) {
+		fx := newFixture(t)
+		removed := newTopic("chat")
+		kept := newTopic("chat")
+		created := time.Now()
+		fx.spaceRepo.EXPECT().RemovedSince(ctx, []string{removed.SpaceKeyBase58(), kept.SpaceKeyBase58()}, created).
+			Return([]string{removed.SpaceKeyBase58()}, nil)
+		fx.spaceRepo.EXPECT().GetPolicies(ctx, []string{kept.SpaceKeyBase58()}).Return(nil, nil)
+		fx.accountRepo.EXPECT().GetAccountIdsByTopics(ctx, []domain.Topic{kept, kept.SpaceWildcard()}).Return(nil, nil)
+		fx.tokenRepo.EXPECT().GetActiveTokensByAccountIds(ctx, gomock.Any()).Return(nil, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{Topics: []domain.Topic{removed, kept}, Created: created}))
+	})
+	t.Run("all spaces removed", func(t *testing.T) {
+		fx := newFixture(t)
+		topic := newTopic("chat")
+		fx.spaceRepo.EXPECT().RemovedSince(ctx, []string{topic.SpaceKeyBase58()}, gomock.Any()).
+			Return([]string{topic.SpaceKeyBase58()}, nil)
+
+		require.NoError(t, fx.SendMessage(queue.Message{Topics: []domain.Topic{topic}}))
+		assert.Empty(t, fx.android.messages)
+	})
+	t.Run("quarantined tokens are restored", func(t *testing.T) {
+		fx := newFixture(t)
+		fx.android.invalid = []string{"t3"}
+		fx.tokenRepo.EXPECT().GetActiveTokensByPeerId(ctx, "a1", "p1").Return([]domain.Token{
+			{Id: "t1", AccountId: "a1", Platform: domain.PlatformAndroid},
+			{Id: "t2", AccountId: "a1", Platform: domain.PlatformAndroid, Status: domain.TokenStatusInvalid},
+			{Id: "t3", AccountId: "a1", Platform: domain.PlatformAndroid, Status: domain.TokenStatusInvalid},
+		}, nil)
+		// t3 is reported again, so it stays in quarantine
+		fx.tokenRepo.EXPECT().RestoreTokens(ctx, []string{"t2"}).Return(nil)
+		fx.tokenRepo.EXPECT().QuarantineTokens(gomock.Any(), []string{"t3"}, domain.TokenInvalidReasonUnregistered).AnyTimes()
+
+		require.NoError(t, fx.SendMessage(queue.Message{Silent: true, AccountId: "a1", PeerId: "p1"}))
+	})
+}
+
+type testProvider struct {
+	messages []domain.Message
+	invalid  []string
+	err      error
+}
+
+func (p *testProvider) SendMessage(ctx context.Context, message domain.Message, onInvalid func(token string, reason domain.TokenInvalidReason)) (err error) {
+	p.messages = append(p.messages, message)
+	for _, token := range message.Tokens {
+		if slices.Contains(p.invalid, token) {
+			onInvalid(token, domain.TokenInvalidReasonUnregistered)
+		}
+	}
+	return p.err
+}
+
+func (p *testProvider) ValidateToken(ctx context.Context, token string) (err error) {
+	return nil
+}
+
+type fixture struct {
+	*sender
+	accountRepo *mock_accountrepo.MockAccountRepo
+	spaceRepo   *mock_spacerepo.MockSpaceRepo
+	tokenRepo   *mock_tokenrepo.MockTokenRepo
+	queue       *mock_queue.MockQueue
+	rateLimit   *mock_ratelimit.MockRateLimit
+	ios         *testProvider
+	android     *testProvider
+	a           *app.App
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctrl := gomock.NewController(t)
+	fx := &fixture{
+		sender:      New().(*sender),
+		accountRepo: mock_accountrepo.NewMockAccountRepo(ctrl),
+		spaceRepo:   mock_spacerepo.NewMockSpaceRepo(ctrl),
+		tokenRepo:   mock_tokenrepo.NewMockTokenRepo(ctrl),
+		queue:       mock_queue.NewMockQueue(ctrl),
+		rateLimit:   mock_ratelimit.NewMockRateLimit(ctrl),
+		ios:         &testProvider{},
+		android:     &testProvider{},
+		a:           new(app.App),
+	}
+	fx.accountRepo.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.accountRepo.EXPECT().Name().Return(accountrepo.CName).AnyTimes()
+	fx.accountRepo.EXPECT().Run(gomock.Any()).AnyTimes()
+	fx.accountRepo.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.spaceRepo.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.spaceRepo.EXPECT().Name().Return(spacerepo.CName).AnyTimes()
+	fx.spaceRepo.EXPECT().Run(gomock.Any()).AnyTimes()
+	fx.spaceRepo.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.tokenRepo.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.tokenRepo.EXPECT().Name().Return(tokenrepo.CName).AnyTimes()
+	fx.tokenRepo.EXPECT().Run(gomock.Any()).AnyTimes()
+	fx.tokenRepo.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.queue.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.queue.EXPECT().Name().Return(queue.CName).AnyTimes()
+	fx.queue.EXPECT().Run(gomock.Any()).AnyTimes()
+	fx.queue.EXPECT().Close(gomock.Any()).AnyTimes()
+	fx.queue.EXPECT().Consume(gomock.Any(), gomock.Any()).AnyTimes()
+	fx.rateLimit.EXPECT().Init(gomock.Any()).AnyTimes()
+	fx.rateLimit.EXPECT().Name().Return(ratelimit.CName).AnyTimes()
+
+	fx.a.Register(fx.accountRepo).
+		Register(fx.spaceRepo).
+		Register(fx.tokenRepo).
+		Register(fx.queue).
+		Register(fx.rateLimit).
+		Register(metric.New()).
+		Register(&testConfig{}).
+		Register(fx.sender)
+	require.NoError(t, fx.a.Start(ctx))
+	fx.RegisterProvider(domain.PlatformIOS, fx.ios)
+	fx.RegisterProvider(domain.PlatformAndroid, fx.android)
+	t.Cleanup(func() {
+		require.NoError(t, fx.a.Close(ctx))
+		ctrl.Finish()
+	})
+	return fx
+}
+
+// expectSpaces expects the space checks of a message to registered spaces without policies
+func (fx *fixture) expectSpaces(topics ...domain.Topic) {
+	fx.spaceRepo.EXPECT().RemovedSince(ctx, domain.SpaceKeys(topics), gomock.Any()).Return(nil, nil)
+	fx.spaceRepo.EXPECT().GetPolicies(ctx, domain.SpaceKeys(topics)).Return(nil, nil)
+}
+
+func newTopic(topic string) domain.Topic {
+	var spaceKey = make([]byte, 32)
+	_, _ = rand.Read(spaceKey)
+	return domain.NewTopic(spaceKey, topic)
+}
+
+type testConfig struct{}
+
+func (t testConfig) Init(a *app.App) (err error) {
+	return
+}
+
+func (t testConfig) Name() (name string) {
+	return "config"
+}
+
+func (t testConfig) GetMetric() metric.Config {
+	return metric.Config{}
+}